@@ -0,0 +1,82 @@
+// Command backtest-predictions scores the predictions of the finished matches of the storage against their results
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/pkg/conn"
+	"github.com/yezarela/go-soccer/pkg/elo"
+	"github.com/yezarela/go-soccer/pkg/predict"
+)
+
+func main() {
+	warmup := flag.Int("warmup", 50, "minimum number of the first matches only used to fit the model")
+	last := flag.Int("matches", 2000, "maximum number of the last matches predicted, raising the warmup, 0 for all")
+	timeout := flag.Duration("timeout", 5*time.Minute, "timeout of the backtest")
+	flag.Parse()
+
+	// The environment may be set without .env
+	_ = godotenv.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var matchRepo match.Repository
+
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "mongodb":
+		c, err := conn.NewMongoDBConnection(ctx, os.Getenv("MONGODB_URI"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer c.Disconnect(ctx)
+
+		matchRepo = match.NewRepository(c.Database(os.Getenv("MONGODB_DBNAME")))
+
+	case "bolt":
+		db, err := conn.NewBoltDBConnection(os.Getenv("BOLTDB_PATH"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer db.Close()
+
+		matchRepo = match.NewBoltRepository(db)
+
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %s, please use mongodb or bolt", driver)
+	}
+
+	matches, err := match.ListAll(ctx, matchRepo, "", url.Values{"status": {model.MatchStatusFinished}})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	samples := predict.Samples(matches, elo.Rate(matches, elo.Default))
+
+	// Each prediction refits the model on the matches before it, the earlier ones are only used to fit
+	skip := *warmup
+	if *last > 0 && len(samples)-*last > skip {
+		skip = len(samples) - *last
+		log.Printf("Raised the warmup from %d to %d matches to predict the last %d only", *warmup, skip, *last)
+	}
+
+	res := predict.Backtest(samples, skip)
+	if res.Matches == 0 {
+		log.Fatalf("Found %d finished matches, please lower the warmup of %d", len(samples), *warmup)
+	}
+
+	log.Printf("Predicted %d of %d finished matches", res.Matches, len(samples))
+	log.Printf("Brier score %.4f, %.4f by the frequencies of the outcomes", res.Brier, res.Baseline)
+
+	m := predict.Fit(samples)
+	log.Printf("Fitted %.2f home goals and %.2f away goals on average, rating coefficient %.3f, form coefficient %.3f",
+		m.HomeGoals, m.AwayGoals, m.Rating, m.Form)
+}
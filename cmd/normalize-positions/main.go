@@ -0,0 +1,73 @@
+// Command normalize-positions rewrites the stored positions of the players to the codes of the taxonomy
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/yezarela/go-soccer/module/player"
+	"github.com/yezarela/go-soccer/pkg/conn"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report the players to update without updating them")
+	timeout := flag.Duration("timeout", 5*time.Minute, "timeout of the migration")
+	flag.Parse()
+
+	// The environment may be set without .env
+	_ = godotenv.Load()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var playerRepo player.Repository
+
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "mongodb":
+		c, err := conn.NewMongoDBConnection(ctx, os.Getenv("MONGODB_URI"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer c.Disconnect(ctx)
+
+		playerRepo = player.NewRepository(c.Database(os.Getenv("MONGODB_DBNAME")))
+
+	case "bolt":
+		db, err := conn.NewBoltDBConnection(os.Getenv("BOLTDB_PATH"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer db.Close()
+
+		playerRepo = player.NewBoltRepository(db)
+
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %s, please use mongodb or bolt", driver)
+	}
+
+	res, err := player.NormalizePositions(ctx, playerRepo, *dryRun)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ids := []string{}
+	for id := range res.Unknown {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		log.Printf("Player %s has unknown positions %v, please fix them by hand", id, res.Unknown[id])
+	}
+
+	if *dryRun {
+		log.Printf("Scanned %d players, %d would be updated", res.Scanned, res.Updated)
+		return
+	}
+	log.Printf("Scanned %d players, %d updated", res.Scanned, res.Updated)
+}
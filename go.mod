@@ -8,6 +8,9 @@ require (
 	github.com/labstack/echo/v4 v4.1.17
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.4.0
+	go.etcd.io/bbolt v1.3.5
 	go.mongodb.org/mongo-driver v1.4.1
+	golang.org/x/net v0.0.0-20200822124328-c89045814202
+	golang.org/x/text v0.3.3
 	gopkg.in/yaml.v2 v2.2.8 // indirect
 )
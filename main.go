@@ -9,9 +9,20 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/yezarela/go-soccer/module/bracket"
+	"github.com/yezarela/go-soccer/module/competition"
+	"github.com/yezarela/go-soccer/module/fixture"
+	"github.com/yezarela/go-soccer/module/live"
+	"github.com/yezarela/go-soccer/module/match"
 	"github.com/yezarela/go-soccer/module/player"
+	"github.com/yezarela/go-soccer/module/rating"
+	"github.com/yezarela/go-soccer/module/search"
+	"github.com/yezarela/go-soccer/module/standings"
+	"github.com/yezarela/go-soccer/module/stats"
 	"github.com/yezarela/go-soccer/module/team"
 	"github.com/yezarela/go-soccer/pkg/conn"
+	"github.com/yezarela/go-soccer/pkg/hub"
+	"github.com/yezarela/go-soccer/pkg/memdb"
 )
 
 func init() {
@@ -23,16 +34,85 @@ func init() {
 }
 
 func main() {
-	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	// Connect to MongoDB cluster
-	c, err := conn.NewMongoDBConnection(ctx, os.Getenv("MONGODB_URI"))
-	if err != nil {
+	// Repositories
+	var teamRepo team.Repository
+	var playerRepo player.Repository
+	var matchRepo match.Repository
+	var competitionRepo competition.Repository
+	var statsRepo stats.Repository
+
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "mongodb":
+		// Connect to MongoDB cluster
+		c, err := conn.NewMongoDBConnection(ctx, os.Getenv("MONGODB_URI"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer c.Disconnect(ctx)
+
+		db := c.Database(os.Getenv("MONGODB_DBNAME"))
+
+		teamRepo = team.NewRepository(db)
+		playerRepo = player.NewRepository(db)
+		matchRepo = match.NewRepository(db)
+		competitionRepo = competition.NewRepository(db)
+		statsRepo = stats.NewRepository(db)
+
+	case "bolt":
+		// Open the boltdb file and migrate its schema
+		db, err := conn.NewBoltDBConnection(os.Getenv("BOLTDB_PATH"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer db.Close()
+
+		teamRepo = team.NewBoltRepository(db)
+		playerRepo = player.NewBoltRepository(db)
+		matchRepo = match.NewBoltRepository(db)
+		competitionRepo = competition.NewBoltRepository(db)
+		statsRepo = stats.NewBoltRepository(db)
+
+	case "memory":
+		db := memdb.New()
+
+		teamRepo = team.NewMemoryRepository(db)
+		playerRepo = player.NewMemoryRepository(db)
+		matchRepo = match.NewMemoryRepository(db)
+		competitionRepo = competition.NewMemoryRepository(db)
+		statsRepo = stats.NewMemoryRepository(db)
+
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %s, please use mongodb, bolt or memory", driver)
+	}
+
+	// Indexes
+	if err := teamRepo.EnsureIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := playerRepo.EnsureIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := matchRepo.EnsureIndexes(ctx); err != nil {
 		log.Fatal(err)
 	}
-	defer c.Disconnect(ctx)
+	if err := competitionRepo.EnsureIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+	if err := statsRepo.EnsureIndexes(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+	e := newServer(teamRepo, playerRepo, matchRepo, competitionRepo, statsRepo)
+
+	// Start server
+	e.Logger.Fatal(e.Start(":1323"))
+}
 
-	db := c.Database(os.Getenv("MONGODB_DBNAME"))
+// newServer creates the Echo instance with the routes of the app
+func newServer(teamRepo team.Repository, playerRepo player.Repository, matchRepo match.Repository, competitionRepo competition.Repository, statsRepo stats.Repository) *echo.Echo {
 
 	// Create Echo instance
 	e := echo.New()
@@ -41,14 +121,29 @@ func main() {
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 
-	// Repositories
-	teamRepo := team.NewRepository(db)
-	playerRepo := player.NewRepository(db)
-
 	// Route & handlers
 	team.NewHandler(e, teamRepo)
 	player.NewHandler(e, playerRepo)
+	search.NewHandler(e, teamRepo, playerRepo)
+	competition.NewHandler(e, competitionRepo, teamRepo)
 
-	// Start server
-	e.Logger.Fatal(e.Start(":1323"))
+	// The standings are cached until a match of their competition changes
+	cache := standings.NewCache()
+	standings.NewHandler(e, competitionRepo, matchRepo, cache)
+
+	// The changes of the matches are pushed to their live clients, the last 256 updates of a match
+	// are kept to replay them and a client more than 64 updates behind is dropped
+	tracker := live.NewTracker(hub.New(256, 64))
+	live.NewHandler(e, matchRepo, tracker)
+
+	// The ratings are cached until any match changes
+	ratings := rating.NewCache()
+	rating.NewHandler(e, teamRepo, matchRepo, ratings)
+
+	match.NewHandler(e, matchRepo, teamRepo, competitionRepo, cache, tracker, ratings)
+	fixture.NewHandler(e, competitionRepo, matchRepo, cache)
+	bracket.NewHandler(e, competitionRepo, matchRepo)
+	stats.NewHandler(e, statsRepo, playerRepo, teamRepo, matchRepo, competitionRepo)
+
+	return e
 }
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/competition"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/module/player"
+	"github.com/yezarela/go-soccer/module/stats"
+	"github.com/yezarela/go-soccer/module/team"
+	"github.com/yezarela/go-soccer/pkg/conn"
+	"github.com/yezarela/go-soccer/pkg/hub"
+	"github.com/yezarela/go-soccer/pkg/memdb"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/net/websocket"
+)
+
+// response represents an api response with its data left encoded
+type response struct {
+	Meta struct {
+		Code int `json:"code"`
+	} `json:"meta"`
+	Data       json.RawMessage `json:"data"`
+	Pagination *query.Page     `json:"pagination"`
+}
+
+func do(t *testing.T, srv *httptest.Server, method, path, body string, data interface{}) response {
+
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	var r response
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&r))
+
+	if data != nil {
+		require.NoError(t, json.Unmarshal(r.Data, data))
+	}
+
+	return r
+}
+
+func TestEndToEnd(t *testing.T) {
+
+	t.Run("Memory", func(t *testing.T) {
+		db := memdb.New()
+		testEndToEnd(t, team.NewMemoryRepository(db), player.NewMemoryRepository(db), match.NewMemoryRepository(db), competition.NewMemoryRepository(db), stats.NewMemoryRepository(db))
+	})
+
+	t.Run("Bolt", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "go-soccer")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		db, err := conn.NewBoltDBConnection(filepath.Join(dir, "soccer.db"))
+		require.NoError(t, err)
+		defer db.Close()
+
+		testEndToEnd(t, team.NewBoltRepository(db), player.NewBoltRepository(db), match.NewBoltRepository(db), competition.NewBoltRepository(db), stats.NewBoltRepository(db))
+	})
+}
+
+func testEndToEnd(t *testing.T, teamRepo team.Repository, playerRepo player.Repository, matchRepo match.Repository, competitionRepo competition.Repository, statsRepo stats.Repository) {
+
+	srv := httptest.NewServer(newServer(teamRepo, playerRepo, matchRepo, competitionRepo, statsRepo))
+	defer srv.Close()
+
+	// Create a team with its players
+	var milan model.Team
+	r := do(t, srv, http.MethodPost, "/teams", `{"name":"AC Milan","location":"Italy","players":[{"name":"Thomas Müller","position":"forward"},{"name":"John Doe","position":"keeper"}]}`, &milan)
+	assert.Equal(t, http.StatusCreated, r.Meta.Code)
+	require.Len(t, milan.Players, 2)
+
+	var inter model.Team
+	r = do(t, srv, http.MethodPost, "/teams", `{"name":"Inter","location":"Italy"}`, &inter)
+	assert.Equal(t, http.StatusCreated, r.Meta.Code)
+
+	// List with pagination
+	var teams []model.Team
+	r = do(t, srv, http.MethodGet, "/teams?limit=1", "", &teams)
+	assert.Equal(t, http.StatusOK, r.Meta.Code)
+	assert.Len(t, teams, 1)
+	assert.Equal(t, milan.ID, teams[0].ID)
+	require.True(t, r.Pagination.HasMore)
+
+	r = do(t, srv, http.MethodGet, "/teams?limit=1&cursor="+r.Pagination.NextCursor, "", &teams)
+	assert.Len(t, teams, 1)
+	assert.Equal(t, inter.ID, teams[0].ID)
+	assert.False(t, r.Pagination.HasMore)
+
+	// Filter players, the positions are stored as codes
+	var players []model.Player
+	do(t, srv, http.MethodGet, "/players?position=ST", "", &players)
+	assert.Len(t, players, 1)
+
+	// Search ignores accents
+	var results []model.SearchResult
+	do(t, srv, http.MethodGet, "/search?q=muller", "", &results)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, model.SearchTypePlayer, results[0].Type)
+	}
+
+	// Transfer a player
+	muller := milan.Players[0]
+	r = do(t, srv, http.MethodPost, "/teams/"+milan.ID.Hex()+"/players/"+muller.ID.Hex()+"/transfer", `{"team_id":"`+inter.ID.Hex()+`"}`, &inter)
+	assert.Equal(t, http.StatusOK, r.Meta.Code)
+	assert.Len(t, inter.Players, 1)
+
+	// A player belongs to one team only
+	r = do(t, srv, http.MethodPost, "/teams/"+milan.ID.Hex()+"/players", `{"players":["`+muller.ID.Hex()+`"]}`, nil)
+	assert.Equal(t, http.StatusConflict, r.Meta.Code)
+
+	// Deleting a player removes it from its team
+	r = do(t, srv, http.MethodDelete, "/players/"+muller.ID.Hex(), "", nil)
+	assert.Equal(t, http.StatusOK, r.Meta.Code)
+
+	do(t, srv, http.MethodGet, "/teams/"+inter.ID.Hex(), "", &inter)
+	assert.Empty(t, inter.Players)
+
+	// Patch a team
+	r = do(t, srv, http.MethodPatch, "/teams/"+milan.ID.Hex(), `{"description":"Rossoneri"}`, &milan)
+	assert.Equal(t, http.StatusOK, r.Meta.Code)
+	assert.Equal(t, "Rossoneri", milan.Description)
+	assert.Equal(t, "AC Milan", milan.Name)
+
+	// Schedule a match between existing teams
+	var derby model.Match
+	r = do(t, srv, http.MethodPost, "/matches", `{"home_team_id":"`+milan.ID.Hex()+`","away_team_id":"`+inter.ID.Hex()+`","kickoff":"2020-10-17T18:45:00+02:00","venue":"San Siro"}`, &derby)
+	assert.Equal(t, http.StatusCreated, r.Meta.Code)
+	assert.Equal(t, model.MatchStatusScheduled, derby.Status)
+
+	r = do(t, srv, http.MethodPost, "/matches", `{"home_team_id":"`+milan.ID.Hex()+`","away_team_id":"5f6a5d6129b2289c40b7444b","kickoff":"2020-10-17T18:45:00Z"}`, nil)
+	assert.Equal(t, http.StatusNotFound, r.Meta.Code)
+
+	// A lineup has a full starting XI of the players of the team, only the keeper is left in milan after the transfer
+	keeper := milan.Players[0]
+	r = do(t, srv, http.MethodPut, "/matches/"+derby.ID.Hex()+"/lineups", `{"home":{"formation":"4-3-3","starters":[{"player_id":"`+keeper.ID.Hex()+`","shirt_number":1}]}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, r.Meta.Code)
+
+	var lineups model.Lineups
+	r = do(t, srv, http.MethodGet, "/matches/"+derby.ID.Hex()+"/lineups", "", &lineups)
+	assert.Equal(t, http.StatusOK, r.Meta.Code)
+	assert.Nil(t, lineups.Home)
+
+	// List the matches of a team in a date range
+	var matches []model.Match
+	do(t, srv, http.MethodGet, "/matches?team_id="+inter.ID.Hex()+"&kickoff[gte]=2020-10-17&kickoff[lt]=2020-10-18", "", &matches)
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, derby.ID, matches[0].ID)
+	}
+
+	do(t, srv, http.MethodGet, "/matches?kickoff[gte]=2020-10-18", "", &matches)
+	assert.Empty(t, matches)
+
+	// Record the events, the score is derived from the goals
+	r = do(t, srv, http.MethodPost, "/matches/"+derby.ID.Hex()+"/events", `{"type":"goal","minute":10,"player_id":"`+muller.ID.Hex()+`"}`, nil)
+	assert.Equal(t, http.StatusConflict, r.Meta.Code)
+
+	r = do(t, srv, http.MethodPatch, "/matches/"+derby.ID.Hex(), `{"status":"live"}`, &derby)
+	assert.Equal(t, http.StatusOK, r.Meta.Code)
+	assert.Equal(t, "San Siro", derby.Venue)
+
+	r = do(t, srv, http.MethodPost, "/matches/"+derby.ID.Hex()+"/events", `{"type":"own_goal","minute":90,"added_time":3,"player_id":"`+keeper.ID.Hex()+`"}`, &derby)
+	assert.Equal(t, http.StatusCreated, r.Meta.Code)
+	assert.Equal(t, model.Score{Home: 0, Away: 1}, derby.Score)
+
+	// The deleted player does not play for the teams anymore
+	r = do(t, srv, http.MethodPost, "/matches/"+derby.ID.Hex()+"/events", `{"type":"goal","minute":10,"player_id":"`+muller.ID.Hex()+`"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, r.Meta.Code)
+
+	var events []model.MatchEvent
+	do(t, srv, http.MethodGet, "/matches/"+derby.ID.Hex()+"/events", "", &events)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, milan.ID, *events[0].TeamID)
+	}
+
+	// Create a competition, its table starts empty
+	var serieA model.Competition
+	r = do(t, srv, http.MethodPost, "/competitions", `{"name":"Serie A","season":"2020-21","team_ids":["`+milan.ID.Hex()+`","`+inter.ID.Hex()+`"]}`, &serieA)
+	assert.Equal(t, http.StatusCreated, r.Meta.Code)
+	assert.Equal(t, model.Points{Win: 3, Draw: 1, Loss: 0}, serieA.Points)
+
+	var standings []model.Standing
+	do(t, srv, http.MethodGet, "/competitions/"+serieA.ID.Hex()+"/standings", "", &standings)
+	if assert.Len(t, standings, 2) {
+		assert.Equal(t, milan.ID, standings[0].TeamID)
+		assert.Equal(t, 0, standings[0].Played)
+	}
+
+	// Finishing a match of the competition updates the cached table
+	var game model.Match
+	r = do(t, srv, http.MethodPost, "/matches", `{"competition_id":"`+serieA.ID.Hex()+`","home_team_id":"`+milan.ID.Hex()+`","away_team_id":"`+inter.ID.Hex()+`","kickoff":"2020-10-24T18:45:00Z","status":"live"}`, &game)
+	assert.Equal(t, http.StatusCreated, r.Meta.Code)
+
+	// The live clients are pushed the events and the score as they are recorded
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/matches/"+game.ID.Hex()+"/live/ws", "", srv.URL)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	var update hub.Message
+	require.NoError(t, websocket.JSON.Receive(ws, &update))
+	assert.Equal(t, model.LiveMatch, update.Type)
+
+	do(t, srv, http.MethodPost, "/matches/"+game.ID.Hex()+"/events", `{"type":"own_goal","minute":12,"player_id":"`+keeper.ID.Hex()+`"}`, nil)
+
+	for _, typ := range []string{model.LiveEvent, model.LiveScore} {
+		require.NoError(t, websocket.JSON.Receive(ws, &update))
+		assert.Equal(t, typ, update.Type)
+	}
+	do(t, srv, http.MethodPatch, "/matches/"+game.ID.Hex(), `{"status":"finished"}`, nil)
+
+	do(t, srv, http.MethodGet, "/competitions/"+serieA.ID.Hex()+"/standings", "", &standings)
+	if assert.Len(t, standings, 2) {
+		assert.Equal(t, inter.ID, standings[0].TeamID)
+		assert.Equal(t, 3, standings[0].Points)
+		assert.Equal(t, -1, standings[1].GoalDifference)
+	}
+
+	// Only the finished matches count in the stats of the players, an own goal is not a goal
+	var keeperStats model.PlayerStats
+	do(t, srv, http.MethodGet, "/players/"+keeper.ID.Hex()+"/stats", "", &keeperStats)
+	assert.Equal(t, model.PlayerTotals{Appearances: 1, Minutes: 90, OwnGoals: 1}, keeperStats.Total)
+	if assert.Len(t, keeperStats.Competitions, 1) {
+		assert.Equal(t, "2020-21", keeperStats.Competitions[0].Season)
+	}
+
+	var scorers []model.Scorer
+	r = do(t, srv, http.MethodGet, "/competitions/"+serieA.ID.Hex()+"/top-scorers", "", &scorers)
+	assert.Equal(t, http.StatusOK, r.Meta.Code)
+	assert.Empty(t, scorers)
+
+	var interStats model.TeamStats
+	do(t, srv, http.MethodGet, "/teams/"+inter.ID.Hex()+"/stats", "", &interStats)
+	assert.Equal(t, "W", interStats.Form)
+	assert.Equal(t, 1, interStats.Away.Won)
+	assert.Equal(t, 1, interStats.Total.CleanSheets)
+
+	var h2h model.HeadToHead
+	do(t, srv, http.MethodGet, "/teams/"+milan.ID.Hex()+"/head-to-head/"+inter.ID.Hex(), "", &h2h)
+	assert.Equal(t, 1, h2h.Played)
+	assert.Equal(t, "L", h2h.Form)
+	assert.Equal(t, 1, h2h.Other.Won)
+
+	r = do(t, srv, http.MethodGet, "/teams/"+milan.ID.Hex()+"/head-to-head/"+milan.ID.Hex(), "", nil)
+	assert.Equal(t, http.StatusBadRequest, r.Meta.Code)
+
+	// The away win is worth more than the stake of an even match
+	var interRating model.Rating
+	do(t, srv, http.MethodGet, "/teams/"+inter.ID.Hex()+"/rating", "", &interRating)
+	assert.Equal(t, 1, interRating.Played)
+	assert.True(t, interRating.Rating > 1510)
+	assert.Len(t, interRating.History, 1)
+
+	var rankings []model.Ranking
+	do(t, srv, http.MethodGet, "/rankings?limit=1", "", &rankings)
+	if assert.Len(t, rankings, 1) {
+		assert.Equal(t, inter.ID, rankings[0].TeamID)
+		assert.Equal(t, 1, rankings[0].Position)
+	}
+
+	// Generate the fixtures of a double round-robin
+	var friendly model.Competition
+	do(t, srv, http.MethodPost, "/competitions", `{"name":"Trofeo","team_ids":["`+milan.ID.Hex()+`","`+inter.ID.Hex()+`"]}`, &friendly)
+
+	var fixtures []model.Match
+	r = do(t, srv, http.MethodPost, "/competitions/"+friendly.ID.Hex()+"/fixtures:generate", `{"start":"2020-10-24T18:45:00Z","double_round_robin":true,"rest_days":3}`, &fixtures)
+	assert.Equal(t, http.StatusCreated, r.Meta.Code)
+	if assert.Len(t, fixtures, 2) {
+		// Both teams already play the league match on the start date
+		assert.Equal(t, "2020-10-28T18:45:00Z", fixtures[0].Kickoff.Format(time.RFC3339))
+		assert.Equal(t, fixtures[0].HomeTeamID, fixtures[1].AwayTeamID)
+	}
+
+	// The teams of a fixture are predicted from their ratings and forms
+	if len(fixtures) > 0 {
+		var prediction model.Prediction
+		r = do(t, srv, http.MethodGet, "/matches/"+fixtures[0].ID.Hex()+"/prediction", "", &prediction)
+		assert.Equal(t, http.StatusOK, r.Meta.Code)
+		assert.Equal(t, fixtures[0].HomeTeamID, prediction.Home.TeamID)
+		assert.Equal(t, 1, len(prediction.Away.Form))
+		assert.InDelta(t, 1, prediction.HomeWin+prediction.Draw+prediction.AwayWin, 0.002)
+	}
+
+	r = do(t, srv, http.MethodPost, "/competitions/"+friendly.ID.Hex()+"/fixtures:generate", `{"start":"2020-10-24T18:45:00Z"}`, nil)
+	assert.Equal(t, http.StatusConflict, r.Meta.Code)
+
+	// A knockout final between the two teams
+	var cup model.Competition
+	do(t, srv, http.MethodPost, "/competitions", `{"name":"Coppa Italia","type":"knockout","team_ids":["`+milan.ID.Hex()+`","`+inter.ID.Hex()+`"]}`, &cup)
+
+	var final model.Match
+	r = do(t, srv, http.MethodPost, "/matches", `{"competition_id":"`+cup.ID.Hex()+`","home_team_id":"`+inter.ID.Hex()+`","away_team_id":"`+milan.ID.Hex()+`","kickoff":"2021-05-19T19:00:00Z","status":"live"}`, &final)
+	assert.Equal(t, http.StatusCreated, r.Meta.Code)
+
+	do(t, srv, http.MethodPatch, "/matches/"+final.ID.Hex(), `{"status":"finished","penalties":{"home":3,"away":4}}`, nil)
+
+	var tie model.Tie
+	r = do(t, srv, http.MethodGet, "/competitions/"+cup.ID.Hex()+"/bracket", "", &tie)
+	assert.Equal(t, http.StatusOK, r.Meta.Code)
+	assert.Equal(t, model.DecidedByPenalties, tie.DecidedBy)
+	if assert.NotNil(t, tie.WinnerID) {
+		assert.Equal(t, milan.ID, *tie.WinnerID)
+	}
+
+	// The tie is over
+	r = do(t, srv, http.MethodPost, "/matches", `{"competition_id":"`+cup.ID.Hex()+`","home_team_id":"`+milan.ID.Hex()+`","away_team_id":"`+inter.ID.Hex()+`","kickoff":"2021-05-26T19:00:00Z"}`, nil)
+	assert.Equal(t, http.StatusBadRequest, r.Meta.Code)
+
+	// A group stage whose two teams meet again in the final
+	var euro model.Competition
+	r = do(t, srv, http.MethodPost, "/competitions", `{"name":"Euro","type":"groups","group_stage":{"advance":2,"groups":[{"name":"A","team_ids":["`+milan.ID.Hex()+`","`+inter.ID.Hex()+`"]}]}}`, &euro)
+	assert.Equal(t, http.StatusCreated, r.Meta.Code)
+	assert.Equal(t, []primitive.ObjectID{milan.ID, inter.ID}, euro.TeamIDs)
+
+	r = do(t, srv, http.MethodPost, "/competitions/"+euro.ID.Hex()+"/fixtures:generate", `{"start":"2021-06-11T19:00:00Z"}`, &fixtures)
+	assert.Equal(t, http.StatusCreated, r.Meta.Code)
+	require.Len(t, fixtures, 1)
+
+	group := fixtures[0]
+	do(t, srv, http.MethodPatch, "/matches/"+group.ID.Hex(), `{"status":"live"}`, nil)
+	do(t, srv, http.MethodPost, "/matches/"+group.ID.Hex()+"/events", `{"type":"own_goal","minute":30,"player_id":"`+keeper.ID.Hex()+`"}`, nil)
+	do(t, srv, http.MethodPatch, "/matches/"+group.ID.Hex(), `{"status":"finished"}`, nil)
+
+	var groupStandings model.GroupStandings
+	do(t, srv, http.MethodGet, "/competitions/"+euro.ID.Hex()+"/standings", "", &groupStandings)
+	if assert.Len(t, groupStandings.Groups, 1) {
+		assert.True(t, groupStandings.Groups[0].Complete)
+		assert.Equal(t, inter.ID, groupStandings.Groups[0].Standings[0].TeamID)
+	}
+
+	do(t, srv, http.MethodGet, "/competitions/"+euro.ID.Hex()+"/bracket", "", &tie)
+	assert.Equal(t, "A1", tie.Slots[0].Label)
+	if assert.NotNil(t, tie.Slots[0].TeamID) {
+		assert.Equal(t, inter.ID, *tie.Slots[0].TeamID)
+	}
+
+	r = do(t, srv, http.MethodPost, "/matches", `{"competition_id":"`+euro.ID.Hex()+`","home_team_id":"`+inter.ID.Hex()+`","away_team_id":"`+milan.ID.Hex()+`","kickoff":"2021-07-11T19:00:00Z"}`, nil)
+	assert.Equal(t, http.StatusCreated, r.Meta.Code)
+}
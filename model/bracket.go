@@ -0,0 +1,36 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+const (
+	// DecidedByBye is a tie won without playing, against a bye
+	DecidedByBye = "bye"
+	// DecidedByAggregate is a tie won by the goals of all its legs
+	DecidedByAggregate = "aggregate"
+	// DecidedByAwayGoals is a tie level on aggregate won by the goals scored away
+	DecidedByAwayGoals = "away_goals"
+	// DecidedByPenalties is a tie won by the shootout of its last leg
+	DecidedByPenalties = "penalties"
+)
+
+// TieSlot represents a side of a knockout tie, its team is nil until known or for a bye
+type TieSlot struct {
+	TeamID    *primitive.ObjectID `json:"team_id"`
+	Seed      int                 `json:"seed,omitempty"`
+	Label     string              `json:"label,omitempty"`
+	Aggregate int                 `json:"aggregate"`
+	AwayGoals int                 `json:"away_goals"`
+	Penalties *int                `json:"penalties,omitempty"`
+}
+
+// Tie represents a tie of a knockout bracket played by the winners of its children
+type Tie struct {
+	Round     int                  `json:"round"`
+	Name      string               `json:"name"`
+	Legs      int                  `json:"legs"`
+	Slots     [2]TieSlot           `json:"slots"`
+	MatchIDs  []primitive.ObjectID `json:"match_ids"`
+	WinnerID  *primitive.ObjectID  `json:"winner_id,omitempty"`
+	DecidedBy string               `json:"decided_by,omitempty"`
+	Children  []*Tie               `json:"children,omitempty"`
+}
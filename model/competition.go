@@ -0,0 +1,123 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// CompetitionTypeLeague is a round-robin ranked by a table
+	CompetitionTypeLeague = "league"
+	// CompetitionTypeKnockout is a cup of ties, the winner of a tie advances to the next round
+	CompetitionTypeKnockout = "knockout"
+	// CompetitionTypeGroups is a stage of round-robin groups, the best teams of each group advance to a knockout
+	CompetitionTypeGroups = "groups"
+)
+
+// CompetitionTypes are the valid types of a competition
+var CompetitionTypes = []string{
+	CompetitionTypeLeague,
+	CompetitionTypeKnockout,
+	CompetitionTypeGroups,
+}
+
+const (
+	// TiebreakerGoalDifference ranks tied teams by goals scored minus goals conceded
+	TiebreakerGoalDifference = "goal_difference"
+	// TiebreakerGoalsScored ranks tied teams by goals scored
+	TiebreakerGoalsScored = "goals_scored"
+	// TiebreakerHeadToHead ranks tied teams by the table of the matches between them
+	TiebreakerHeadToHead = "head_to_head"
+	// TiebreakerFairPlay ranks tied teams by their cards, a yellow card costs 1 point and a red card 3
+	TiebreakerFairPlay = "fair_play"
+)
+
+// Tiebreakers are the valid tiebreakers of a competition
+var Tiebreakers = []string{
+	TiebreakerGoalDifference,
+	TiebreakerGoalsScored,
+	TiebreakerHeadToHead,
+	TiebreakerFairPlay,
+}
+
+// Points represents the points earned by a team for the result of a match
+type Points struct {
+	Win  int `json:"win"`
+	Draw int `json:"draw"`
+	Loss int `json:"loss"`
+}
+
+// KnockoutRules represents how the ties of a knockout are played
+type KnockoutRules struct {
+	Legs           int  `json:"legs"`
+	AwayGoals      bool `json:"away_goals" bson:"away_goals"`
+	SingleLegFinal bool `json:"single_leg_final" bson:"single_leg_final"`
+}
+
+// Group represents a group of teams playing a round-robin
+type Group struct {
+	Name    string               `json:"name"`
+	TeamIDs []primitive.ObjectID `json:"team_ids" bson:"team_ids"`
+}
+
+// GroupRules represents the group stage of a competition and how many teams of each group qualify
+type GroupRules struct {
+	Groups     []Group `json:"groups"`
+	Legs       int     `json:"legs"`
+	Advance    int     `json:"advance"`
+	BestPlaced int     `json:"best_placed" bson:"best_placed"`
+}
+
+// Competition represents competition model
+type Competition struct {
+	ID          primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	Name        string               `json:"name"`
+	Season      string               `json:"season"`
+	Type        string               `json:"type"`
+	TeamIDs     []primitive.ObjectID `json:"team_ids" bson:"team_ids"`
+	Points      Points               `json:"points"`
+	Tiebreakers []string             `json:"tiebreakers"`
+	Knockout    *KnockoutRules       `json:"knockout,omitempty" bson:"knockout,omitempty"`
+	GroupStage  *GroupRules          `json:"group_stage,omitempty" bson:"group_stage,omitempty"`
+	CreatedAt   time.Time            `json:"created_at" bson:"created_at"`
+}
+
+// IsKnockout returns whether the competition is a knockout, competitions stored before types are leagues
+func (c Competition) IsKnockout() bool {
+	return c.Type == CompetitionTypeKnockout
+}
+
+// HasGroups returns whether the competition starts with a group stage
+func (c Competition) HasGroups() bool {
+	return c.Type == CompetitionTypeGroups
+}
+
+// Standing represents the row of a team in the table of a competition, or of a group
+type Standing struct {
+	Position       int                `json:"position"`
+	TeamID         primitive.ObjectID `json:"team_id"`
+	Group          string             `json:"group,omitempty"`
+	Played         int                `json:"played"`
+	Won            int                `json:"won"`
+	Drawn          int                `json:"drawn"`
+	Lost           int                `json:"lost"`
+	GoalsFor       int                `json:"goals_for"`
+	GoalsAgainst   int                `json:"goals_against"`
+	GoalDifference int                `json:"goal_difference"`
+	Points         int                `json:"points"`
+	FairPlay       int                `json:"fair_play"`
+}
+
+// GroupTable represents the table of a group
+type GroupTable struct {
+	Name      string     `json:"name"`
+	Complete  bool       `json:"complete"`
+	Standings []Standing `json:"standings"`
+}
+
+// GroupStandings represents the tables of a group stage and the ranking of the best placed teams
+type GroupStandings struct {
+	Groups     []GroupTable `json:"groups"`
+	BestPlaced []Standing   `json:"best_placed"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// Starters is the number of players starting a match for a team
+const Starters = 11
+
+// LineupPlayer represents a player of a lineup, the position is the slot of the formation of a starter
+type LineupPlayer struct {
+	PlayerID    primitive.ObjectID `json:"player_id" bson:"player_id"`
+	ShirtNumber int                `json:"shirt_number" bson:"shirt_number"`
+	Position    string             `json:"position,omitempty" bson:"position,omitempty"`
+}
+
+// Lineup represents the starting XI and the bench of a team in a match, e.g. in a 4-3-3
+type Lineup struct {
+	TeamID    primitive.ObjectID `json:"team_id" bson:"team_id"`
+	Formation string             `json:"formation"`
+	Starters  []LineupPlayer     `json:"starters"`
+	Bench     []LineupPlayer     `json:"bench"`
+}
+
+// Lineups represents the lineups of both teams of a match, nil until announced
+type Lineups struct {
+	Home *Lineup `json:"home"`
+	Away *Lineup `json:"away"`
+}
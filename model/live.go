@@ -0,0 +1,30 @@
+package model
+
+const (
+	// LiveMatch is the match as it stands, sent when a client connects without replay
+	LiveMatch = "match"
+	// LiveEvent is an event recorded in the match
+	LiveEvent = "event"
+	// LiveEventRemoved is an event of the match removed as recorded by mistake
+	LiveEventRemoved = "event_removed"
+	// LiveScore is the new score of the match
+	LiveScore = "score"
+	// LiveStatus is the new status of the match
+	LiveStatus = "status"
+)
+
+// LiveScoreData represents the data of a score message, the penalties are the shootout if any
+type LiveScoreData struct {
+	Score     Score  `json:"score"`
+	Penalties *Score `json:"penalties,omitempty"`
+}
+
+// LiveStatusData represents the data of a status message
+type LiveStatusData struct {
+	Status string `json:"status"`
+}
+
+// LiveEventRemovedData represents the data of a message of a removed event
+type LiveEventRemovedData struct {
+	EventID string `json:"event_id"`
+}
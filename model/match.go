@@ -0,0 +1,93 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// MatchStatusScheduled is the status of a match which has not started yet
+	MatchStatusScheduled = "scheduled"
+	// MatchStatusLive is the status of a match being played
+	MatchStatusLive = "live"
+	// MatchStatusFinished is the status of a match which is over
+	MatchStatusFinished = "finished"
+	// MatchStatusPostponed is the status of a match moved to a later date
+	MatchStatusPostponed = "postponed"
+	// MatchStatusCancelled is the status of a match which will not be played
+	MatchStatusCancelled = "cancelled"
+)
+
+// MatchStatuses are the valid statuses of a match
+var MatchStatuses = []string{
+	MatchStatusScheduled,
+	MatchStatusLive,
+	MatchStatusFinished,
+	MatchStatusPostponed,
+	MatchStatusCancelled,
+}
+
+const (
+	// EventGoal is a goal scored by the player
+	EventGoal = "goal"
+	// EventOwnGoal is a goal scored by the player against its own team
+	EventOwnGoal = "own_goal"
+	// EventPenalty is a penalty scored by the player
+	EventPenalty = "penalty"
+	// EventYellowCard is a yellow card shown to the player
+	EventYellowCard = "yellow_card"
+	// EventRedCard is a red card shown to the player
+	EventRedCard = "red_card"
+	// EventSubstitution is the player replaced by another player of the team
+	EventSubstitution = "substitution"
+	// EventVAR is a video assistant referee decision, it may overturn a goal
+	EventVAR = "var"
+)
+
+// EventTypes are the valid types of a match event
+var EventTypes = []string{
+	EventGoal,
+	EventOwnGoal,
+	EventPenalty,
+	EventYellowCard,
+	EventRedCard,
+	EventSubstitution,
+	EventVAR,
+}
+
+// MatchEvent represents something which happened in a match at a minute
+type MatchEvent struct {
+	ID         primitive.ObjectID  `json:"id" bson:"_id"`
+	Type       string              `json:"type"`
+	Minute     int                 `json:"minute"`
+	AddedTime  int                 `json:"added_time" bson:"added_time"`
+	TeamID     *primitive.ObjectID `json:"team_id,omitempty" bson:"team_id,omitempty"`
+	PlayerID   *primitive.ObjectID `json:"player_id,omitempty" bson:"player_id,omitempty"`
+	PlayerInID *primitive.ObjectID `json:"player_in_id,omitempty" bson:"player_in_id,omitempty"`
+	AssistID   *primitive.ObjectID `json:"assist_id,omitempty" bson:"assist_id,omitempty"`
+	EventID    *primitive.ObjectID `json:"event_id,omitempty" bson:"event_id,omitempty"`
+	Detail     string              `json:"detail,omitempty" bson:"detail,omitempty"`
+}
+
+// Score represents the goals of both teams of a match
+type Score struct {
+	Home int `json:"home"`
+	Away int `json:"away"`
+}
+
+// Match represents match model, its score is derived from its events
+type Match struct {
+	ID            primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	CompetitionID *primitive.ObjectID `json:"competition_id,omitempty" bson:"competition_id,omitempty"`
+	HomeTeamID    primitive.ObjectID  `json:"home_team_id" bson:"home_team_id"`
+	AwayTeamID    primitive.ObjectID  `json:"away_team_id" bson:"away_team_id"`
+	Kickoff       time.Time           `json:"kickoff"`
+	Venue         string              `json:"venue"`
+	Status        string              `json:"status"`
+	Score         Score               `json:"score" bson:"-"`
+	Penalties     *Score              `json:"penalties,omitempty" bson:"penalties,omitempty"`
+	Events        []MatchEvent        `json:"events"`
+	Lineups       *Lineups            `json:"lineups,omitempty" bson:"lineups,omitempty"`
+	CreatedAt     time.Time           `json:"created_at" bson:"created_at"`
+}
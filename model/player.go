@@ -1,16 +1,71 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Player represents player model
+// DateLayout is the layout of the dates of a player, e.g. 1986-04-16
+const DateLayout = "2006-01-02"
+
+const (
+	// FootLeft is a left-footed player
+	FootLeft = "left"
+	// FootRight is a right-footed player
+	FootRight = "right"
+	// FootBoth is a two-footed player
+	FootBoth = "both"
+)
+
+// Feet are the valid preferred feet of a player
+var Feet = []string{
+	FootLeft,
+	FootRight,
+	FootBoth,
+}
+
+// Player represents player model, position is the main position and positions all the playable ones
 type Player struct {
-	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Name      string             `json:"name"`
-	Nickname  string             `json:"nickname"`
-	Position  string             `json:"position"`
-	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name          string             `json:"name"`
+	Nickname      string             `json:"nickname"`
+	Position      string             `json:"position"`
+	Positions     []string           `json:"positions,omitempty" bson:"positions,omitempty"`
+	DateOfBirth   string             `json:"date_of_birth,omitempty" bson:"date_of_birth,omitempty"`
+	Nationality   string             `json:"nationality,omitempty" bson:"nationality,omitempty"`
+	PreferredFoot string             `json:"preferred_foot,omitempty" bson:"preferred_foot,omitempty"`
+	HeightCm      int                `json:"height_cm,omitempty" bson:"height_cm,omitempty"`
+	WeightKg      int                `json:"weight_kg,omitempty" bson:"weight_kg,omitempty"`
+	ShirtNumber   int                `json:"shirt_number,omitempty" bson:"shirt_number,omitempty"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// AgeAt returns the age of the player at a time, nil without date of birth
+func (p Player) AgeAt(t time.Time) *int {
+
+	dob, err := time.Parse(DateLayout, p.DateOfBirth)
+	if err != nil {
+		return nil
+	}
+
+	age := t.Year() - dob.Year()
+	if t.Month() < dob.Month() || (t.Month() == dob.Month() && t.Day() < dob.Day()) {
+		age--
+	}
+
+	return &age
+}
+
+// MarshalJSON encodes the player along with its current age, which is never stored
+func (p Player) MarshalJSON() ([]byte, error) {
+
+	// The alias drops the methods so encoding does not recurse
+	type player Player
+
+	return json.Marshal(struct {
+		player
+		Age *int `json:"age,omitempty"`
+	}{player(p), p.AgeAt(time.Now())})
 }
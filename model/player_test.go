@@ -0,0 +1,17 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgeAt(t *testing.T) {
+
+	p := Player{DateOfBirth: "1985-09-09"}
+
+	assert.Equal(t, 35, *p.AgeAt(time.Date(2021, 9, 8, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 36, *p.AgeAt(time.Date(2021, 9, 9, 0, 0, 0, 0, time.UTC)))
+	assert.Nil(t, Player{}.AgeAt(time.Now()))
+}
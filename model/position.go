@@ -0,0 +1,90 @@
+package model
+
+const (
+	// PositionGoalkeeper is the goalkeeper
+	PositionGoalkeeper = "GK"
+	// PositionCentreBack is a centre-back
+	PositionCentreBack = "CB"
+	// PositionLeftBack is the left-back
+	PositionLeftBack = "LB"
+	// PositionRightBack is the right-back
+	PositionRightBack = "RB"
+	// PositionLeftWingBack is the left wing-back
+	PositionLeftWingBack = "LWB"
+	// PositionRightWingBack is the right wing-back
+	PositionRightWingBack = "RWB"
+	// PositionDefensiveMidfielder is a defensive midfielder
+	PositionDefensiveMidfielder = "DM"
+	// PositionCentralMidfielder is a central midfielder
+	PositionCentralMidfielder = "CM"
+	// PositionLeftMidfielder is the left midfielder
+	PositionLeftMidfielder = "LM"
+	// PositionRightMidfielder is the right midfielder
+	PositionRightMidfielder = "RM"
+	// PositionAttackingMidfielder is an attacking midfielder
+	PositionAttackingMidfielder = "AM"
+	// PositionLeftWinger is the left winger
+	PositionLeftWinger = "LW"
+	// PositionRightWinger is the right winger
+	PositionRightWinger = "RW"
+	// PositionCentreForward is a centre-forward playing off the striker
+	PositionCentreForward = "CF"
+	// PositionStriker is a striker
+	PositionStriker = "ST"
+)
+
+// Positions are the valid positions of a player, from the goal to the attack
+var Positions = []string{
+	PositionGoalkeeper,
+	PositionCentreBack,
+	PositionLeftBack,
+	PositionRightBack,
+	PositionLeftWingBack,
+	PositionRightWingBack,
+	PositionDefensiveMidfielder,
+	PositionCentralMidfielder,
+	PositionLeftMidfielder,
+	PositionRightMidfielder,
+	PositionAttackingMidfielder,
+	PositionLeftWinger,
+	PositionRightWinger,
+	PositionCentreForward,
+	PositionStriker,
+}
+
+const (
+	// LineGoalkeeper is the line of the goalkeeper
+	LineGoalkeeper = "goalkeeper"
+	// LineDefence is the line of the defenders
+	LineDefence = "defence"
+	// LineMidfield is the line of the midfielders
+	LineMidfield = "midfield"
+	// LineAttack is the line of the forwards
+	LineAttack = "attack"
+)
+
+// PositionLines are the lines of the positions
+var PositionLines = map[string]string{
+	PositionGoalkeeper:          LineGoalkeeper,
+	PositionCentreBack:          LineDefence,
+	PositionLeftBack:            LineDefence,
+	PositionRightBack:           LineDefence,
+	PositionLeftWingBack:        LineDefence,
+	PositionRightWingBack:       LineDefence,
+	PositionDefensiveMidfielder: LineMidfield,
+	PositionCentralMidfielder:   LineMidfield,
+	PositionLeftMidfielder:      LineMidfield,
+	PositionRightMidfielder:     LineMidfield,
+	PositionAttackingMidfielder: LineMidfield,
+	PositionLeftWinger:          LineAttack,
+	PositionRightWinger:         LineAttack,
+	PositionCentreForward:       LineAttack,
+	PositionStriker:             LineAttack,
+}
+
+// PositionInfo represents a position of the taxonomy, its line and the legacy values normalized to it
+type PositionInfo struct {
+	Code    string   `json:"code"`
+	Line    string   `json:"line"`
+	Aliases []string `json:"aliases"`
+}
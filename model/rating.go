@@ -0,0 +1,53 @@
+package model
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// RatingChange represents the change of the rating of a team after a finished match
+type RatingChange struct {
+	TeamResult
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+	Change float64 `json:"change"`
+}
+
+// Rating represents the Elo rating of a team and its history from the oldest match to the most recent
+type Rating struct {
+	TeamID  primitive.ObjectID `json:"team_id"`
+	Rating  float64            `json:"rating"`
+	Played  int                `json:"played"`
+	History []RatingChange     `json:"history"`
+}
+
+// Ranking represents a row of the ranking of the teams by rating
+type Ranking struct {
+	Position int                `json:"position"`
+	TeamID   primitive.ObjectID `json:"team_id"`
+	Name     string             `json:"name"`
+	Rating   float64            `json:"rating"`
+	Played   int                `json:"played"`
+}
+
+// ExpectedScore represents the expected goals of the teams of a match
+type ExpectedScore struct {
+	Home float64 `json:"home"`
+	Away float64 `json:"away"`
+}
+
+// PredictionTeam represents the strength of a team before a match, its form is its last results from the oldest
+type PredictionTeam struct {
+	TeamID primitive.ObjectID `json:"team_id"`
+	Rating float64            `json:"rating"`
+	Form   string             `json:"form"`
+}
+
+// Prediction represents the probabilities of the outcomes of a match, its expected score and its most likely score
+type Prediction struct {
+	MatchID       primitive.ObjectID `json:"match_id"`
+	Home          PredictionTeam     `json:"home"`
+	Away          PredictionTeam     `json:"away"`
+	HomeWin       float64            `json:"home_win"`
+	Draw          float64            `json:"draw"`
+	AwayWin       float64            `json:"away_win"`
+	ExpectedScore ExpectedScore      `json:"expected_score"`
+	LikelyScore   Score              `json:"likely_score"`
+}
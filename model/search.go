@@ -0,0 +1,15 @@
+package model
+
+const (
+	// SearchTypePlayer is the type of a player search result
+	SearchTypePlayer = "player"
+	// SearchTypeTeam is the type of a team search result
+	SearchTypeTeam = "team"
+)
+
+// SearchResult represents a player or a team found by a search
+type SearchResult struct {
+	Type  string      `json:"type"`
+	Score float64     `json:"score"`
+	Data  interface{} `json:"data"`
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PlayerTotals represents the statistics of a player summed over finished matches
+type PlayerTotals struct {
+	Appearances int `json:"appearances"`
+	Minutes     int `json:"minutes"`
+	Goals       int `json:"goals"`
+	Penalties   int `json:"penalties"`
+	OwnGoals    int `json:"own_goals"`
+	Assists     int `json:"assists"`
+	YellowCards int `json:"yellow_cards"`
+	RedCards    int `json:"red_cards"`
+	CleanSheets int `json:"clean_sheets"`
+}
+
+// CompetitionTotals represents the statistics of a player in a competition, none for friendlies
+type CompetitionTotals struct {
+	CompetitionID *primitive.ObjectID `json:"competition_id"`
+	Season        string              `json:"season"`
+	PlayerTotals
+}
+
+// PlayerStats represents the statistics of a player over all its matches and by competition
+type PlayerStats struct {
+	PlayerID     primitive.ObjectID  `json:"player_id"`
+	Total        PlayerTotals        `json:"total"`
+	Competitions []CompetitionTotals `json:"competitions"`
+}
+
+// Scorer represents a row of the top scorers of a competition, the team is the last team the player scored for
+type Scorer struct {
+	Position int                `json:"position"`
+	PlayerID primitive.ObjectID `json:"player_id"`
+	TeamID   primitive.ObjectID `json:"team_id"`
+	PlayerTotals
+}
+
+const (
+	// ResultWin is a match won by the team
+	ResultWin = "W"
+	// ResultDraw is a match drawn by the team
+	ResultDraw = "D"
+	// ResultLoss is a match lost by the team
+	ResultLoss = "L"
+)
+
+// TeamResult represents a finished match from the side of a team, a tie decided by penalties is a draw
+type TeamResult struct {
+	MatchID      primitive.ObjectID `json:"match_id"`
+	OpponentID   primitive.ObjectID `json:"opponent_id"`
+	Home         bool               `json:"home"`
+	Kickoff      time.Time          `json:"kickoff"`
+	GoalsFor     int                `json:"goals_for"`
+	GoalsAgainst int                `json:"goals_against"`
+	Result       string             `json:"result"`
+}
+
+// TeamRecord represents the results of a team over finished matches, the averages are by match
+type TeamRecord struct {
+	Played              int     `json:"played"`
+	Won                 int     `json:"won"`
+	Drawn               int     `json:"drawn"`
+	Lost                int     `json:"lost"`
+	GoalsFor            int     `json:"goals_for"`
+	GoalsAgainst        int     `json:"goals_against"`
+	GoalsForAverage     float64 `json:"goals_for_average"`
+	GoalsAgainstAverage float64 `json:"goals_against_average"`
+	CleanSheets         int     `json:"clean_sheets"`
+}
+
+// TeamStats represents the statistics of a team
+type TeamStats struct {
+	TeamID      primitive.ObjectID `json:"team_id"`
+	Form        string             `json:"form"`
+	Last        []TeamResult       `json:"last"`
+	Total       TeamRecord         `json:"total"`
+	Home        TeamRecord         `json:"home"`
+	Away        TeamRecord         `json:"away"`
+	BiggestWin  *TeamResult        `json:"biggest_win"`
+	BiggestLoss *TeamResult        `json:"biggest_loss"`
+}
+
+// HeadToHeadTeam represents the record of a team against another team
+type HeadToHeadTeam struct {
+	TeamID       primitive.ObjectID `json:"team_id"`
+	Won          int                `json:"won"`
+	Drawn        int                `json:"drawn"`
+	Lost         int                `json:"lost"`
+	GoalsFor     int                `json:"goals_for"`
+	GoalsAgainst int                `json:"goals_against"`
+	BiggestWin   *TeamResult        `json:"biggest_win"`
+}
+
+// HeadToHead represents the finished meetings of two teams from the side of the first team
+type HeadToHead struct {
+	Played   int            `json:"played"`
+	Team     HeadToHeadTeam `json:"team"`
+	Other    HeadToHeadTeam `json:"other"`
+	Form     string         `json:"form"`
+	Meetings []TeamResult   `json:"meetings"`
+}
@@ -0,0 +1,63 @@
+package bracket
+
+import (
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yezarela/go-soccer/module/competition"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/groups"
+	"github.com/yezarela/go-soccer/pkg/knockout"
+)
+
+// Handler represents the httphandler for bracket
+type Handler struct {
+	competitionRepo competition.Repository
+	matchRepo       match.Repository
+}
+
+// NewHandler initializes endpoints for bracket
+func NewHandler(e *echo.Echo, competitionRepo competition.Repository, matchRepo match.Repository) {
+	handler := &Handler{
+		competitionRepo: competitionRepo,
+		matchRepo:       matchRepo,
+	}
+
+	e.GET("/competitions/:id/bracket", handler.Get)
+}
+
+// Get returns the bracket of a knockout or of the qualifiers of a group stage as a tree from its final
+func (h *Handler) Get(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	comp, err := h.competitionRepo.GetCompetition(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if comp == nil {
+		return api.ResponseNotFound(c, "cannot find the requested competition")
+	}
+
+	if !comp.IsKnockout() && !comp.HasGroups() {
+		return api.ResponseBadRequest(c, "the competition is not a knockout")
+	}
+	if len(comp.TeamIDs) < 2 {
+		return api.ResponseBadRequest(c, "the competition needs at least two teams")
+	}
+
+	matches, err := match.ListAll(ctx, h.matchRepo, "", url.Values{"competition_id": {comp.ID.Hex()}})
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if comp.HasGroups() {
+		return api.ResponseOK(c, groups.Bracket(*comp, matches))
+	}
+
+	res := knockout.Build(comp.TeamIDs, matches, knockout.Rules(*comp))
+
+	return api.ResponseOK(c, res)
+}
@@ -0,0 +1,190 @@
+package bracket
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	competitionMock "github.com/yezarela/go-soccer/module/competition/mock"
+	matchMock "github.com/yezarela/go-soccer/module/match/mock"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/table"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGet(t *testing.T) {
+
+	mockCompetition := model.Competition{}
+	mockCompetition.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444d")
+	mockCompetition.Type = model.CompetitionTypeKnockout
+	mockCompetition.TeamIDs = []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+	mockCompetition.Knockout = &model.KnockoutRules{Legs: 1}
+
+	newContext := func() (echo.Context, *httptest.ResponseRecorder) {
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/competitions/:id/bracket")
+		c.SetParamNames("id")
+		c.SetParamValues(mockCompetition.ID.Hex())
+
+		return c, rec
+	}
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.ID = primitive.NewObjectID()
+		mockMatch.HomeTeamID = mockCompetition.TeamIDs[1]
+		mockMatch.AwayTeamID = mockCompetition.TeamIDs[0]
+		mockMatch.Status = model.MatchStatusFinished
+		mockMatch.Score = model.Score{Home: 1, Away: 0}
+
+		// Setup
+		c, rec := newContext()
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+			matchRepo:       mockMatchRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&mockCompetition, nil)
+		mockMatchRepo.EXPECT().ListMatch(ctx, "", gomock.Any()).Return([]model.Match{mockMatch}, &query.Page{}, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Get(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var res struct {
+				Data model.Tie `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+			assert.Equal(t, "final", res.Data.Name)
+			assert.Equal(t, []primitive.ObjectID{mockMatch.ID}, res.Data.MatchIDs)
+			assert.Equal(t, mockCompetition.TeamIDs[1], *res.Data.WinnerID)
+		}
+	})
+
+	t.Run("Response OK Groups", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+
+		mockGroups := mockCompetition
+		mockGroups.Type = model.CompetitionTypeGroups
+		mockGroups.TeamIDs = ids
+		mockGroups.Points = table.DefaultPoints
+		mockGroups.GroupStage = &model.GroupRules{
+			Groups:  []model.Group{{Name: "A", TeamIDs: ids[:2]}, {Name: "B", TeamIDs: ids[2:]}},
+			Legs:    1,
+			Advance: 1,
+		}
+
+		mockMatch := model.Match{}
+		mockMatch.ID = primitive.NewObjectID()
+		mockMatch.HomeTeamID = ids[0]
+		mockMatch.AwayTeamID = ids[1]
+		mockMatch.Status = model.MatchStatusFinished
+		mockMatch.Score = model.Score{Home: 0, Away: 1}
+
+		// Setup
+		c, rec := newContext()
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+			matchRepo:       mockMatchRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&mockGroups, nil)
+		mockMatchRepo.EXPECT().ListMatch(ctx, "", gomock.Any()).Return([]model.Match{mockMatch}, &query.Page{}, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Get(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var res struct {
+				Data model.Tie `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+			// The winner of group A is known, group B is still to be played
+			assert.Equal(t, "final", res.Data.Name)
+			assert.Equal(t, "A1", res.Data.Slots[0].Label)
+			assert.Equal(t, ids[1], *res.Data.Slots[0].TeamID)
+			assert.Equal(t, "B1", res.Data.Slots[1].Label)
+			assert.Nil(t, res.Data.Slots[1].TeamID)
+			assert.Empty(t, res.Data.MatchIDs)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+
+		league := mockCompetition
+		league.Type = model.CompetitionTypeLeague
+
+		// Setup
+		c, rec := newContext()
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&league, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Get(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext()
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Get(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package competition
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/boltdb"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/storage"
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type boltRepository struct {
+	db *bolt.DB
+}
+
+// NewBoltRepository creates a new competition repository stored in a boltdb file
+func NewBoltRepository(db *bolt.DB) Repository {
+	return &boltRepository{db}
+}
+
+// ListCompetition returns a filtered and sorted page of competitions
+func (repo *boltRepository) ListCompetition(ctx context.Context, params query.Params) ([]model.Competition, *query.Page, error) {
+	op := "competition.Repository.ListCompetition"
+
+	all := map[primitive.ObjectID]model.Competition{}
+
+	err := repo.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltdb.CompetitionsBucket).ForEach(func(k, v []byte) error {
+			var c model.Competition
+			if err := json.Unmarshal(v, &c); err != nil {
+				return err
+			}
+			all[c.ID] = c
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+
+	items, page, err := paginate(all, params)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+
+	return items, page, nil
+}
+
+// GetCompetition returns a competition by id
+func (repo *boltRepository) GetCompetition(ctx context.Context, id string) (*model.Competition, error) {
+	op := "competition.Repository.GetCompetition"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	var data *model.Competition
+
+	err := repo.db.View(func(tx *bolt.Tx) error {
+		var c model.Competition
+		found, err := boltdb.Get(tx.Bucket(boltdb.CompetitionsBucket), oid, &c)
+		if found {
+			data = &c
+		}
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return data, nil
+}
+
+// CreateCompetition creates a new competition
+func (repo *boltRepository) CreateCompetition(ctx context.Context, data model.Competition) (*model.Competition, error) {
+	op := "competition.Repository.CreateCompetition"
+
+	data.ID = primitive.NewObjectID()
+	data.CreatedAt = storage.Now()
+
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		return boltdb.Put(tx.Bucket(boltdb.CompetitionsBucket), data.ID, data)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return &data, nil
+}
+
+// PatchCompetition applies a merge patch to a competition, null values remove the field
+func (repo *boltRepository) PatchCompetition(ctx context.Context, id string, patch map[string]interface{}) (*model.Competition, error) {
+	op := "competition.Repository.PatchCompetition"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	var data *model.Competition
+
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltdb.CompetitionsBucket)
+
+		var c model.Competition
+		found, err := boltdb.Get(b, oid, &c)
+		if err != nil || !found {
+			return err
+		}
+
+		applyPatch(&c, patch)
+		data = &c
+
+		return boltdb.Put(b, oid, c)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return data, nil
+}
+
+// EnsureIndexes does nothing, the bucket is created by the schema migrations
+func (repo *boltRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
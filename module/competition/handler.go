@@ -0,0 +1,439 @@
+package competition
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/team"
+	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/table"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Handler represents the httphandler for competition
+type Handler struct {
+	competitionRepo Repository
+	teamRepo        team.Repository
+}
+
+// NewHandler initializes endpoints for competition
+func NewHandler(e *echo.Echo, competitionRepo Repository, teamRepo team.Repository) {
+	handler := &Handler{
+		competitionRepo: competitionRepo,
+		teamRepo:        teamRepo,
+	}
+
+	e.GET("/competitions", handler.GetAll)
+	e.POST("/competitions", handler.Post)
+	e.GET("/competitions/:id", handler.GetByID)
+	e.PATCH("/competitions/:id", handler.Patch)
+}
+
+// GetAll returns a page of competitions
+func (h *Handler) GetAll(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	params, err := query.ParseParams(c.QueryParams(), Fields)
+	if err != nil {
+		return api.ResponseBadRequest(c, err.Error())
+	}
+
+	res, page, err := h.competitionRepo.ListCompetition(ctx, params)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	return api.ResponsePage(c, res, page)
+}
+
+// GetByID returns a competition by id
+func (h *Handler) GetByID(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	res, err := h.competitionRepo.GetCompetition(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested competition")
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// Post creates a new competition between existing teams, a league by default
+func (h *Handler) Post(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	var body model.Competition
+	err := c.Bind(&body)
+	if err != nil {
+		return api.ResponseUnprocessableEntity(c, "invalid body")
+	}
+
+	if len(body.Type) <= 0 {
+		body.Type = model.CompetitionTypeLeague
+	}
+	if (body.IsKnockout() || body.HasGroups()) && body.Knockout == nil {
+		body.Knockout = &model.KnockoutRules{Legs: 1}
+	}
+
+	if body.HasGroups() && body.GroupStage != nil {
+		if body.GroupStage.Legs == 0 {
+			body.GroupStage.Legs = 1
+		}
+
+		ids := []primitive.ObjectID{}
+		for _, g := range body.GroupStage.Groups {
+			ids = append(ids, g.TeamIDs...)
+		}
+		if body.TeamIDs != nil && !reflect.DeepEqual(body.TeamIDs, ids) {
+			return api.ResponseBadRequest(c, "team_ids must be the teams of the groups in order")
+		}
+		body.TeamIDs = ids
+	}
+
+	if body.Points == (model.Points{}) {
+		body.Points = table.DefaultPoints
+	}
+	if body.Tiebreakers == nil {
+		body.Tiebreakers = table.DefaultTiebreakers
+	}
+	if body.TeamIDs == nil {
+		body.TeamIDs = []primitive.ObjectID{}
+	}
+
+	if msg := validateCompetition(body); len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
+	}
+
+	found, err := h.teamsExist(ctx, body.TeamIDs)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+	if !found {
+		return api.ResponseNotFound(c, "cannot find the teams of the competition")
+	}
+
+	res, err := h.competitionRepo.CreateCompetition(ctx, body)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	return api.ResponseCreated(c, res)
+}
+
+// Patch partially updates a competition by id using JSON merge patch
+func (h *Handler) Patch(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	var body map[string]interface{}
+	err := json.NewDecoder(c.Request().Body).Decode(&body)
+	if err != nil || body == nil {
+		return api.ResponseUnprocessableEntity(c, "invalid body")
+	}
+
+	patch, msg := parseCompetitionPatch(body)
+	if len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
+	}
+
+	_, knockout := patch["knockout"]
+	_, teams := patch["team_ids"]
+
+	// The knockout rules only apply to knockouts and group stages, the teams of a group stage are the teams of its groups
+	if knockout || teams {
+		comp, err := h.competitionRepo.GetCompetition(ctx, c.Param("id"))
+		if err != nil {
+			return api.ResponseError(c, err)
+		}
+		if comp == nil {
+			return api.ResponseNotFound(c, "cannot find the requested competition")
+		}
+		if knockout && !comp.IsKnockout() && !comp.HasGroups() {
+			return api.ResponseBadRequest(c, "knockout can only be patched in knockouts and group stages")
+		}
+		if teams && comp.HasGroups() {
+			return api.ResponseBadRequest(c, "team_ids cannot be patched in group stages")
+		}
+	}
+
+	if ids, ok := patch["team_ids"].([]primitive.ObjectID); ok {
+		found, err := h.teamsExist(ctx, ids)
+		if err != nil {
+			return api.ResponseError(c, err)
+		}
+		if !found {
+			return api.ResponseNotFound(c, "cannot find the teams of the competition")
+		}
+	}
+
+	res, err := h.competitionRepo.PatchCompetition(ctx, c.Param("id"), patch)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested competition")
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// teamsExist returns whether all the teams exist
+func (h *Handler) teamsExist(ctx context.Context, ids []primitive.ObjectID) (bool, error) {
+
+	for _, id := range ids {
+		t, err := h.teamRepo.GetTeam(ctx, id.Hex())
+		if err != nil {
+			return false, err
+		}
+		if t == nil {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// validateCompetition returns the validation message of a competition, empty if valid
+func validateCompetition(body model.Competition) string {
+
+	if len(body.Name) <= 0 {
+		return "name cannot be empty"
+	}
+
+	known := false
+	for _, typ := range model.CompetitionTypes {
+		known = known || typ == body.Type
+	}
+	if !known {
+		return "unknown type " + body.Type
+	}
+
+	if body.IsKnockout() || body.HasGroups() {
+		if msg := validateKnockout(*body.Knockout); len(msg) > 0 {
+			return msg
+		}
+	} else if body.Knockout != nil {
+		return "knockout is only allowed in knockouts and group stages"
+	}
+
+	if body.HasGroups() {
+		if body.GroupStage == nil {
+			return "group_stage cannot be empty in group stages"
+		}
+		if msg := validateGroupStage(*body.GroupStage); len(msg) > 0 {
+			return msg
+		}
+	} else if body.GroupStage != nil {
+		return "group_stage is only allowed in group stages"
+	}
+
+	if msg := validateTeamIDs(body.TeamIDs); len(msg) > 0 {
+		return msg
+	}
+	if msg := validatePoints(body.Points); len(msg) > 0 {
+		return msg
+	}
+
+	return validateTiebreakers(body.Tiebreakers)
+}
+
+func validateTeamIDs(ids []primitive.ObjectID) string {
+
+	seen := map[primitive.ObjectID]bool{}
+	for _, id := range ids {
+		if id.IsZero() {
+			return "invalid team id"
+		}
+		if seen[id] {
+			return "duplicate team id " + id.Hex()
+		}
+		seen[id] = true
+	}
+
+	return ""
+}
+
+func validateKnockout(rules model.KnockoutRules) string {
+
+	if rules.Legs != 1 && rules.Legs != 2 {
+		return "knockout legs must be 1 or 2"
+	}
+	if rules.AwayGoals && rules.Legs != 2 {
+		return "away goals only apply to ties of 2 legs"
+	}
+
+	return ""
+}
+
+func validateGroupStage(rules model.GroupRules) string {
+
+	if len(rules.Groups) <= 0 {
+		return "group_stage needs at least one group"
+	}
+	if rules.Legs != 1 && rules.Legs != 2 {
+		return "group_stage legs must be 1 or 2"
+	}
+
+	names := map[string]bool{}
+	teams := map[primitive.ObjectID]bool{}
+	smallest := 0
+
+	for _, g := range rules.Groups {
+		if len(g.Name) <= 0 {
+			return "group name cannot be empty"
+		}
+		if names[g.Name] {
+			return "duplicate group " + g.Name
+		}
+		names[g.Name] = true
+
+		if len(g.TeamIDs) < 2 {
+			return "group " + g.Name + " needs at least two teams"
+		}
+		if smallest == 0 || len(g.TeamIDs) < smallest {
+			smallest = len(g.TeamIDs)
+		}
+
+		for _, id := range g.TeamIDs {
+			if id.IsZero() {
+				return "invalid team id"
+			}
+			if teams[id] {
+				return "duplicate team id " + id.Hex()
+			}
+			teams[id] = true
+		}
+	}
+
+	if rules.Advance < 1 || rules.Advance > smallest {
+		return "group_stage advance must be between 1 and the size of the smallest group"
+	}
+	if rules.BestPlaced < 0 || rules.BestPlaced > len(rules.Groups) {
+		return "group_stage best_placed must be between 0 and the number of groups"
+	}
+	if rules.BestPlaced > 0 && rules.Advance >= smallest {
+		return "group_stage best_placed needs a place after the qualified ones in every group"
+	}
+	if rules.Advance*len(rules.Groups)+rules.BestPlaced < 2 {
+		return "group_stage needs at least two qualifiers"
+	}
+
+	return ""
+}
+
+func validatePoints(p model.Points) string {
+
+	if p.Win < 0 || p.Draw < 0 || p.Loss < 0 {
+		return "points cannot be negative"
+	}
+
+	return ""
+}
+
+func validateTiebreakers(tiebreakers []string) string {
+
+	seen := map[string]bool{}
+	for _, tb := range tiebreakers {
+		known := false
+		for _, k := range model.Tiebreakers {
+			known = known || k == tb
+		}
+		if !known {
+			return "unknown tiebreaker " + tb
+		}
+		if seen[tb] {
+			return "duplicate tiebreaker " + tb
+		}
+		seen[tb] = true
+	}
+
+	return ""
+}
+
+// parseCompetitionPatch validates a competition merge patch and returns it with typed values
+func parseCompetitionPatch(body map[string]interface{}) (map[string]interface{}, string) {
+
+	patch := map[string]interface{}{}
+
+	for k, v := range body {
+		switch k {
+		case "name":
+			if s, ok := v.(string); !ok || len(s) <= 0 {
+				return nil, k + " cannot be empty"
+			}
+			patch[k] = v
+		case "season":
+			if _, ok := v.(string); !ok && v != nil {
+				return nil, k + " must be a string"
+			}
+			patch[k] = v
+		case "team_ids":
+			ids := []primitive.ObjectID{}
+			if !convert(v, &ids) {
+				return nil, k + " must be an array of team ids"
+			}
+			if msg := validateTeamIDs(ids); len(msg) > 0 {
+				return nil, msg
+			}
+			patch[k] = ids
+		case "points":
+			var p model.Points
+			if !convert(v, &p) {
+				return nil, k + " must be an object of win, draw and loss"
+			}
+			if msg := validatePoints(p); len(msg) > 0 {
+				return nil, msg
+			}
+			patch[k] = p
+		case "tiebreakers":
+			tiebreakers := []string{}
+			if !convert(v, &tiebreakers) {
+				return nil, k + " must be an array of strings"
+			}
+			if msg := validateTiebreakers(tiebreakers); len(msg) > 0 {
+				return nil, msg
+			}
+			patch[k] = tiebreakers
+		case "knockout":
+			var rules model.KnockoutRules
+			if !convert(v, &rules) {
+				return nil, k + " must be an object of legs, away_goals and single_leg_final"
+			}
+			if msg := validateKnockout(rules); len(msg) > 0 {
+				return nil, msg
+			}
+			patch[k] = &rules
+		default:
+			return nil, k + " cannot be patched"
+		}
+	}
+
+	return patch, ""
+}
+
+// convert decodes a generic JSON value into a typed one, null is not a valid value
+func convert(v interface{}, dst interface{}) bool {
+
+	if v == nil {
+		return false
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(b, dst) == nil
+}
@@ -0,0 +1,434 @@
+package competition
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	competitionMock "github.com/yezarela/go-soccer/module/competition/mock"
+	teamMock "github.com/yezarela/go-soccer/module/team/mock"
+	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/table"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetByID(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+
+		mockCompetition := model.Competition{}
+		mockCompetition.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444d")
+		mockCompetition.Name = "Serie A"
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockCompetition,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/competitions/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockCompetition.ID.Hex())
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&mockCompetition, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetByID(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/competitions/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("5f6a5d6129b2289c40b7444d")
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetCompetition(ctx, "5f6a5d6129b2289c40b7444d").Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetByID(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+}
+
+func TestPost(t *testing.T) {
+
+	t.Run("Response Created", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		mockTeam := model.Team{}
+		mockTeam.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+
+		mockCompetition := model.Competition{}
+		mockCompetition.Name = "Serie A"
+		mockCompetition.Season = "2020-21"
+		mockCompetition.TeamIDs = []primitive.ObjectID{mockTeam.ID}
+
+		mockPayload := `{"name":"Serie A","season":"2020-21","team_ids":["` + mockTeam.ID.Hex() + `"]}`
+
+		// The points and tiebreakers default to the usual league rules
+		mockCompetition.Type = model.CompetitionTypeLeague
+		mockCompetition.Points = table.DefaultPoints
+		mockCompetition.Tiebreakers = table.DefaultTiebreakers
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusCreated,
+			},
+			Data: mockCompetition,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/competitions", strings.NewReader(mockPayload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+			teamRepo:        mockTeamRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockTeam.ID.Hex()).Return(&mockTeam, nil)
+		mockRepo.EXPECT().CreateCompetition(ctx, mockCompetition).Return(&mockCompetition, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Post(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Created Knockout", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		mockPayload := `{"name":"Coppa Italia","type":"knockout"}`
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/competitions", strings.NewReader(mockPayload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+			teamRepo:        mockTeamRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		// A knockout is played in single legs by default
+		mockRepo.EXPECT().CreateCompetition(ctx, gomock.Any()).DoAndReturn(func(_ interface{}, data model.Competition) (*model.Competition, error) {
+			assert.Equal(t, &model.KnockoutRules{Legs: 1}, data.Knockout)
+			return &data, nil
+		})
+
+		// Assertions
+		if assert.NoError(t, h.Post(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+		}
+	})
+
+	t.Run("Response Created Groups", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		mockPayload := `{"name":"Euro","type":"groups","group_stage":{"advance":1,"groups":[
+			{"name":"A","team_ids":["5f6a5d6129b2289c40b74441","5f6a5d6129b2289c40b74442"]},
+			{"name":"B","team_ids":["5f6a5d6129b2289c40b74443","5f6a5d6129b2289c40b74444"]}]}}`
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/competitions", strings.NewReader(mockPayload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+			teamRepo:        mockTeamRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockTeamRepo.EXPECT().GetTeam(ctx, gomock.Any()).Return(&model.Team{}, nil).Times(4)
+
+		// The teams are the teams of the groups, which are played in single legs by default
+		mockRepo.EXPECT().CreateCompetition(ctx, gomock.Any()).DoAndReturn(func(_ interface{}, data model.Competition) (*model.Competition, error) {
+			if assert.Len(t, data.TeamIDs, 4) {
+				assert.Equal(t, "5f6a5d6129b2289c40b74443", data.TeamIDs[2].Hex())
+			}
+			assert.Equal(t, 1, data.GroupStage.Legs)
+			assert.Equal(t, &model.KnockoutRules{Legs: 1}, data.Knockout)
+			return &data, nil
+		})
+
+		// Assertions
+		if assert.NoError(t, h.Post(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		mockPayload := `{"name":"Serie A","team_ids":["5f6a5d6129b2289c40b7444b"]}`
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/competitions", strings.NewReader(mockPayload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+			teamRepo:        mockTeamRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockTeamRepo.EXPECT().GetTeam(ctx, "5f6a5d6129b2289c40b7444b").Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Post(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		payloads := []string{
+			`{"season":"2020-21"}`,
+			`{"name":"Serie A","team_ids":["5f6a5d6129b2289c40b7444b","5f6a5d6129b2289c40b7444b"]}`,
+			`{"name":"Serie A","points":{"win":3,"draw":-1}}`,
+			`{"name":"Serie A","tiebreakers":["away_goals"]}`,
+			`{"name":"Serie A","tiebreakers":["head_to_head","head_to_head"]}`,
+			`{"name":"Serie A","type":"friendly"}`,
+			`{"name":"Serie A","knockout":{"legs":1}}`,
+			`{"name":"Coppa Italia","type":"knockout","knockout":{"legs":3}}`,
+			`{"name":"Coppa Italia","type":"knockout","knockout":{"legs":1,"away_goals":true}}`,
+			`{"name":"Euro","type":"groups"}`,
+			`{"name":"Serie A","group_stage":{"advance":1,"groups":[{"name":"A","team_ids":["5f6a5d6129b2289c40b74441","5f6a5d6129b2289c40b74442"]}]}}`,
+			`{"name":"Euro","type":"groups","group_stage":{"advance":1,"groups":[{"name":"A","team_ids":["5f6a5d6129b2289c40b74441"]}]}}`,
+			`{"name":"Euro","type":"groups","group_stage":{"advance":1,"groups":[{"name":"A","team_ids":["5f6a5d6129b2289c40b74441","5f6a5d6129b2289c40b74442"]}]}}`,
+			`{"name":"Euro","type":"groups","group_stage":{"advance":3,"groups":[{"name":"A","team_ids":["5f6a5d6129b2289c40b74441","5f6a5d6129b2289c40b74442"]}]}}`,
+			`{"name":"Euro","type":"groups","group_stage":{"advance":1,"legs":3,"groups":[{"name":"A","team_ids":["5f6a5d6129b2289c40b74441","5f6a5d6129b2289c40b74442"]},{"name":"B","team_ids":["5f6a5d6129b2289c40b74443","5f6a5d6129b2289c40b74444"]}]}}`,
+			`{"name":"Euro","type":"groups","group_stage":{"advance":1,"groups":[{"name":"A","team_ids":["5f6a5d6129b2289c40b74441","5f6a5d6129b2289c40b74442"]},{"name":"A","team_ids":["5f6a5d6129b2289c40b74443","5f6a5d6129b2289c40b74444"]}]}}`,
+			`{"name":"Euro","type":"groups","group_stage":{"advance":1,"groups":[{"name":"A","team_ids":["5f6a5d6129b2289c40b74441","5f6a5d6129b2289c40b74442"]},{"name":"B","team_ids":["5f6a5d6129b2289c40b74442","5f6a5d6129b2289c40b74444"]}]}}`,
+			`{"name":"Euro","type":"groups","group_stage":{"advance":2,"best_placed":1,"groups":[{"name":"A","team_ids":["5f6a5d6129b2289c40b74441","5f6a5d6129b2289c40b74442"]},{"name":"B","team_ids":["5f6a5d6129b2289c40b74443","5f6a5d6129b2289c40b74444"]}]}}`,
+			`{"name":"Euro","type":"groups","team_ids":["5f6a5d6129b2289c40b74441"],"group_stage":{"advance":1,"groups":[{"name":"A","team_ids":["5f6a5d6129b2289c40b74441","5f6a5d6129b2289c40b74442"]},{"name":"B","team_ids":["5f6a5d6129b2289c40b74443","5f6a5d6129b2289c40b74444"]}]}}`,
+		}
+
+		for _, mockPayload := range payloads {
+
+			// Setup
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/competitions", strings.NewReader(mockPayload))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := &Handler{
+				competitionRepo: mockRepo,
+				teamRepo:        mockTeamRepo,
+			}
+
+			// Assertions
+			if assert.NoError(t, h.Post(c)) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code, mockPayload)
+			}
+		}
+	})
+}
+
+func TestPatch(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+
+		mockCompetition := model.Competition{}
+		mockCompetition.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444d")
+		mockCompetition.Name = "Serie A"
+
+		mockPayload := `{"season":null,"points":{"win":2,"draw":1,"loss":0},"tiebreakers":["head_to_head"]}`
+		mockPatch := map[string]interface{}{
+			"season":      nil,
+			"points":      model.Points{Win: 2, Draw: 1, Loss: 0},
+			"tiebreakers": []string{model.TiebreakerHeadToHead},
+		}
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(mockPayload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/competitions/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockCompetition.ID.Hex())
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().PatchCompetition(ctx, mockCompetition.ID.Hex(), mockPatch).Return(&mockCompetition, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Patch(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+
+		payloads := []string{
+			`{"name":""}`,
+			`{"team_ids":null}`,
+			`{"team_ids":["invalid"]}`,
+			`{"points":"3-1-0"}`,
+			`{"tiebreakers":["coin_toss"]}`,
+			`{"knockout":{"legs":0}}`,
+			`{"type":"knockout"}`,
+			`{"group_stage":null}`,
+			`{"created_at":"2020-10-17T00:00:00Z"}`,
+		}
+
+		for _, mockPayload := range payloads {
+
+			// Setup
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(mockPayload))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			c.SetPath("/competitions/:id")
+			c.SetParamNames("id")
+			c.SetParamValues("5f6a5d6129b2289c40b7444d")
+
+			h := &Handler{
+				competitionRepo: mockRepo,
+			}
+
+			// Assertions
+			if assert.NoError(t, h.Patch(c)) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code, mockPayload)
+			}
+		}
+	})
+
+	t.Run("Response Bad Request Group Stage", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+
+		mockCompetition := model.Competition{}
+		mockCompetition.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444d")
+		mockCompetition.Type = model.CompetitionTypeGroups
+
+		mockPayload := `{"team_ids":["5f6a5d6129b2289c40b74441","5f6a5d6129b2289c40b74442"]}`
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(mockPayload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/competitions/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockCompetition.ID.Hex())
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		// The teams of a group stage are the teams of its groups
+		mockRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&mockCompetition, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Patch(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package competition
+
+import (
+	"context"
+	"sort"
+
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/memdb"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryRepository struct {
+	db *memdb.DB
+}
+
+// NewMemoryRepository creates a new competition repository stored in memory
+func NewMemoryRepository(db *memdb.DB) Repository {
+	return &memoryRepository{db}
+}
+
+// ListCompetition returns a filtered and sorted page of competitions
+func (repo *memoryRepository) ListCompetition(ctx context.Context, params query.Params) ([]model.Competition, *query.Page, error) {
+	repo.db.RLock()
+	defer repo.db.RUnlock()
+
+	return paginate(repo.db.Competitions, params)
+}
+
+// GetCompetition returns a competition by id
+func (repo *memoryRepository) GetCompetition(ctx context.Context, id string) (*model.Competition, error) {
+	repo.db.RLock()
+	defer repo.db.RUnlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	c, ok := repo.db.Competitions[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	return &c, nil
+}
+
+// CreateCompetition creates a new competition
+func (repo *memoryRepository) CreateCompetition(ctx context.Context, data model.Competition) (*model.Competition, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	data.ID = primitive.NewObjectID()
+	data.CreatedAt = storage.Now()
+
+	repo.db.Competitions[data.ID] = data
+
+	return &data, nil
+}
+
+// PatchCompetition applies a merge patch to a competition, null values remove the field
+func (repo *memoryRepository) PatchCompetition(ctx context.Context, id string, patch map[string]interface{}) (*model.Competition, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	c, ok := repo.db.Competitions[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	applyPatch(&c, patch)
+	repo.db.Competitions[oid] = c
+
+	return &c, nil
+}
+
+// EnsureIndexes does nothing, the in-memory storage has no indexes
+func (repo *memoryRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// paginate returns the filtered and sorted page of competitions
+func paginate(all map[primitive.ObjectID]model.Competition, params query.Params) ([]model.Competition, *query.Page, error) {
+
+	var items []model.Competition
+
+	for _, c := range all {
+		if params.Match(cursorValues(c)) {
+			items = append(items, c)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return params.Less(cursorValues(items[i]), cursorValues(items[j]))
+	})
+
+	page, n, err := params.NewPage(len(items), func(i int) map[string]interface{} {
+		return cursorValues(items[i])
+	}, query.Total(len(all)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return items[:n], page, nil
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: module/competition/repository.go
+
+// Package mock_competition is a generated GoMock package.
+package mock_competition
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/yezarela/go-soccer/model"
+	query "github.com/yezarela/go-soccer/pkg/query"
+	reflect "reflect"
+)
+
+// MockRepository is a mock of Repository interface
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListCompetition mocks base method
+func (m *MockRepository) ListCompetition(ctx context.Context, params query.Params) ([]model.Competition, *query.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCompetition", ctx, params)
+	ret0, _ := ret[0].([]model.Competition)
+	ret1, _ := ret[1].(*query.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCompetition indicates an expected call of ListCompetition
+func (mr *MockRepositoryMockRecorder) ListCompetition(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCompetition", reflect.TypeOf((*MockRepository)(nil).ListCompetition), ctx, params)
+}
+
+// GetCompetition mocks base method
+func (m *MockRepository) GetCompetition(ctx context.Context, id string) (*model.Competition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCompetition", ctx, id)
+	ret0, _ := ret[0].(*model.Competition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCompetition indicates an expected call of GetCompetition
+func (mr *MockRepositoryMockRecorder) GetCompetition(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompetition", reflect.TypeOf((*MockRepository)(nil).GetCompetition), ctx, id)
+}
+
+// CreateCompetition mocks base method
+func (m *MockRepository) CreateCompetition(ctx context.Context, data model.Competition) (*model.Competition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCompetition", ctx, data)
+	ret0, _ := ret[0].(*model.Competition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCompetition indicates an expected call of CreateCompetition
+func (mr *MockRepositoryMockRecorder) CreateCompetition(ctx, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCompetition", reflect.TypeOf((*MockRepository)(nil).CreateCompetition), ctx, data)
+}
+
+// PatchCompetition mocks base method
+func (m *MockRepository) PatchCompetition(ctx context.Context, id string, patch map[string]interface{}) (*model.Competition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchCompetition", ctx, id, patch)
+	ret0, _ := ret[0].(*model.Competition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchCompetition indicates an expected call of PatchCompetition
+func (mr *MockRepositoryMockRecorder) PatchCompetition(ctx, id, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchCompetition", reflect.TypeOf((*MockRepository)(nil).PatchCompetition), ctx, id, patch)
+}
+
+// EnsureIndexes mocks base method
+func (m *MockRepository) EnsureIndexes(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureIndexes", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureIndexes indicates an expected call of EnsureIndexes
+func (mr *MockRepositoryMockRecorder) EnsureIndexes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureIndexes", reflect.TypeOf((*MockRepository)(nil).EnsureIndexes), ctx)
+}
@@ -0,0 +1,201 @@
+package competition
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repository represents repository of pkg competition
+type Repository interface {
+	ListCompetition(ctx context.Context, params query.Params) ([]model.Competition, *query.Page, error)
+	GetCompetition(ctx context.Context, id string) (*model.Competition, error)
+	CreateCompetition(ctx context.Context, data model.Competition) (*model.Competition, error)
+	PatchCompetition(ctx context.Context, id string, patch map[string]interface{}) (*model.Competition, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	db *mongo.Database
+}
+
+// Fields are the filterable and sortable fields of a competition
+var Fields = query.Fields{
+	"name":       query.String,
+	"season":     query.String,
+	"type":       query.String,
+	"created_at": query.Time,
+}
+
+// cursorValues returns the sortable values of a competition
+func cursorValues(data model.Competition) map[string]interface{} {
+	return map[string]interface{}{
+		"name":       data.Name,
+		"season":     data.Season,
+		"type":       data.Type,
+		"created_at": data.CreatedAt,
+		"_id":        data.ID,
+	}
+}
+
+// applyPatch applies a typed merge patch to a competition, nil values remove the field
+func applyPatch(c *model.Competition, patch map[string]interface{}) {
+
+	for k, v := range patch {
+		switch k {
+		case "name":
+			c.Name, _ = v.(string)
+		case "season":
+			c.Season, _ = v.(string)
+		case "team_ids":
+			c.TeamIDs, _ = v.([]primitive.ObjectID)
+		case "points":
+			c.Points, _ = v.(model.Points)
+		case "tiebreakers":
+			c.Tiebreakers, _ = v.([]string)
+		case "knockout":
+			c.Knockout, _ = v.(*model.KnockoutRules)
+		}
+	}
+}
+
+// NewRepository creates a new competition repository
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{db}
+}
+
+// ListCompetition returns a filtered and sorted page of competitions
+func (repo *repository) ListCompetition(ctx context.Context, params query.Params) ([]model.Competition, *query.Page, error) {
+	op := "competition.Repository.ListCompetition"
+
+	opts := options.Find().SetSort(params.MongoSort()).SetLimit(int64(params.FetchLimit()))
+
+	cur, err := repo.db.Collection("competitions").Find(ctx, params.MongoFilter(), opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+	defer cur.Close(ctx)
+
+	var items []model.Competition
+
+	page, err := params.MongoPage(ctx, cur, &items, query.EstimatedCount(ctx, repo.db.Collection("competitions")))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+
+	return items, page, nil
+}
+
+// GetCompetition returns a competition by id
+func (repo *repository) GetCompetition(ctx context.Context, id string) (*model.Competition, error) {
+	op := "competition.Repository.GetCompetition"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	var data *model.Competition
+
+	err := repo.db.Collection("competitions").FindOne(ctx, bson.M{"_id": oid}).Decode(&data)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, op)
+	}
+
+	return data, nil
+}
+
+// CreateCompetition creates a new competition
+func (repo *repository) CreateCompetition(ctx context.Context, data model.Competition) (*model.Competition, error) {
+	op := "competition.Repository.CreateCompetition"
+
+	body := bson.M{
+		"name":        data.Name,
+		"season":      data.Season,
+		"type":        data.Type,
+		"team_ids":    data.TeamIDs,
+		"points":      data.Points,
+		"tiebreakers": data.Tiebreakers,
+		"created_at":  time.Now(),
+	}
+
+	if data.Knockout != nil {
+		body["knockout"] = data.Knockout
+	}
+	if data.GroupStage != nil {
+		body["group_stage"] = data.GroupStage
+	}
+
+	res, err := repo.db.Collection("competitions").InsertOne(ctx, body)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		return repo.GetCompetition(ctx, oid.Hex())
+	}
+
+	return nil, nil
+}
+
+// PatchCompetition applies a merge patch of typed values to a competition, null values remove the field
+func (repo *repository) PatchCompetition(ctx context.Context, id string, patch map[string]interface{}) (*model.Competition, error) {
+	op := "competition.Repository.PatchCompetition"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	set := bson.M{}
+	unset := bson.M{}
+
+	for k, v := range patch {
+		if v == nil {
+			unset[k] = ""
+		} else {
+			set[k] = v
+		}
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	if len(update) == 0 {
+		return repo.GetCompetition(ctx, id)
+	}
+
+	res, err := repo.db.Collection("competitions").UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if res.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	return repo.GetCompetition(ctx, id)
+}
+
+// EnsureIndexes creates the indexes of the competitions of a team
+func (repo *repository) EnsureIndexes(ctx context.Context) error {
+	op := "competition.Repository.EnsureIndexes"
+
+	index := mongo.IndexModel{Keys: bson.D{{Key: "team_ids", Value: 1}}}
+
+	_, err := repo.db.Collection("competitions").Indexes().CreateOne(ctx, index)
+	if err != nil {
+		return errors.Wrap(err, op)
+	}
+
+	return nil
+}
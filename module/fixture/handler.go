@@ -0,0 +1,233 @@
+package fixture
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/competition"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/schedule"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultInterval is the number of days between two rounds, one round a week
+const defaultInterval = 7
+
+// generateBody represents the payload to generate the fixtures of a competition
+type generateBody struct {
+	Start            time.Time `json:"start"`
+	DoubleRoundRobin bool      `json:"double_round_robin"`
+	IntervalDays     int       `json:"interval_days"`
+	RestDays         int       `json:"rest_days"`
+	BlackoutDates    []string  `json:"blackout_dates"`
+}
+
+// Handler represents the httphandler for fixture
+type Handler struct {
+	competitionRepo competition.Repository
+	matchRepo       match.Repository
+	listeners       []match.Listener
+}
+
+// NewHandler initializes endpoints for fixture
+func NewHandler(e *echo.Echo, competitionRepo competition.Repository, matchRepo match.Repository, listeners ...match.Listener) {
+	handler := &Handler{
+		competitionRepo: competitionRepo,
+		matchRepo:       matchRepo,
+		listeners:       listeners,
+	}
+
+	// The router has no escape for colons, the custom method is read as a param
+	e.POST("/competitions/:id/fixtures:action", handler.Generate)
+}
+
+// Generate schedules the round-robin of the teams or the groups of a competition and creates its matches
+func (h *Handler) Generate(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	if c.Param("action") != ":generate" {
+		return api.ResponseNotFound(c, "cannot find the requested action")
+	}
+
+	// Bind would try to bind the id path param into the body
+	var body generateBody
+	err := json.NewDecoder(c.Request().Body).Decode(&body)
+	if err != nil {
+		return api.ResponseUnprocessableEntity(c, "invalid body")
+	}
+
+	opts, msg := parseOptions(body)
+	if len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
+	}
+
+	comp, err := h.competitionRepo.GetCompetition(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if comp == nil {
+		return api.ResponseNotFound(c, "cannot find the requested competition")
+	}
+
+	if comp.IsKnockout() {
+		return api.ResponseBadRequest(c, "the matches of a knockout are the legs of its bracket")
+	}
+	if len(comp.TeamIDs) < 2 {
+		return api.ResponseBadRequest(c, "the competition needs at least two teams")
+	}
+
+	// Generating twice would schedule every match twice
+	params, _ := query.ParseParams(url.Values{"competition_id": {comp.ID.Hex()}, "limit": {"1"}}, match.Fields)
+	existing, _, err := h.matchRepo.ListMatch(ctx, "", params)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+	if len(existing) > 0 {
+		return api.ResponseConflict(c, "the fixtures of the competition are already generated")
+	}
+
+	opts.Busy, err = h.busy(ctx, comp.TeamIDs, opts)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	rounds := roundRobin(*comp, body.DoubleRoundRobin)
+	dates := schedule.Dates(rounds, opts)
+
+	res := []model.Match{}
+
+	for i, round := range rounds {
+		for _, f := range round {
+			m, err := h.matchRepo.CreateMatch(ctx, model.Match{
+				CompetitionID: &comp.ID,
+				HomeTeamID:    f.HomeTeamID,
+				AwayTeamID:    f.AwayTeamID,
+				Kickoff:       dates[i],
+				Status:        model.MatchStatusScheduled,
+			})
+			if err != nil {
+				// A partial schedule would conflict with generating again
+				if rerr := h.rollback(ctx, res); rerr != nil {
+					err = errors.Wrapf(err, "cannot delete the generated matches: %v", rerr)
+				}
+				return api.ResponseError(c, err)
+			}
+			if m == nil {
+				continue
+			}
+
+			res = append(res, *m)
+		}
+	}
+
+	for _, m := range res {
+		for _, l := range h.listeners {
+			l.MatchChanged(m)
+		}
+	}
+
+	return api.ResponseCreated(c, res)
+}
+
+// rollback deletes the matches created before a failure, it keeps deleting past an error
+func (h *Handler) rollback(ctx context.Context, created []model.Match) error {
+
+	var res error
+
+	for _, m := range created {
+		if _, err := h.matchRepo.DeleteMatch(ctx, m.ID.Hex()); err != nil && res == nil {
+			res = err
+		}
+	}
+
+	return res
+}
+
+// roundRobin returns the rounds of a competition, the rounds of the groups of a group stage are merged
+func roundRobin(comp model.Competition, double bool) [][]schedule.Fixture {
+
+	if !comp.HasGroups() {
+		return schedule.RoundRobin(comp.TeamIDs, double)
+	}
+
+	res := [][]schedule.Fixture{}
+
+	for _, g := range comp.GroupStage.Groups {
+		for i, round := range schedule.RoundRobin(g.TeamIDs, comp.GroupStage.Legs == 2) {
+			if i == len(res) {
+				res = append(res, []schedule.Fixture{})
+			}
+			res[i] = append(res[i], round...)
+		}
+	}
+
+	return res
+}
+
+// busy returns the kickoffs of the matches the teams already play from the start of the season, rest days included
+func (h *Handler) busy(ctx context.Context, teamIDs []primitive.ObjectID, opts schedule.Options) (map[primitive.ObjectID][]time.Time, error) {
+
+	from := opts.Start.AddDate(0, 0, -opts.RestDays-1)
+	filters := url.Values{"kickoff[gte]": {from.Format(time.RFC3339Nano)}}
+
+	res := map[primitive.ObjectID][]time.Time{}
+
+	for _, id := range teamIDs {
+		matches, err := match.ListAll(ctx, h.matchRepo, id.Hex(), filters)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range matches {
+			if m.Status != model.MatchStatusCancelled {
+				res[id] = append(res[id], m.Kickoff)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// parseOptions validates the body of a generation and returns the options of the schedule
+func parseOptions(body generateBody) (schedule.Options, string) {
+
+	opts := schedule.Options{
+		// Store the precision of mongodb whatever the storage
+		Start:    body.Start.UTC().Truncate(time.Millisecond),
+		Interval: body.IntervalDays,
+		RestDays: body.RestDays,
+	}
+
+	if body.Start.IsZero() {
+		return opts, "start cannot be empty"
+	}
+
+	if opts.Interval == 0 {
+		opts.Interval = defaultInterval
+	}
+	if opts.Interval < 0 || opts.Interval > 365 {
+		return opts, "interval_days must be between 1 and 365"
+	}
+	if opts.RestDays < 0 || opts.RestDays > 30 {
+		return opts, "rest_days must be between 0 and 30"
+	}
+
+	for _, d := range body.BlackoutDates {
+		t, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			return opts, "invalid blackout date " + d
+		}
+		opts.Blackouts = append(opts.Blackouts, t)
+	}
+
+	return opts, ""
+}
@@ -0,0 +1,273 @@
+package fixture
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	competitionMock "github.com/yezarela/go-soccer/module/competition/mock"
+	"github.com/yezarela/go-soccer/module/match"
+	matchMock "github.com/yezarela/go-soccer/module/match/mock"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGenerate(t *testing.T) {
+
+	mockCompetition := model.Competition{}
+	mockCompetition.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444d")
+	mockCompetition.TeamIDs = []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+
+	newContext := func(action, payload string) (echo.Context, *httptest.ResponseRecorder) {
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/competitions/:id/fixtures:action")
+		c.SetParamNames("id", "action")
+		c.SetParamValues(mockCompetition.ID.Hex(), action)
+
+		return c, rec
+	}
+
+	t.Run("Response Created", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		start := time.Date(2020, 9, 19, 15, 0, 0, 0, time.UTC)
+
+		// Setup
+		c, rec := newContext(":generate", `{"start":"2020-09-19T17:00:00+02:00","double_round_robin":true,"blackout_dates":["2020-10-03"]}`)
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+			matchRepo:       mockMatchRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&mockCompetition, nil)
+		mockMatchRepo.EXPECT().ListMatch(ctx, "", gomock.Any()).Return(nil, &query.Page{}, nil)
+		for _, id := range mockCompetition.TeamIDs {
+			mockMatchRepo.EXPECT().ListMatch(ctx, id.Hex(), gomock.Any()).Return(nil, &query.Page{}, nil)
+		}
+		mockMatchRepo.EXPECT().CreateMatch(ctx, gomock.Any()).DoAndReturn(func(_ interface{}, m model.Match) (*model.Match, error) {
+			return &m, nil
+		}).Times(6)
+
+		// Assertions
+		if assert.NoError(t, h.Generate(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+
+			var res struct {
+				Data []model.Match `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+			// Three teams play six rounds, one team rests each round
+			if assert.Len(t, res.Data, 6) {
+				assert.Equal(t, start, res.Data[0].Kickoff)
+				assert.Equal(t, start.AddDate(0, 0, 7), res.Data[1].Kickoff)
+				assert.Equal(t, start.AddDate(0, 0, 15), res.Data[2].Kickoff)
+				assert.Equal(t, model.MatchStatusScheduled, res.Data[0].Status)
+				assert.Equal(t, mockCompetition.ID, *res.Data[0].CompetitionID)
+			}
+		}
+	})
+
+	t.Run("Response Created Groups", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+
+		mockGroups := mockCompetition
+		mockGroups.Type = model.CompetitionTypeGroups
+		mockGroups.TeamIDs = ids
+		mockGroups.GroupStage = &model.GroupRules{
+			Groups:  []model.Group{{Name: "A", TeamIDs: ids[:2]}, {Name: "B", TeamIDs: ids[2:]}},
+			Legs:    1,
+			Advance: 1,
+		}
+
+		start := time.Date(2021, 6, 11, 21, 0, 0, 0, time.UTC)
+
+		// Setup
+		c, rec := newContext(":generate", `{"start":"2021-06-11T21:00:00Z","double_round_robin":true}`)
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+			matchRepo:       mockMatchRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&mockGroups, nil)
+		mockMatchRepo.EXPECT().ListMatch(ctx, "", gomock.Any()).Return(nil, &query.Page{}, nil)
+		for _, id := range ids {
+			mockMatchRepo.EXPECT().ListMatch(ctx, id.Hex(), gomock.Any()).Return(nil, &query.Page{}, nil)
+		}
+		mockMatchRepo.EXPECT().CreateMatch(ctx, gomock.Any()).DoAndReturn(func(_ interface{}, m model.Match) (*model.Match, error) {
+			return &m, nil
+		}).Times(4)
+
+		// Assertions
+		if assert.NoError(t, h.Generate(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+
+			var res struct {
+				Data []model.Match `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+			// The groups play single legs between their own teams, the first round of both groups on the same date
+			if assert.Len(t, res.Data, 4) {
+				assert.Equal(t, start, res.Data[0].Kickoff)
+				assert.Equal(t, start, res.Data[1].Kickoff)
+				assert.Equal(t, start.AddDate(0, 0, 7), res.Data[2].Kickoff)
+				assert.Equal(t, start.AddDate(0, 0, 14), res.Data[3].Kickoff)
+
+				for _, m := range res.Data[1:] {
+					assert.NotContains(t, ids[:2], m.HomeTeamID)
+					assert.NotContains(t, ids[:2], m.AwayTeamID)
+				}
+			}
+		}
+	})
+
+	t.Run("Response Conflict", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext(":generate", `{"start":"2020-09-19T15:00:00Z"}`)
+
+		h := &Handler{
+			competitionRepo: mockRepo,
+			matchRepo:       mockMatchRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&mockCompetition, nil)
+		mockMatchRepo.EXPECT().ListMatch(ctx, "", gomock.Any()).Return([]model.Match{{}}, &query.Page{}, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Generate(c)) {
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("Response Error", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext(":generate", `{"start":"2020-09-19T15:00:00Z"}`)
+
+		listener := &recorder{}
+		h := &Handler{
+			competitionRepo: mockRepo,
+			matchRepo:       mockMatchRepo,
+			listeners:       []match.Listener{listener},
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&mockCompetition, nil)
+		mockMatchRepo.EXPECT().ListMatch(ctx, "", gomock.Any()).Return(nil, &query.Page{}, nil)
+		for _, id := range mockCompetition.TeamIDs {
+			mockMatchRepo.EXPECT().ListMatch(ctx, id.Hex(), gomock.Any()).Return(nil, &query.Page{}, nil)
+		}
+
+		// The third of the three matches fails, the first two are deleted
+		created := []string{}
+		mockMatchRepo.EXPECT().CreateMatch(ctx, gomock.Any()).DoAndReturn(func(_ interface{}, m model.Match) (*model.Match, error) {
+			m.ID = primitive.NewObjectID()
+			created = append(created, m.ID.Hex())
+			return &m, nil
+		}).Times(2)
+		mockMatchRepo.EXPECT().CreateMatch(ctx, gomock.Any()).Return(nil, errors.New("connection reset"))
+
+		deleted := []string{}
+		mockMatchRepo.EXPECT().DeleteMatch(ctx, gomock.Any()).DoAndReturn(func(_ interface{}, id string) (*model.Match, error) {
+			deleted = append(deleted, id)
+			return &model.Match{}, nil
+		}).Times(2)
+
+		// Assertions
+		if assert.NoError(t, h.Generate(c)) {
+			assert.Equal(t, http.StatusInternalServerError, rec.Code)
+			assert.Equal(t, created, deleted)
+			assert.Empty(t, listener.changed)
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Setup
+		c, rec := newContext(":shuffle", `{"start":"2020-09-19T15:00:00Z"}`)
+
+		h := &Handler{}
+
+		// Assertions
+		if assert.NoError(t, h.Generate(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		payloads := []string{
+			`{}`,
+			`{"start":"2020-09-19T15:00:00Z","interval_days":-7}`,
+			`{"start":"2020-09-19T15:00:00Z","rest_days":-1}`,
+			`{"start":"2020-09-19T15:00:00Z","blackout_dates":["25/12/2020"]}`,
+		}
+
+		for _, mockPayload := range payloads {
+
+			// Setup
+			c, rec := newContext(":generate", mockPayload)
+
+			h := &Handler{}
+
+			// Assertions
+			if assert.NoError(t, h.Generate(c)) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code, mockPayload)
+			}
+		}
+	})
+}
+
+// recorder is a listener which records the changed matches
+type recorder struct {
+	changed []model.Match
+}
+
+func (r *recorder) MatchChanged(m model.Match) {
+	r.changed = append(r.changed, m)
+}
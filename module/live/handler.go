@@ -0,0 +1,223 @@
+package live
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/hub"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// heartbeat is the interval of the comments keeping an idle event stream open through proxies,
+	// and of the pings of the websockets, a websocket client answering none for two intervals is disconnected
+	heartbeat = 15 * time.Second
+	// writeTimeout is the time a websocket client has to read a message before it is disconnected
+	writeTimeout = 10 * time.Second
+)
+
+// Handler represents the httphandler for the live updates of matches
+type Handler struct {
+	matchRepo match.Repository
+	tracker   *Tracker
+	heartbeat time.Duration
+}
+
+// NewHandler initializes endpoints for the live updates of matches
+func NewHandler(e *echo.Echo, matchRepo match.Repository, tracker *Tracker) {
+	handler := &Handler{
+		matchRepo: matchRepo,
+		tracker:   tracker,
+		heartbeat: heartbeat,
+	}
+
+	e.GET("/matches/:id/live", handler.GetLive)
+	e.GET("/matches/:id/live/ws", handler.GetLiveWebSocket)
+}
+
+// GetLive streams the updates of a match as server-sent events, replaying them after the last event id
+func (h *Handler) GetLive(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	last, msg := lastEventID(c)
+	if len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
+	}
+
+	m, err := h.matchRepo.GetMatch(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if m == nil {
+		return api.ResponseNotFound(c, "cannot find the requested match")
+	}
+
+	sub, msgs := h.tracker.Subscribe(*m, last)
+	defer h.tracker.Unsubscribe(m.ID, sub)
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.WriteHeader(http.StatusOK)
+
+	// The response is committed, write errors only mean the client went away
+	for _, msg := range msgs {
+		if err := writeEvent(res, msg); err != nil {
+			return nil
+		}
+	}
+	res.Flush()
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if err := writeEvent(res, msg); err != nil {
+				return nil
+			}
+			res.Flush()
+		case <-ticker.C:
+			if _, err := io.WriteString(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// GetLiveWebSocket sends the updates of a match as JSON messages over a websocket, like GetLive
+func (h *Handler) GetLiveWebSocket(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	last, msg := lastEventID(c)
+	if len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
+	}
+
+	m, err := h.matchRepo.GetMatch(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if m == nil {
+		return api.ResponseNotFound(c, "cannot find the requested match")
+	}
+
+	websocket.Handler(func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		sub, msgs := h.tracker.Subscribe(*m, last)
+		defer h.tracker.Unsubscribe(m.ID, sub)
+
+		// The client only answers the pings, reading tells when it goes away or stops answering
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				ws.SetReadDeadline(time.Now().Add(2 * h.heartbeat))
+				frame, err := ws.NewFrameReader()
+				if err != nil {
+					return
+				}
+				// The pings are answered, the pongs and the messages discarded
+				if frame, err = ws.HandleFrame(frame); err != nil {
+					return
+				}
+				if frame != nil {
+					io.Copy(ioutil.Discard, frame)
+				}
+			}
+		}()
+
+		send := func(msg hub.Message) bool {
+			ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+			return websocket.JSON.Send(ws, msg) == nil
+		}
+
+		// The messages are sent with websocket.JSON, writing to the connection only sends the pings
+		ws.PayloadType = websocket.PingFrame
+		ping := func() bool {
+			ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+			_, err := ws.Write(nil)
+			return err == nil
+		}
+
+		ticker := time.NewTicker(h.heartbeat)
+		defer ticker.Stop()
+
+		for _, msg := range msgs {
+			if !send(msg) {
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-closed:
+				return
+			case msg, ok := <-sub.C:
+				if !ok || !send(msg) {
+					return
+				}
+			case <-ticker.C:
+				if !ping() {
+					return
+				}
+			}
+		}
+	}).ServeHTTP(c.Response(), c.Request())
+
+	return nil
+}
+
+// lastEventID returns the id of the last message received by a client coming back or its validation message
+func lastEventID(c echo.Context) (*uint64, string) {
+
+	v := c.Request().Header.Get("Last-Event-ID")
+	if len(v) <= 0 {
+		v = c.QueryParam("last_event_id")
+	}
+
+	if len(v) <= 0 {
+		return nil, ""
+	}
+
+	id, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return nil, "last event id must be a non-negative integer"
+	}
+
+	return &id, ""
+}
+
+// writeEvent writes a message in the format of server-sent events, its data on a single line
+func writeEvent(w io.Writer, msg hub.Message) error {
+
+	data, err := json.Marshal(msg.Data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", msg.ID, msg.Type, data)
+
+	return err
+}
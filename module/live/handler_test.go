@@ -0,0 +1,297 @@
+package live
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yezarela/go-soccer/model"
+	matchMock "github.com/yezarela/go-soccer/module/match/mock"
+	"github.com/yezarela/go-soccer/pkg/hub"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/net/websocket"
+)
+
+// readEvent reads the next server-sent event of a stream, skipping the comments
+func readEvent(t *testing.T, r *bufio.Reader) map[string]string {
+
+	res := map[string]string{}
+
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		if len(line) == 0 {
+			if len(res) > 0 {
+				return res
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		kv := strings.SplitN(line, ": ", 2)
+		require.Len(t, kv, 2)
+		res[kv[0]] = kv[1]
+	}
+}
+
+func TestGetLive(t *testing.T) {
+
+	mockMatch := model.Match{}
+	mockMatch.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444e")
+	mockMatch.Status = model.MatchStatusLive
+	mockMatch.Events = []model.MatchEvent{}
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		mockRepo.EXPECT().GetMatch(gomock.Any(), mockMatch.ID.Hex()).Return(&mockMatch, nil).Times(2)
+
+		// Setup
+		tracker := NewTracker(hub.New(10, 10))
+
+		e := echo.New()
+		h := &Handler{
+			matchRepo: mockRepo,
+			tracker:   tracker,
+			heartbeat: 10 * time.Millisecond,
+		}
+		e.GET("/matches/:id/live", h.GetLive)
+
+		srv := httptest.NewServer(e)
+		defer srv.Close()
+
+		resp, err := http.Get(srv.URL + "/matches/" + mockMatch.ID.Hex() + "/live")
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get(echo.HeaderContentType))
+
+		r := bufio.NewReader(resp.Body)
+
+		// Assertions, the match as it stands then its changes
+		event := readEvent(t, r)
+		assert.Equal(t, map[string]string{"id": "0", "event": model.LiveMatch, "data": event["data"]}, event)
+
+		var data model.Match
+		assert.NoError(t, json.Unmarshal([]byte(event["data"]), &data))
+		assert.Equal(t, mockMatch.ID, data.ID)
+
+		finished := mockMatch
+		finished.Status = model.MatchStatusFinished
+		tracker.MatchChanged(finished)
+
+		assert.Equal(t, map[string]string{"id": "1", "event": model.LiveStatus, "data": `{"status":"finished"}`}, readEvent(t, r))
+		resp.Body.Close()
+
+		// A client coming back replays what it missed
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/matches/"+mockMatch.ID.Hex()+"/live", nil)
+		req.Header.Set("Last-Event-ID", "0")
+
+		resp, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, "1", readEvent(t, bufio.NewReader(resp.Body))["id"])
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id/live")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMatch.ID.Hex())
+
+		h := &Handler{
+			matchRepo: mockRepo,
+			tracker:   NewTracker(hub.New(10, 10)),
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatch.ID.Hex()).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetLive(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/?last_event_id=-1", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id/live")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMatch.ID.Hex())
+
+		h := &Handler{
+			matchRepo: mockRepo,
+			tracker:   NewTracker(hub.New(10, 10)),
+		}
+
+		// Assertions
+		if assert.NoError(t, h.GetLive(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+}
+
+func TestGetLiveWebSocket(t *testing.T) {
+
+	mockMatch := model.Match{}
+	mockMatch.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444e")
+	mockMatch.Status = model.MatchStatusLive
+	mockMatch.Events = []model.MatchEvent{}
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		mockRepo.EXPECT().GetMatch(gomock.Any(), mockMatch.ID.Hex()).Return(&mockMatch, nil)
+
+		// Setup
+		tracker := NewTracker(hub.New(10, 10))
+
+		e := echo.New()
+		h := &Handler{
+			matchRepo: mockRepo,
+			tracker:   tracker,
+			heartbeat: 10 * time.Millisecond,
+		}
+		e.GET("/matches/:id/live/ws", h.GetLiveWebSocket)
+
+		srv := httptest.NewServer(e)
+		defer srv.Close()
+
+		ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/matches/"+mockMatch.ID.Hex()+"/live/ws", "", srv.URL)
+		require.NoError(t, err)
+		defer ws.Close()
+
+		// Assertions, the match as it stands then its changes
+		var msg struct {
+			ID   uint64          `json:"id"`
+			Type string          `json:"type"`
+			Data json.RawMessage `json:"data"`
+		}
+
+		require.NoError(t, websocket.JSON.Receive(ws, &msg))
+		assert.Equal(t, model.LiveMatch, msg.Type)
+
+		goal := mockMatch
+		goal.Score = model.Score{Home: 1}
+		tracker.MatchChanged(goal)
+
+		require.NoError(t, websocket.JSON.Receive(ws, &msg))
+		assert.Equal(t, uint64(1), msg.ID)
+		assert.Equal(t, model.LiveScore, msg.Type)
+		assert.JSONEq(t, `{"score":{"home":1,"away":0}}`, string(msg.Data))
+
+		// A client reading answers the pings and stays connected
+		go websocket.JSON.Receive(ws, &msg)
+		time.Sleep(50 * time.Millisecond)
+
+		assert.True(t, tracker.hub.Followed(mockMatch.ID.Hex()))
+	})
+
+	t.Run("Response Unanswered Pings", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		mockRepo.EXPECT().GetMatch(gomock.Any(), mockMatch.ID.Hex()).Return(&mockMatch, nil)
+
+		// Setup
+		tracker := NewTracker(hub.New(10, 10))
+
+		e := echo.New()
+		h := &Handler{
+			matchRepo: mockRepo,
+			tracker:   tracker,
+			heartbeat: 10 * time.Millisecond,
+		}
+		e.GET("/matches/:id/live/ws", h.GetLiveWebSocket)
+
+		srv := httptest.NewServer(e)
+		defer srv.Close()
+
+		ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/matches/"+mockMatch.ID.Hex()+"/live/ws", "", srv.URL)
+		require.NoError(t, err)
+		defer ws.Close()
+
+		// Assertions, a client not reading answers no ping and is disconnected
+		assert.Eventually(t, func() bool {
+			return !tracker.hub.Followed(mockMatch.ID.Hex())
+		}, time.Second, 10*time.Millisecond)
+
+		var msg hub.Message
+		assert.NoError(t, websocket.JSON.Receive(ws, &msg))
+		assert.Equal(t, model.LiveMatch, msg.Type)
+		assert.Error(t, websocket.JSON.Receive(ws, &msg))
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id/live/ws")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMatch.ID.Hex())
+
+		h := &Handler{
+			matchRepo: mockRepo,
+			tracker:   NewTracker(hub.New(10, 10)),
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatch.ID.Hex()).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetLiveWebSocket(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+}
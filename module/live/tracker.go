@@ -0,0 +1,158 @@
+package live
+
+import (
+	"sync"
+
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/hub"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Tracker publishes what changed in the followed matches to a hub, it implements match.Listener
+type Tracker struct {
+	mu      sync.Mutex
+	hub     *hub.Hub
+	matches map[primitive.ObjectID]model.Match
+}
+
+// NewTracker creates a tracker publishing to a hub
+func NewTracker(h *hub.Hub) *Tracker {
+	return &Tracker{
+		hub:     h,
+		matches: map[primitive.ObjectID]model.Match{},
+	}
+}
+
+// topic returns the topic of a match
+func topic(id primitive.ObjectID) string {
+	return id.Hex()
+}
+
+// over returns whether a match will not change anymore but by correcting it
+func over(m model.Match) bool {
+	return m.Status == model.MatchStatusFinished || m.Status == model.MatchStatusCancelled
+}
+
+// remember stores the last known state of a match while it is followed, or forgets it. The tracker must be locked
+func (t *Tracker) remember(m model.Match) {
+
+	if over(m) || !t.hub.Followed(topic(m.ID)) {
+		delete(t.matches, m.ID)
+		return
+	}
+
+	t.matches[m.ID] = m
+}
+
+// MatchChanged publishes the changes of a derived match since its last known state
+func (t *Tracker) MatchChanged(m model.Match) {
+	// The tracker stays locked while publishing so the messages follow the order of the states
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	name := topic(m.ID)
+
+	prev, ok := t.matches[m.ID]
+	t.remember(m)
+
+	if !t.hub.Kept(name) {
+		return
+	}
+
+	// A forgotten match, e.g. a finished match being corrected, is published as it stands
+	if !ok {
+		t.hub.Publish(name, model.LiveMatch, m)
+		return
+	}
+
+	for _, msg := range diff(prev, m) {
+		t.hub.Publish(name, msg.Type, msg.Data)
+	}
+}
+
+// Subscribe follows a match read from the repository, replaying the messages after the last event id if kept
+func (t *Tracker) Subscribe(m model.Match, lastEventID *uint64) (*hub.Subscription, []hub.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	name := topic(m.ID)
+
+	// The tracker may know a more recent state than the repository had
+	current, ok := t.matches[m.ID]
+	if !ok {
+		current = m
+	}
+
+	if lastEventID != nil {
+		sub, replay, complete := t.hub.SubscribeAfter(name, *lastEventID)
+		if complete {
+			t.remember(current)
+			return sub, replay
+		}
+		sub.Close()
+	}
+
+	// Nothing is published to the topic in between as the tracker is locked
+	snapshot := hub.Message{ID: t.hub.Last(name), Type: model.LiveMatch, Data: current}
+	sub := t.hub.Subscribe(name)
+	t.remember(current)
+
+	return sub, []hub.Message{snapshot}
+}
+
+// Unsubscribe closes the subscription to a match, its state is forgotten once nobody follows it
+func (t *Tracker) Unsubscribe(id primitive.ObjectID, sub *hub.Subscription) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sub.Close()
+
+	if !t.hub.Followed(topic(id)) {
+		delete(t.matches, id)
+	}
+}
+
+// diff returns the messages of the removed and new events, then the score and the status of a derived match
+func diff(prev, cur model.Match) []hub.Message {
+
+	var res []hub.Message
+
+	before := map[primitive.ObjectID]bool{}
+	for _, e := range prev.Events {
+		before[e.ID] = true
+	}
+
+	after := map[primitive.ObjectID]bool{}
+	for _, e := range cur.Events {
+		after[e.ID] = true
+	}
+
+	for _, e := range prev.Events {
+		if !after[e.ID] {
+			res = append(res, hub.Message{Type: model.LiveEventRemoved, Data: model.LiveEventRemovedData{EventID: e.ID.Hex()}})
+		}
+	}
+
+	for _, e := range cur.Events {
+		if !before[e.ID] {
+			res = append(res, hub.Message{Type: model.LiveEvent, Data: e})
+		}
+	}
+
+	if prev.Score != cur.Score || !samePenalties(prev.Penalties, cur.Penalties) {
+		res = append(res, hub.Message{Type: model.LiveScore, Data: model.LiveScoreData{Score: cur.Score, Penalties: cur.Penalties}})
+	}
+
+	if prev.Status != cur.Status {
+		res = append(res, hub.Message{Type: model.LiveStatus, Data: model.LiveStatusData{Status: cur.Status}})
+	}
+
+	return res
+}
+
+func samePenalties(a, b *model.Score) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
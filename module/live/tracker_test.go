@@ -0,0 +1,187 @@
+package live
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/pkg/hub"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// types returns the types of messages
+func types(msgs []hub.Message) []string {
+	res := []string{}
+	for _, msg := range msgs {
+		res = append(res, msg.Type)
+	}
+	return res
+}
+
+func TestDiff(t *testing.T) {
+
+	home := primitive.NewObjectID()
+	player := primitive.NewObjectID()
+
+	goal := model.MatchEvent{ID: primitive.NewObjectID(), Type: model.EventGoal, Minute: 10, TeamID: &home, PlayerID: &player}
+	card := model.MatchEvent{ID: primitive.NewObjectID(), Type: model.EventYellowCard, Minute: 20, TeamID: &home, PlayerID: &player}
+
+	prev := model.Match{HomeTeamID: home, Status: model.MatchStatusLive, Events: []model.MatchEvent{card}}
+	match.Derive(&prev)
+
+	assert.Empty(t, diff(prev, prev))
+
+	cur := prev
+	cur.Events = []model.MatchEvent{goal}
+	match.Derive(&cur)
+
+	res := diff(prev, cur)
+
+	assert.Equal(t, []string{model.LiveEventRemoved, model.LiveEvent, model.LiveScore}, types(res))
+	assert.Equal(t, model.LiveEventRemovedData{EventID: card.ID.Hex()}, res[0].Data)
+	assert.Equal(t, goal, res[1].Data)
+	assert.Equal(t, model.LiveScoreData{Score: model.Score{Home: 1}}, res[2].Data)
+
+	// A shootout changes the score
+	finished := cur
+	finished.Status = model.MatchStatusFinished
+	finished.Penalties = &model.Score{Home: 5, Away: 4}
+
+	res = diff(cur, finished)
+
+	assert.Equal(t, []string{model.LiveScore, model.LiveStatus}, types(res))
+	assert.Equal(t, model.LiveStatusData{Status: model.MatchStatusFinished}, res[1].Data)
+
+	same := finished
+	same.Penalties = &model.Score{Home: 5, Away: 4}
+	assert.Empty(t, diff(finished, same))
+}
+
+func TestTracker(t *testing.T) {
+
+	h := hub.New(10, 10)
+	tracker := NewTracker(h)
+
+	m := model.Match{ID: primitive.NewObjectID(), Status: model.MatchStatusScheduled}
+	match.Derive(&m)
+
+	// Nothing is published nor kept while nobody follows the match
+	live := m
+	live.Status = model.MatchStatusLive
+	tracker.MatchChanged(live)
+
+	assert.Equal(t, uint64(0), h.Last(topic(m.ID)))
+	assert.Empty(t, tracker.matches)
+
+	sub, msgs := tracker.Subscribe(live, nil)
+	assert.Len(t, tracker.matches, 1)
+
+	if assert.Len(t, msgs, 1) {
+		assert.Equal(t, hub.Message{ID: 0, Type: model.LiveMatch, Data: live}, msgs[0])
+	}
+
+	finished := live
+	finished.Status = model.MatchStatusFinished
+	tracker.MatchChanged(finished)
+
+	msg := <-sub.C
+	assert.Equal(t, uint64(1), msg.ID)
+	assert.Equal(t, model.LiveStatus, msg.Type)
+
+	// A finished match is forgotten
+	assert.Empty(t, tracker.matches)
+
+	// A client coming back replays what it missed
+	_, msgs = tracker.Subscribe(finished, &msgs[0].ID)
+	assert.Equal(t, []hub.Message{msg}, msgs)
+
+	_, msgs = tracker.Subscribe(finished, &msg.ID)
+	assert.Empty(t, msgs)
+
+	// Or gets the match as it stands when the id is unknown
+	unknown := uint64(42)
+	_, msgs = tracker.Subscribe(finished, &unknown)
+
+	if assert.Len(t, msgs, 1) {
+		assert.Equal(t, hub.Message{ID: 1, Type: model.LiveMatch, Data: finished}, msgs[0])
+	}
+	assert.Empty(t, tracker.matches)
+
+	// A forgotten match being corrected is published as it stands
+	corrected := finished
+	corrected.Score = model.Score{Home: 1}
+	tracker.MatchChanged(corrected)
+
+	assert.Equal(t, hub.Message{ID: 2, Type: model.LiveMatch, Data: corrected}, <-sub.C)
+	assert.Empty(t, tracker.matches)
+
+	// Unless nobody follows it
+	other := model.Match{ID: primitive.NewObjectID(), Status: model.MatchStatusCancelled}
+	tracker.MatchChanged(other)
+
+	assert.Equal(t, uint64(2), h.Last(topic(other.ID)))
+	assert.Empty(t, tracker.matches)
+}
+
+func TestTrackerUnsubscribe(t *testing.T) {
+
+	h := hub.New(10, 10)
+	tracker := NewTracker(h)
+
+	m := model.Match{ID: primitive.NewObjectID(), Status: model.MatchStatusLive}
+	match.Derive(&m)
+
+	a, _ := tracker.Subscribe(m, nil)
+	b, msgs := tracker.Subscribe(m, nil)
+
+	// The match is kept while it is followed
+	tracker.Unsubscribe(m.ID, a)
+	assert.Len(t, tracker.matches, 1)
+
+	tracker.Unsubscribe(m.ID, b)
+	assert.Empty(t, tracker.matches)
+
+	// A client coming back replays the match as it stands
+	goal := m
+	goal.Score = model.Score{Home: 1}
+	tracker.MatchChanged(goal)
+
+	assert.Empty(t, tracker.matches)
+
+	_, replay := tracker.Subscribe(goal, &msgs[0].ID)
+	assert.Equal(t, []hub.Message{{ID: 1, Type: model.LiveMatch, Data: goal}}, replay)
+	assert.Equal(t, goal, tracker.matches[m.ID])
+}
+
+func TestTrackerConcurrency(t *testing.T) {
+
+	h := hub.New(100, 100)
+	tracker := NewTracker(h)
+
+	m := model.Match{ID: primitive.NewObjectID(), Status: model.MatchStatusLive}
+	sub, _ := tracker.Subscribe(m, nil)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cur := m
+			cur.Score = model.Score{Home: i}
+			tracker.MatchChanged(cur)
+		}(i)
+	}
+	wg.Wait()
+	sub.Close()
+
+	// The messages follow the order of the states, the last one is the state the tracker knows
+	var last hub.Message
+	for msg := range sub.C {
+		assert.Equal(t, last.ID+1, msg.ID)
+		last = msg
+	}
+
+	assert.Equal(t, model.LiveScoreData{Score: tracker.matches[m.ID].Score}, last.Data)
+}
@@ -0,0 +1,228 @@
+package match
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/boltdb"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/storage"
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type boltRepository struct {
+	db *bolt.DB
+}
+
+// NewBoltRepository creates a new match repository stored in a boltdb file
+func NewBoltRepository(db *bolt.DB) Repository {
+	return &boltRepository{db}
+}
+
+// ListMatch returns a filtered and sorted page of matches, of a team if teamID is not empty
+func (repo *boltRepository) ListMatch(ctx context.Context, teamID string, params query.Params) ([]model.Match, *query.Page, error) {
+	op := "match.Repository.ListMatch"
+
+	all := map[primitive.ObjectID]model.Match{}
+
+	err := repo.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltdb.MatchesBucket).ForEach(func(k, v []byte) error {
+			var m model.Match
+			if err := json.Unmarshal(v, &m); err != nil {
+				return err
+			}
+			all[m.ID] = m
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+
+	items, page, err := paginate(filterMatches(all, teamID, params), params, len(all), len(teamID) > 0)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+
+	return items, page, nil
+}
+
+// GetMatch returns a match by id
+func (repo *boltRepository) GetMatch(ctx context.Context, id string) (*model.Match, error) {
+	op := "match.Repository.GetMatch"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	var data *model.Match
+
+	err := repo.db.View(func(tx *bolt.Tx) error {
+		var m model.Match
+		found, err := boltdb.Get(tx.Bucket(boltdb.MatchesBucket), oid, &m)
+		if found {
+			Derive(&m)
+			data = &m
+		}
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return data, nil
+}
+
+// CreateMatch creates a new match
+func (repo *boltRepository) CreateMatch(ctx context.Context, data model.Match) (*model.Match, error) {
+	op := "match.Repository.CreateMatch"
+
+	data.ID = primitive.NewObjectID()
+	data.CreatedAt = storage.Now()
+	data.Events = []model.MatchEvent{}
+
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		return boltdb.Put(tx.Bucket(boltdb.MatchesBucket), data.ID, data)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	Derive(&data)
+
+	return &data, nil
+}
+
+// DeleteMatch deletes a match and returns the deleted match
+func (repo *boltRepository) DeleteMatch(ctx context.Context, id string) (*model.Match, error) {
+	op := "match.Repository.DeleteMatch"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	var data *model.Match
+
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltdb.MatchesBucket)
+
+		var m model.Match
+		found, err := boltdb.Get(b, oid, &m)
+		if err != nil || !found {
+			return err
+		}
+		Derive(&m)
+		data = &m
+
+		return b.Delete(oid[:])
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return data, nil
+}
+
+// update applies fn to a stored match, it returns nil if the match does not exist
+func (repo *boltRepository) update(id string, fn func(m *model.Match) error) (*model.Match, error) {
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	var data *model.Match
+
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltdb.MatchesBucket)
+
+		var m model.Match
+		found, err := boltdb.Get(b, oid, &m)
+		if err != nil || !found {
+			return err
+		}
+
+		if err = fn(&m); err != nil {
+			return err
+		}
+		data = &m
+
+		return boltdb.Put(b, oid, m)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if data != nil {
+		Derive(data)
+	}
+
+	return data, nil
+}
+
+// PatchMatch applies a merge patch to a match, null values remove the field
+func (repo *boltRepository) PatchMatch(ctx context.Context, id string, patch map[string]interface{}) (*model.Match, error) {
+	op := "match.Repository.PatchMatch"
+
+	res, err := repo.update(id, func(m *model.Match) error {
+		applyPatch(m, patch)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return res, nil
+}
+
+// AddEvent appends an event to a match
+func (repo *boltRepository) AddEvent(ctx context.Context, id string, data model.MatchEvent) (*model.Match, error) {
+	op := "match.Repository.AddEvent"
+
+	data.ID = primitive.NewObjectID()
+
+	res, err := repo.update(id, func(m *model.Match) error {
+		m.Events = append(m.Events, data)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return res, nil
+}
+
+// RemoveEvent removes an event from a match
+func (repo *boltRepository) RemoveEvent(ctx context.Context, id string, eventID string) (*model.Match, error) {
+	op := "match.Repository.RemoveEvent"
+
+	res, err := repo.update(id, func(m *model.Match) error {
+		events, removed := removeEvent(m.Events, eventID)
+		if !removed {
+			return ErrEventNotFound
+		}
+		m.Events = events
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return res, nil
+}
+
+// SetLineups replaces the lineups of a match
+func (repo *boltRepository) SetLineups(ctx context.Context, id string, data model.Lineups) (*model.Match, error) {
+	op := "match.Repository.SetLineups"
+
+	res, err := repo.update(id, func(m *model.Match) error {
+		m.Lineups = &data
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return res, nil
+}
+
+// EnsureIndexes does nothing, the bucket is created by the schema migrations
+func (repo *boltRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,85 @@
+package match
+
+import (
+	"sort"
+
+	"github.com/yezarela/go-soccer/model"
+)
+
+// Derive sorts the events of a match and sets its score from the goals not overturned by the VAR
+func Derive(m *model.Match) {
+
+	if m.Events == nil {
+		m.Events = []model.MatchEvent{}
+	}
+
+	sort.SliceStable(m.Events, func(i, j int) bool {
+		a, b := m.Events[i], m.Events[j]
+		if a.Minute != b.Minute {
+			return a.Minute < b.Minute
+		}
+		if a.AddedTime != b.AddedTime {
+			return a.AddedTime < b.AddedTime
+		}
+		return a.ID.Hex() < b.ID.Hex()
+	})
+
+	overturned := map[string]bool{}
+	for _, e := range m.Events {
+		if e.Type == model.EventVAR && e.EventID != nil {
+			overturned[e.EventID.Hex()] = true
+		}
+	}
+
+	m.Score = model.Score{}
+
+	for _, e := range m.Events {
+		if overturned[e.ID.Hex()] || e.TeamID == nil {
+			continue
+		}
+
+		home := *e.TeamID == m.HomeTeamID
+
+		switch e.Type {
+		case model.EventGoal, model.EventPenalty:
+		case model.EventOwnGoal:
+			home = !home
+		default:
+			continue
+		}
+
+		if home {
+			m.Score.Home++
+		} else {
+			m.Score.Away++
+		}
+	}
+}
+
+// isGoal returns whether an event type changes the score
+func isGoal(typ string) bool {
+	return typ == model.EventGoal || typ == model.EventOwnGoal || typ == model.EventPenalty
+}
+
+// findEvent returns the event of a match by id
+func findEvent(m model.Match, id string) (model.MatchEvent, bool) {
+	for _, e := range m.Events {
+		if e.ID.Hex() == id {
+			return e, true
+		}
+	}
+	return model.MatchEvent{}, false
+}
+
+// removeEvent returns a copy of events without the event of an id, and whether it was found
+func removeEvent(events []model.MatchEvent, id string) ([]model.MatchEvent, bool) {
+
+	res := []model.MatchEvent{}
+	for _, e := range events {
+		if e.ID.Hex() != id {
+			res = append(res, e)
+		}
+	}
+
+	return res, len(res) != len(events)
+}
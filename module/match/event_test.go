@@ -0,0 +1,66 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDerive(t *testing.T) {
+
+	home := primitive.NewObjectID()
+	away := primitive.NewObjectID()
+
+	event := func(typ string, minute, addedTime int, teamID primitive.ObjectID) model.MatchEvent {
+		return model.MatchEvent{ID: primitive.NewObjectID(), Type: typ, Minute: minute, AddedTime: addedTime, TeamID: &teamID}
+	}
+
+	t.Run("No Events", func(t *testing.T) {
+
+		m := model.Match{HomeTeamID: home, AwayTeamID: away}
+		Derive(&m)
+
+		assert.Equal(t, model.Score{}, m.Score)
+		assert.NotNil(t, m.Events)
+	})
+
+	t.Run("Goals", func(t *testing.T) {
+
+		m := model.Match{HomeTeamID: home, AwayTeamID: away, Events: []model.MatchEvent{
+			event(model.EventGoal, 10, 0, home),
+			event(model.EventPenalty, 30, 0, away),
+			event(model.EventOwnGoal, 50, 0, away),
+			event(model.EventYellowCard, 60, 0, home),
+			event(model.EventRedCard, 61, 0, away),
+		}}
+		Derive(&m)
+
+		// The own goal counts for the home team
+		assert.Equal(t, model.Score{Home: 2, Away: 1}, m.Score)
+	})
+
+	t.Run("Goal Overturned By VAR", func(t *testing.T) {
+
+		goal := event(model.EventGoal, 90, 3, away)
+		decision := model.MatchEvent{ID: primitive.NewObjectID(), Type: model.EventVAR, Minute: 90, AddedTime: 5, EventID: &goal.ID, Detail: "offside"}
+
+		m := model.Match{HomeTeamID: home, AwayTeamID: away, Events: []model.MatchEvent{decision, goal}}
+		Derive(&m)
+
+		assert.Equal(t, model.Score{}, m.Score)
+	})
+
+	t.Run("Events By Time", func(t *testing.T) {
+
+		first := event(model.EventGoal, 45, 0, home)
+		second := event(model.EventGoal, 45, 2, home)
+		third := event(model.EventGoal, 46, 0, home)
+
+		m := model.Match{HomeTeamID: home, AwayTeamID: away, Events: []model.MatchEvent{third, second, first}}
+		Derive(&m)
+
+		assert.Equal(t, []model.MatchEvent{first, second, third}, m.Events)
+	})
+}
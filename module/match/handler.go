@@ -0,0 +1,670 @@
+package match
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/competition"
+	"github.com/yezarela/go-soccer/module/team"
+	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/groups"
+	"github.com/yezarela/go-soccer/pkg/knockout"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// eventBody represents the payload to record a match event, its team is the team of the player
+type eventBody struct {
+	Type       string `json:"type"`
+	Minute     int    `json:"minute"`
+	AddedTime  int    `json:"added_time"`
+	PlayerID   string `json:"player_id"`
+	PlayerInID string `json:"player_in_id"`
+	AssistID   string `json:"assist_id"`
+	EventID    string `json:"event_id"`
+	Detail     string `json:"detail"`
+}
+
+// Listener is notified after a match is created or changed, e.g. to invalidate what is computed from it
+type Listener interface {
+	MatchChanged(m model.Match)
+}
+
+// Handler represents the httphandler for match
+type Handler struct {
+	matchRepo       Repository
+	teamRepo        team.Repository
+	competitionRepo competition.Repository
+	listeners       []Listener
+}
+
+// NewHandler initializes endpoints for match
+func NewHandler(e *echo.Echo, matchRepo Repository, teamRepo team.Repository, competitionRepo competition.Repository, listeners ...Listener) {
+	handler := &Handler{
+		matchRepo:       matchRepo,
+		teamRepo:        teamRepo,
+		competitionRepo: competitionRepo,
+		listeners:       listeners,
+	}
+
+	e.GET("/matches", handler.GetAll)
+	e.POST("/matches", handler.Post)
+	e.GET("/matches/:id", handler.GetByID)
+	e.PATCH("/matches/:id", handler.Patch)
+	e.GET("/matches/:id/events", handler.GetEvents)
+	e.POST("/matches/:id/events", handler.PostEvent)
+	e.DELETE("/matches/:id/events/:eventId", handler.DeleteEvent)
+	e.GET("/matches/:id/lineups", handler.GetLineups)
+	e.PUT("/matches/:id/lineups", handler.PutLineups)
+}
+
+// GetAll returns a page of matches, team_id returns the matches of a team on both sides
+func (h *Handler) GetAll(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	values := url.Values{}
+	for k, v := range c.QueryParams() {
+		values[k] = v
+	}
+
+	teamID := values.Get("team_id")
+	delete(values, "team_id")
+
+	if len(teamID) > 0 {
+		if _, err := primitive.ObjectIDFromHex(teamID); err != nil {
+			return api.ResponseBadRequest(c, "invalid value of filter team_id")
+		}
+	}
+
+	params, err := query.ParseParams(values, Fields)
+	if err != nil {
+		return api.ResponseBadRequest(c, err.Error())
+	}
+
+	res, page, err := h.matchRepo.ListMatch(ctx, teamID, params)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	return api.ResponsePage(c, res, page)
+}
+
+// GetByID returns a match by id
+func (h *Handler) GetByID(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	res, err := h.matchRepo.GetMatch(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested match")
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// Post schedules a new match between two existing teams, of a competition they take part in if competition_id is set
+func (h *Handler) Post(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	var body model.Match
+	err := c.Bind(&body)
+	if err != nil {
+		return api.ResponseUnprocessableEntity(c, "invalid body")
+	}
+
+	if len(body.Status) <= 0 {
+		body.Status = model.MatchStatusScheduled
+	}
+
+	// The score is derived from the events which are recorded once the match started
+	body.Score = model.Score{}
+	body.Events = nil
+	body.Penalties = nil
+
+	if msg := validateMatch(body); len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
+	}
+
+	// Store the precision of mongodb whatever the storage
+	body.Kickoff = body.Kickoff.UTC().Truncate(time.Millisecond)
+
+	home, err := h.teamRepo.GetTeam(ctx, body.HomeTeamID.Hex())
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+	if home == nil {
+		return api.ResponseNotFound(c, "cannot find the home team")
+	}
+
+	away, err := h.teamRepo.GetTeam(ctx, body.AwayTeamID.Hex())
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+	if away == nil {
+		return api.ResponseNotFound(c, "cannot find the away team")
+	}
+
+	if body.CompetitionID != nil {
+		comp, err := h.competitionRepo.GetCompetition(ctx, body.CompetitionID.Hex())
+		if err != nil {
+			return api.ResponseError(c, err)
+		}
+		if comp == nil {
+			return api.ResponseNotFound(c, "cannot find the competition")
+		}
+		if !takesPart(*comp, body.HomeTeamID) || !takesPart(*comp, body.AwayTeamID) {
+			return api.ResponseBadRequest(c, "both teams must take part in the competition")
+		}
+
+		// The matches of a knockout are the legs of the ties of its bracket,
+		// a group stage plays the matches of its groups first
+		if comp.IsKnockout() || comp.HasGroups() {
+			matches, err := ListAll(ctx, h.matchRepo, "", url.Values{"competition_id": {comp.ID.Hex()}})
+			if err != nil {
+				return api.ResponseError(c, err)
+			}
+
+			if !comp.HasGroups() || !groups.Pending(*comp.GroupStage, matches, body.HomeTeamID, body.AwayTeamID) {
+				tie := tieOf(*comp, matches, body.HomeTeamID, body.AwayTeamID)
+				if tie == nil || tie.WinnerID != nil {
+					return api.ResponseBadRequest(c, "the teams do not play an open tie of the bracket")
+				}
+				if len(tie.MatchIDs) >= tie.Legs {
+					return api.ResponseConflict(c, "all the legs of the tie are scheduled")
+				}
+			}
+		}
+	}
+
+	res, err := h.matchRepo.CreateMatch(ctx, body)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	h.notify(res)
+
+	return api.ResponseCreated(c, res)
+}
+
+// Patch partially updates a match by id using JSON merge patch
+func (h *Handler) Patch(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	var body map[string]interface{}
+	err := json.NewDecoder(c.Request().Body).Decode(&body)
+	if err != nil || body == nil {
+		return api.ResponseUnprocessableEntity(c, "invalid body")
+	}
+
+	patch, msg := parseMatchPatch(body)
+	if len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
+	}
+
+	res, err := h.matchRepo.PatchMatch(ctx, c.Param("id"), patch)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested match")
+	}
+
+	h.notify(res)
+
+	return api.ResponseOK(c, res)
+}
+
+// GetEvents returns the events of a match by time
+func (h *Handler) GetEvents(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	res, err := h.matchRepo.GetMatch(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested match")
+	}
+
+	return api.ResponseOK(c, res.Events)
+}
+
+// PostEvent records an event of a live or finished match and returns the match with its new score
+func (h *Handler) PostEvent(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	// Bind would try to bind the id path param into the body
+	var body eventBody
+	err := json.NewDecoder(c.Request().Body).Decode(&body)
+	if err != nil {
+		return api.ResponseUnprocessableEntity(c, "invalid body")
+	}
+
+	event, msg := parseEvent(body)
+	if len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
+	}
+
+	m, err := h.matchRepo.GetMatch(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if m == nil {
+		return api.ResponseNotFound(c, "cannot find the requested match")
+	}
+
+	if m.Status != model.MatchStatusLive && m.Status != model.MatchStatusFinished {
+		return api.ResponseConflict(c, "events can only be recorded in live or finished matches")
+	}
+
+	if event.PlayerID != nil {
+		event.TeamID, err = h.teamOf(ctx, *m, *event.PlayerID)
+		if err != nil {
+			return api.ResponseError(c, err)
+		}
+		if event.TeamID == nil {
+			return api.ResponseBadRequest(c, "player_id does not play for the teams of the match")
+		}
+	}
+
+	if event.PlayerInID != nil {
+		teamID, err := h.teamOf(ctx, *m, *event.PlayerInID)
+		if err != nil {
+			return api.ResponseError(c, err)
+		}
+		if teamID == nil || *teamID != *event.TeamID {
+			return api.ResponseBadRequest(c, "player_in_id does not play for the team of player_id")
+		}
+	}
+
+	if event.AssistID != nil {
+		teamID, err := h.teamOf(ctx, *m, *event.AssistID)
+		if err != nil {
+			return api.ResponseError(c, err)
+		}
+		if teamID == nil || *teamID != *event.TeamID {
+			return api.ResponseBadRequest(c, "assist_id does not play for the team of player_id")
+		}
+	}
+
+	if event.EventID != nil {
+		if e, ok := findEvent(*m, event.EventID.Hex()); !ok || !isGoal(e.Type) {
+			return api.ResponseBadRequest(c, "event_id is not a goal of the match")
+		}
+	}
+
+	res, err := h.matchRepo.AddEvent(ctx, m.ID.Hex(), event)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested match")
+	}
+
+	h.notify(res)
+
+	return api.ResponseCreated(c, res)
+}
+
+// DeleteEvent removes an event of a match and returns the match with its new score
+func (h *Handler) DeleteEvent(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	res, err := h.matchRepo.RemoveEvent(ctx, c.Param("id"), c.Param("eventId"))
+	if err != nil {
+		if errors.Cause(err) == ErrEventNotFound {
+			return api.ResponseNotFound(c, ErrEventNotFound.Error())
+		}
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested match")
+	}
+
+	h.notify(res)
+
+	return api.ResponseOK(c, res)
+}
+
+// GetLineups returns the lineups of a match, a side is null until its lineup is announced
+func (h *Handler) GetLineups(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	res, err := h.matchRepo.GetMatch(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested match")
+	}
+
+	if res.Lineups == nil {
+		return api.ResponseOK(c, model.Lineups{})
+	}
+
+	return api.ResponseOK(c, res.Lineups)
+}
+
+// PutLineups replaces the lineups of a match sent in the body, each lineup is validated against the roster of its team
+func (h *Handler) PutLineups(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	// Bind would try to bind the id path param into the body
+	var body model.Lineups
+	err := json.NewDecoder(c.Request().Body).Decode(&body)
+	if err != nil {
+		return api.ResponseUnprocessableEntity(c, "invalid body")
+	}
+
+	m, err := h.matchRepo.GetMatch(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if m == nil {
+		return api.ResponseNotFound(c, "cannot find the requested match")
+	}
+
+	if m.Status == model.MatchStatusCancelled {
+		return api.ResponseConflict(c, "a cancelled match has no lineups")
+	}
+
+	current := model.Lineups{}
+	if m.Lineups != nil {
+		current = *m.Lineups
+	}
+
+	sides := []struct {
+		name    string
+		teamID  primitive.ObjectID
+		lineup  **model.Lineup
+		current *model.Lineup
+	}{
+		{"home", m.HomeTeamID, &body.Home, current.Home},
+		{"away", m.AwayTeamID, &body.Away, current.Away},
+	}
+
+	for _, side := range sides {
+		// A side left out keeps its lineup, the whole lineups are saved
+		if *side.lineup == nil {
+			*side.lineup = side.current
+			continue
+		}
+
+		t, err := h.teamRepo.GetTeam(ctx, side.teamID.Hex())
+		if err != nil {
+			return api.ResponseError(c, err)
+		}
+		if t == nil {
+			return api.ResponseNotFound(c, "cannot find the "+side.name+" team")
+		}
+
+		l, msg := buildLineup(**side.lineup, *t)
+		if len(msg) > 0 {
+			return api.ResponseBadRequest(c, side.name+" lineup: "+msg)
+		}
+		*side.lineup = &l
+	}
+
+	res, err := h.matchRepo.SetLineups(ctx, m.ID.Hex(), body)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested match")
+	}
+
+	h.notify(res)
+
+	return api.ResponseOK(c, res.Lineups)
+}
+
+// notify tells the listeners a match changed
+func (h *Handler) notify(m *model.Match) {
+
+	if m == nil {
+		return
+	}
+
+	for _, l := range h.listeners {
+		l.MatchChanged(*m)
+	}
+}
+
+// tieOf returns the tie of the bracket of a knockout or a group stage between two teams, nil if they do not meet
+func tieOf(comp model.Competition, matches []model.Match, a, b primitive.ObjectID) *model.Tie {
+
+	if comp.HasGroups() {
+		return knockout.Find(groups.Bracket(comp, matches), a, b)
+	}
+
+	final := knockout.Build(comp.TeamIDs, matches, knockout.Rules(comp))
+
+	return knockout.Find(final, a, b)
+}
+
+// teamOf returns the id of the team of a match the player plays for, nil if none
+func (h *Handler) teamOf(ctx context.Context, m model.Match, playerID primitive.ObjectID) (*primitive.ObjectID, error) {
+
+	for _, id := range []primitive.ObjectID{m.HomeTeamID, m.AwayTeamID} {
+		t, err := h.teamRepo.GetTeam(ctx, id.Hex())
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			continue
+		}
+
+		for _, p := range t.Players {
+			if p.ID == playerID {
+				teamID := t.ID
+				return &teamID, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// validateMatch returns the validation message of a match, empty if valid
+func validateMatch(body model.Match) string {
+
+	if body.HomeTeamID.IsZero() {
+		return "home_team_id cannot be empty"
+	}
+	if body.AwayTeamID.IsZero() {
+		return "away_team_id cannot be empty"
+	}
+	if body.HomeTeamID == body.AwayTeamID {
+		return "a team cannot play against itself"
+	}
+	if body.Kickoff.IsZero() {
+		return "kickoff cannot be empty"
+	}
+	if !validStatus(body.Status) {
+		return "unknown status " + body.Status
+	}
+
+	return ""
+}
+
+// takesPart returns whether a team takes part in a competition
+func takesPart(comp model.Competition, teamID primitive.ObjectID) bool {
+	for _, id := range comp.TeamIDs {
+		if id == teamID {
+			return true
+		}
+	}
+	return false
+}
+
+func validStatus(status string) bool {
+	for _, s := range model.MatchStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// parseMatchPatch validates a match merge patch and returns it with typed values
+func parseMatchPatch(body map[string]interface{}) (map[string]interface{}, string) {
+
+	patch := map[string]interface{}{}
+
+	for k, v := range body {
+		switch k {
+		case "kickoff":
+			s, _ := v.(string)
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, k + " must be a RFC 3339 timestamp"
+			}
+			patch[k] = t.UTC().Truncate(time.Millisecond)
+		case "venue":
+			if _, ok := v.(string); !ok && v != nil {
+				return nil, k + " must be a string"
+			}
+			patch[k] = v
+		case "status":
+			if s, ok := v.(string); !ok || !validStatus(s) {
+				return nil, "unknown status"
+			}
+			patch[k] = v
+		case "penalties":
+			if v == nil {
+				patch[k] = nil
+				continue
+			}
+			p, ok := parseScore(v)
+			if !ok {
+				return nil, k + " must be an object of home and away goals"
+			}
+			if p.Home == p.Away {
+				return nil, "a penalty shootout has a winner"
+			}
+			patch[k] = p
+		default:
+			return nil, k + " cannot be patched"
+		}
+	}
+
+	return patch, ""
+}
+
+// parseScore parses a score object with non-negative home and away goals
+func parseScore(v interface{}) (*model.Score, bool) {
+
+	obj, ok := v.(map[string]interface{})
+	if !ok || len(obj) != 2 {
+		return nil, false
+	}
+
+	home, ok1 := obj["home"].(float64)
+	away, ok2 := obj["away"].(float64)
+	if !ok1 || !ok2 || home < 0 || away < 0 || home != float64(int(home)) || away != float64(int(away)) {
+		return nil, false
+	}
+
+	return &model.Score{Home: int(home), Away: int(away)}, true
+}
+
+// parseEvent validates the body of an event and returns the event without its team
+func parseEvent(body eventBody) (model.MatchEvent, string) {
+
+	event := model.MatchEvent{
+		Type:      body.Type,
+		Minute:    body.Minute,
+		AddedTime: body.AddedTime,
+		Detail:    body.Detail,
+	}
+
+	known := false
+	for _, typ := range model.EventTypes {
+		known = known || typ == body.Type
+	}
+	if !known {
+		return event, "unknown type " + body.Type
+	}
+
+	if body.Minute < 1 || body.Minute > 120 {
+		return event, "minute must be between 1 and 120"
+	}
+	if body.AddedTime < 0 || body.AddedTime > 30 {
+		return event, "added_time must be between 0 and 30"
+	}
+
+	ids := []struct {
+		name     string
+		value    string
+		required bool
+		dst      **primitive.ObjectID
+	}{
+		{"player_id", body.PlayerID, body.Type != model.EventVAR, &event.PlayerID},
+		{"player_in_id", body.PlayerInID, body.Type == model.EventSubstitution, &event.PlayerInID},
+		{"assist_id", body.AssistID, false, &event.AssistID},
+		{"event_id", body.EventID, false, &event.EventID},
+	}
+
+	for _, id := range ids {
+		if len(id.value) <= 0 {
+			if id.required {
+				return event, id.name + " cannot be empty"
+			}
+			continue
+		}
+
+		oid, err := primitive.ObjectIDFromHex(id.value)
+		if err != nil {
+			return event, "invalid " + id.name
+		}
+		*id.dst = &oid
+	}
+
+	if event.PlayerInID != nil && body.Type != model.EventSubstitution {
+		return event, "player_in_id is only allowed in substitutions"
+	}
+	if event.PlayerInID != nil && *event.PlayerInID == *event.PlayerID {
+		return event, "a player cannot replace itself"
+	}
+	if event.AssistID != nil && body.Type != model.EventGoal {
+		return event, "assist_id is only allowed in goals"
+	}
+	if event.AssistID != nil && *event.AssistID == *event.PlayerID {
+		return event, "a player cannot assist itself"
+	}
+
+	if body.Type == model.EventVAR {
+		if len(body.Detail) <= 0 {
+			return event, "detail cannot be empty"
+		}
+	} else if event.EventID != nil {
+		return event, "event_id is only allowed in var decisions"
+	}
+
+	return event, ""
+}
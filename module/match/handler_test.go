@@ -0,0 +1,1295 @@
+package match
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	competitionMock "github.com/yezarela/go-soccer/module/competition/mock"
+	matchMock "github.com/yezarela/go-soccer/module/match/mock"
+	teamMock "github.com/yezarela/go-soccer/module/team/mock"
+	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/table"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetByID(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockMatch,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMatch.ID.Hex())
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatch.ID.Hex()).Return(&mockMatch, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetByID(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockMatchID := "5f6a5d6129b2289c40b7444b"
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMatchID)
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatchID).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetByID(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+}
+
+func TestPost(t *testing.T) {
+
+	t.Run("Response Created", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		mockHome := model.Team{}
+		mockHome.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockAway := model.Team{}
+		mockAway.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444c")
+
+		mockMatch := model.Match{}
+		mockMatch.HomeTeamID = mockHome.ID
+		mockMatch.AwayTeamID = mockAway.ID
+		mockMatch.Kickoff = time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC)
+		mockMatch.Venue = "San Siro"
+
+		mockPayload, _ := json.Marshal(mockMatch)
+
+		// The status defaults to scheduled
+		mockMatch.Status = model.MatchStatusScheduled
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusCreated,
+			},
+			Data: mockMatch,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/matches", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			matchRepo: mockRepo,
+			teamRepo:  mockTeamRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockHome.ID.Hex()).Return(&mockHome, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockAway.ID.Hex()).Return(&mockAway, nil)
+		mockRepo.EXPECT().CreateMatch(ctx, mockMatch).Return(&mockMatch, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Post(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		mockHome := model.Team{}
+		mockHome.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+
+		mockMatch := model.Match{}
+		mockMatch.HomeTeamID = mockHome.ID
+		mockMatch.AwayTeamID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444c")
+		mockMatch.Kickoff = time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC)
+
+		mockPayload, _ := json.Marshal(mockMatch)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/matches", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			matchRepo: mockRepo,
+			teamRepo:  mockTeamRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockHome.ID.Hex()).Return(&mockHome, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockMatch.AwayTeamID.Hex()).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Post(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.HomeTeamID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockMatch.AwayTeamID = mockMatch.HomeTeamID
+		mockMatch.Kickoff = time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC)
+
+		mockPayload, _ := json.Marshal(mockMatch)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/matches", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			matchRepo: mockRepo,
+			teamRepo:  mockTeamRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.Post(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Response Created In Competition", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockCompetitionRepo := competitionMock.NewMockRepository(ctrl)
+
+		mockHome := model.Team{}
+		mockHome.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockAway := model.Team{}
+		mockAway.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444c")
+
+		mockCompetition := model.Competition{}
+		mockCompetition.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444d")
+		mockCompetition.TeamIDs = []primitive.ObjectID{mockHome.ID, mockAway.ID}
+
+		mockMatch := model.Match{}
+		mockMatch.CompetitionID = &mockCompetition.ID
+		mockMatch.HomeTeamID = mockHome.ID
+		mockMatch.AwayTeamID = mockAway.ID
+		mockMatch.Kickoff = time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC)
+		mockMatch.Status = model.MatchStatusScheduled
+
+		mockPayload, _ := json.Marshal(mockMatch)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/matches", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		listener := &recorder{}
+
+		h := &Handler{
+			matchRepo:       mockRepo,
+			teamRepo:        mockTeamRepo,
+			competitionRepo: mockCompetitionRepo,
+			listeners:       []Listener{listener},
+		}
+
+		ctx := c.Request().Context()
+
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockHome.ID.Hex()).Return(&mockHome, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockAway.ID.Hex()).Return(&mockAway, nil)
+		mockCompetitionRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&mockCompetition, nil)
+		mockRepo.EXPECT().CreateMatch(ctx, mockMatch).Return(&mockMatch, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Post(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, []model.Match{mockMatch}, listener.changed)
+		}
+	})
+
+	t.Run("Response Bad Request Team Not In Competition", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockCompetitionRepo := competitionMock.NewMockRepository(ctrl)
+
+		mockHome := model.Team{}
+		mockHome.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockAway := model.Team{}
+		mockAway.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444c")
+
+		mockCompetition := model.Competition{}
+		mockCompetition.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444d")
+		mockCompetition.TeamIDs = []primitive.ObjectID{mockHome.ID}
+
+		mockMatch := model.Match{}
+		mockMatch.CompetitionID = &mockCompetition.ID
+		mockMatch.HomeTeamID = mockHome.ID
+		mockMatch.AwayTeamID = mockAway.ID
+		mockMatch.Kickoff = time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC)
+
+		mockPayload, _ := json.Marshal(mockMatch)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/matches", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			matchRepo:       mockRepo,
+			teamRepo:        mockTeamRepo,
+			competitionRepo: mockCompetitionRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockHome.ID.Hex()).Return(&mockHome, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockAway.ID.Hex()).Return(&mockAway, nil)
+		mockCompetitionRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&mockCompetition, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Post(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+}
+
+func TestPostKnockout(t *testing.T) {
+
+	mockTeams := []model.Team{{}, {}, {}}
+	mockTeams[0].ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444a")
+	mockTeams[1].ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+	mockTeams[2].ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444c")
+
+	// The first seed gets a bye to the final
+	mockCompetition := model.Competition{}
+	mockCompetition.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444d")
+	mockCompetition.Type = model.CompetitionTypeKnockout
+	mockCompetition.TeamIDs = []primitive.ObjectID{mockTeams[0].ID, mockTeams[1].ID, mockTeams[2].ID}
+	mockCompetition.Knockout = &model.KnockoutRules{Legs: 1}
+
+	post := func(t *testing.T, home, away model.Team, existing []model.Match) *httptest.ResponseRecorder {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockCompetitionRepo := competitionMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.CompetitionID = &mockCompetition.ID
+		mockMatch.HomeTeamID = home.ID
+		mockMatch.AwayTeamID = away.ID
+		mockMatch.Kickoff = time.Date(2021, 1, 13, 20, 45, 0, 0, time.UTC)
+		mockMatch.Status = model.MatchStatusScheduled
+
+		mockPayload, _ := json.Marshal(mockMatch)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/matches", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			matchRepo:       mockRepo,
+			teamRepo:        mockTeamRepo,
+			competitionRepo: mockCompetitionRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockTeamRepo.EXPECT().GetTeam(ctx, home.ID.Hex()).Return(&home, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, away.ID.Hex()).Return(&away, nil)
+		mockCompetitionRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&mockCompetition, nil)
+		mockRepo.EXPECT().ListMatch(ctx, "", gomock.Any()).Return(existing, &query.Page{}, nil)
+		mockRepo.EXPECT().CreateMatch(ctx, mockMatch).Return(&mockMatch, nil).MaxTimes(1)
+
+		assert.NoError(t, h.Post(c))
+
+		return rec
+	}
+
+	t.Run("Response Created", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, post(t, mockTeams[2], mockTeams[1], nil).Code)
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// The first seed waits for the winner of the semi final
+		assert.Equal(t, http.StatusBadRequest, post(t, mockTeams[0], mockTeams[1], nil).Code)
+	})
+
+	t.Run("Response Conflict", func(t *testing.T) {
+
+		existing := []model.Match{{HomeTeamID: mockTeams[1].ID, AwayTeamID: mockTeams[2].ID, Status: model.MatchStatusScheduled}}
+
+		assert.Equal(t, http.StatusConflict, post(t, mockTeams[2], mockTeams[1], existing).Code)
+	})
+}
+
+func TestPostGroups(t *testing.T) {
+
+	mockTeams := []model.Team{{}, {}, {}, {}}
+	mockTeams[0].ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444a")
+	mockTeams[1].ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+	mockTeams[2].ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444c")
+	mockTeams[3].ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444e")
+
+	// The winners of both groups play the final
+	mockCompetition := model.Competition{}
+	mockCompetition.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444d")
+	mockCompetition.Type = model.CompetitionTypeGroups
+	mockCompetition.TeamIDs = []primitive.ObjectID{mockTeams[0].ID, mockTeams[1].ID, mockTeams[2].ID, mockTeams[3].ID}
+	mockCompetition.Points = table.DefaultPoints
+	mockCompetition.Knockout = &model.KnockoutRules{Legs: 1}
+	mockCompetition.GroupStage = &model.GroupRules{
+		Groups: []model.Group{
+			{Name: "A", TeamIDs: mockCompetition.TeamIDs[:2]},
+			{Name: "B", TeamIDs: mockCompetition.TeamIDs[2:]},
+		},
+		Legs:    1,
+		Advance: 1,
+	}
+
+	groupMatches := []model.Match{
+		{HomeTeamID: mockTeams[0].ID, AwayTeamID: mockTeams[1].ID, Status: model.MatchStatusFinished, Score: model.Score{Home: 1, Away: 0}},
+		{HomeTeamID: mockTeams[2].ID, AwayTeamID: mockTeams[3].ID, Status: model.MatchStatusFinished, Score: model.Score{Home: 1, Away: 0}},
+	}
+
+	post := func(t *testing.T, home, away model.Team, existing []model.Match) *httptest.ResponseRecorder {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockCompetitionRepo := competitionMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.CompetitionID = &mockCompetition.ID
+		mockMatch.HomeTeamID = home.ID
+		mockMatch.AwayTeamID = away.ID
+		mockMatch.Kickoff = time.Date(2021, 7, 11, 20, 0, 0, 0, time.UTC)
+		mockMatch.Status = model.MatchStatusScheduled
+
+		mockPayload, _ := json.Marshal(mockMatch)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/matches", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			matchRepo:       mockRepo,
+			teamRepo:        mockTeamRepo,
+			competitionRepo: mockCompetitionRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockTeamRepo.EXPECT().GetTeam(ctx, home.ID.Hex()).Return(&home, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, away.ID.Hex()).Return(&away, nil)
+		mockCompetitionRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&mockCompetition, nil)
+		mockRepo.EXPECT().ListMatch(ctx, "", gomock.Any()).Return(existing, &query.Page{}, nil)
+		mockRepo.EXPECT().CreateMatch(ctx, mockMatch).Return(&mockMatch, nil).MaxTimes(1)
+
+		assert.NoError(t, h.Post(c))
+
+		return rec
+	}
+
+	t.Run("Response Created Group Match", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, post(t, mockTeams[1], mockTeams[0], nil).Code)
+	})
+
+	t.Run("Response Created Knockout Match", func(t *testing.T) {
+		assert.Equal(t, http.StatusCreated, post(t, mockTeams[2], mockTeams[0], groupMatches).Code)
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// The qualifiers are unknown until the groups are complete
+		assert.Equal(t, http.StatusBadRequest, post(t, mockTeams[0], mockTeams[2], nil).Code)
+
+		// The teams of a group already played their single leg
+		assert.Equal(t, http.StatusBadRequest, post(t, mockTeams[1], mockTeams[0], groupMatches).Code)
+	})
+}
+
+// recorder is a listener which records the changed matches
+type recorder struct {
+	changed []model.Match
+}
+
+func (r *recorder) MatchChanged(m model.Match) {
+	r.changed = append(r.changed, m)
+}
+
+func TestGetAll(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockMatches := []model.Match{mockMatch}
+		mockPage := &query.Page{}
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data:       mockMatches,
+			Pagination: mockPage,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/matches", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().ListMatch(ctx, "", query.Params{Limit: query.DefaultLimit, Sort: query.DefaultSort}).Return(mockMatches, mockPage, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response OK With Team And Date Range", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		mockTeamID := "5f6a5d6129b2289c40b7444b"
+		mockMatches := []model.Match{}
+		mockPage := &query.Page{}
+		mockParams := query.Params{
+			Limit: query.DefaultLimit,
+			Filters: []query.Filter{
+				{Field: "kickoff", Op: "gte", Value: time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)},
+				{Field: "kickoff", Op: "lt", Value: time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)},
+			},
+			Sort: []query.Sort{{Field: "kickoff"}, {Field: "_id"}},
+		}
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/matches?team_id="+mockTeamID+"&kickoff[gte]=2020-10-01&kickoff[lt]=2020-11-01&sort=kickoff", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().ListMatch(ctx, mockTeamID, mockParams).Return(mockMatches, mockPage, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/matches?team_id=invalid", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+}
+
+func TestPatch(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockMatch.Status = model.MatchStatusFinished
+
+		mockPayload := `{"status":"finished","kickoff":"2020-10-17T18:45:00+02:00","venue":null,"penalties":{"home":4,"away":3}}`
+		mockPatch := map[string]interface{}{
+			"status":    model.MatchStatusFinished,
+			"kickoff":   time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC),
+			"venue":     nil,
+			"penalties": &model.Score{Home: 4, Away: 3},
+		}
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockMatch,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(mockPayload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMatch.ID.Hex())
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().PatchMatch(ctx, mockMatch.ID.Hex(), mockPatch).Return(&mockMatch, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Patch(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		payloads := []string{
+			`{"score":{"home":1,"away":0}}`,
+			`{"penalties":{"home":4,"away":4}}`,
+			`{"penalties":{"home":-1,"away":4}}`,
+			`{"penalties":"4-3"}`,
+		}
+
+		for _, mockPayload := range payloads {
+
+			// Setup
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(mockPayload))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			c.SetPath("/matches/:id")
+			c.SetParamNames("id")
+			c.SetParamValues("5f6a5d6129b2289c40b7444b")
+
+			h := &Handler{
+				matchRepo: mockRepo,
+			}
+
+			// Assertions
+			if assert.NoError(t, h.Patch(c)) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code, mockPayload)
+			}
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockMatchID := "5f6a5d6129b2289c40b7444b"
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(`{"status":"live"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMatchID)
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().PatchMatch(ctx, mockMatchID, map[string]interface{}{"status": model.MatchStatusLive}).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Patch(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+}
+
+func TestPostEvent(t *testing.T) {
+
+	mockHome := model.Team{}
+	mockHome.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+	mockAway := model.Team{}
+	mockAway.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444c")
+
+	mockPlayer := model.Player{}
+	mockPlayer.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444d")
+	mockAway.Players = []model.Player{mockPlayer}
+
+	t.Run("Response Created", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444e")
+		mockMatch.HomeTeamID = mockHome.ID
+		mockMatch.AwayTeamID = mockAway.ID
+		mockMatch.Status = model.MatchStatusLive
+
+		mockEvent := model.MatchEvent{Type: model.EventGoal, Minute: 45, AddedTime: 2, TeamID: &mockAway.ID, PlayerID: &mockPlayer.ID}
+		mockPayload := `{"type":"goal","minute":45,"added_time":2,"player_id":"` + mockPlayer.ID.Hex() + `"}`
+
+		mockUpdated := mockMatch
+		mockUpdated.Events = []model.MatchEvent{mockEvent}
+		mockUpdated.Score = model.Score{Away: 1}
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusCreated,
+			},
+			Data: mockUpdated,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(mockPayload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id/events")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMatch.ID.Hex())
+
+		h := &Handler{
+			matchRepo: mockRepo,
+			teamRepo:  mockTeamRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatch.ID.Hex()).Return(&mockMatch, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockHome.ID.Hex()).Return(&mockHome, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockAway.ID.Hex()).Return(&mockAway, nil)
+		mockRepo.EXPECT().AddEvent(ctx, mockMatch.ID.Hex(), mockEvent).Return(&mockUpdated, nil)
+
+		// Assertions
+		if assert.NoError(t, h.PostEvent(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Bad Request Player Of Another Team", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444e")
+		mockMatch.HomeTeamID = mockHome.ID
+		mockMatch.AwayTeamID = mockAway.ID
+		mockMatch.Status = model.MatchStatusLive
+
+		mockPayload := `{"type":"yellow_card","minute":12,"player_id":"5f6a5d6129b2289c40b7444f"}`
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(mockPayload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id/events")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMatch.ID.Hex())
+
+		h := &Handler{
+			matchRepo: mockRepo,
+			teamRepo:  mockTeamRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatch.ID.Hex()).Return(&mockMatch, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockHome.ID.Hex()).Return(&mockHome, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockAway.ID.Hex()).Return(&mockAway, nil)
+
+		// Assertions
+		if assert.NoError(t, h.PostEvent(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request Assist Of Another Team", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444e")
+		mockMatch.HomeTeamID = mockHome.ID
+		mockMatch.AwayTeamID = mockAway.ID
+		mockMatch.Status = model.MatchStatusLive
+
+		mockPayload := `{"type":"goal","minute":12,"player_id":"` + mockPlayer.ID.Hex() + `","assist_id":"5f6a5d6129b2289c40b7444f"}`
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(mockPayload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id/events")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMatch.ID.Hex())
+
+		h := &Handler{
+			matchRepo: mockRepo,
+			teamRepo:  mockTeamRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatch.ID.Hex()).Return(&mockMatch, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockHome.ID.Hex()).Return(&mockHome, nil).Times(2)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockAway.ID.Hex()).Return(&mockAway, nil).Times(2)
+
+		// Assertions
+		if assert.NoError(t, h.PostEvent(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Response Conflict", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444e")
+		mockMatch.Status = model.MatchStatusScheduled
+
+		mockPayload := `{"type":"goal","minute":3,"player_id":"` + mockPlayer.ID.Hex() + `"}`
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(mockPayload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id/events")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMatch.ID.Hex())
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatch.ID.Hex()).Return(&mockMatch, nil)
+
+		// Assertions
+		if assert.NoError(t, h.PostEvent(c)) {
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		payloads := []string{
+			`{"type":"corner","minute":3,"player_id":"` + mockPlayer.ID.Hex() + `"}`,
+			`{"type":"goal","minute":0,"player_id":"` + mockPlayer.ID.Hex() + `"}`,
+			`{"type":"goal","minute":90,"added_time":-1,"player_id":"` + mockPlayer.ID.Hex() + `"}`,
+			`{"type":"goal","minute":3}`,
+			`{"type":"substitution","minute":60,"player_id":"` + mockPlayer.ID.Hex() + `"}`,
+			`{"type":"var","minute":60}`,
+			`{"type":"penalty","minute":60,"player_id":"` + mockPlayer.ID.Hex() + `","assist_id":"5f6a5d6129b2289c40b7444f"}`,
+			`{"type":"goal","minute":60,"player_id":"` + mockPlayer.ID.Hex() + `","assist_id":"` + mockPlayer.ID.Hex() + `"}`,
+		}
+
+		for _, mockPayload := range payloads {
+
+			// Setup
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(mockPayload))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			c.SetPath("/matches/:id/events")
+			c.SetParamNames("id")
+			c.SetParamValues("5f6a5d6129b2289c40b7444e")
+
+			h := &Handler{
+				matchRepo: mockRepo,
+			}
+
+			// Assertions
+			if assert.NoError(t, h.PostEvent(c)) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code, mockPayload)
+			}
+		}
+	})
+}
+
+func TestDeleteEvent(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444e")
+		mockEventID := "5f6a5d6129b2289c40b7444f"
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id/events/:eventId")
+		c.SetParamNames("id", "eventId")
+		c.SetParamValues(mockMatch.ID.Hex(), mockEventID)
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().RemoveEvent(ctx, mockMatch.ID.Hex(), mockEventID).Return(&mockMatch, nil)
+
+		// Assertions
+		if assert.NoError(t, h.DeleteEvent(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		mockMatchID := "5f6a5d6129b2289c40b7444e"
+		mockEventID := "5f6a5d6129b2289c40b7444f"
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id/events/:eventId")
+		c.SetParamNames("id", "eventId")
+		c.SetParamValues(mockMatchID, mockEventID)
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().RemoveEvent(ctx, mockMatchID, mockEventID).Return(nil, errors.Wrap(ErrEventNotFound, "match.Repository.RemoveEvent"))
+
+		// Assertions
+		if assert.NoError(t, h.DeleteEvent(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+}
+
+func TestGetLineups(t *testing.T) {
+
+	newContext := func(id string) (echo.Context, *httptest.ResponseRecorder) {
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id/lineups")
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+
+		return c, rec
+	}
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444e")
+
+		// Setup
+		c, rec := newContext(mockMatch.ID.Hex())
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatch.ID.Hex()).Return(&mockMatch, nil)
+
+		// Assertions, both sides are null until announced
+		if assert.NoError(t, h.GetLineups(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, `{"meta":{"code":200},"data":{"home":null,"away":null}}`, strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		mockMatchID := "5f6a5d6129b2289c40b7444e"
+
+		// Setup
+		c, rec := newContext(mockMatchID)
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatchID).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetLineups(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+}
+
+func TestPutLineups(t *testing.T) {
+
+	mockHome := squad()
+	mockAway := squad()
+
+	mockMatch := model.Match{}
+	mockMatch.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444e")
+	mockMatch.HomeTeamID = mockHome.ID
+	mockMatch.AwayTeamID = mockAway.ID
+	mockMatch.Status = model.MatchStatusScheduled
+
+	newContext := func(payload string) (echo.Context, *httptest.ResponseRecorder) {
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(payload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id/lineups")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMatch.ID.Hex())
+
+		return c, rec
+	}
+
+	payload := func(lineups model.Lineups) string {
+		b, _ := json.Marshal(lineups)
+		return string(b)
+	}
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		// Only the home lineup is announced
+		home := lineup(mockHome, "4-3-3")
+		want, _ := buildLineup(home, mockHome)
+
+		mockUpdated := mockMatch
+		mockUpdated.Lineups = &model.Lineups{Home: &want}
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockUpdated.Lineups,
+		})
+
+		// Setup
+		c, rec := newContext(payload(model.Lineups{Home: &home}))
+
+		h := &Handler{
+			matchRepo: mockRepo,
+			teamRepo:  mockTeamRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatch.ID.Hex()).Return(&mockMatch, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockHome.ID.Hex()).Return(&mockHome, nil)
+		mockRepo.EXPECT().SetLineups(ctx, mockMatch.ID.Hex(), *mockUpdated.Lineups).Return(&mockUpdated, nil)
+
+		// Assertions
+		if assert.NoError(t, h.PutLineups(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response OK Keeping The Other Side", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		// The away lineup is announced, the home one is updated afterwards
+		away, _ := buildLineup(lineup(mockAway, "4-4-2"), mockAway)
+		announced := mockMatch
+		announced.Lineups = &model.Lineups{Away: &away}
+
+		home := lineup(mockHome, "4-3-3")
+		want, _ := buildLineup(home, mockHome)
+
+		mockUpdated := mockMatch
+		mockUpdated.Lineups = &model.Lineups{Home: &want, Away: &away}
+
+		// Setup
+		c, rec := newContext(payload(model.Lineups{Home: &home}))
+
+		h := &Handler{
+			matchRepo: mockRepo,
+			teamRepo:  mockTeamRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatch.ID.Hex()).Return(&announced, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockHome.ID.Hex()).Return(&mockHome, nil)
+		mockRepo.EXPECT().SetLineups(ctx, mockMatch.ID.Hex(), *mockUpdated.Lineups).Return(&mockUpdated, nil)
+
+		// Assertions
+		if assert.NoError(t, h.PutLineups(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+
+		// The away team fields the players of the home team, the bench is checked first
+		away := lineup(mockHome, "4-3-3")
+
+		// Setup
+		c, rec := newContext(payload(model.Lineups{Away: &away}))
+
+		h := &Handler{
+			matchRepo: mockRepo,
+			teamRepo:  mockTeamRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatch.ID.Hex()).Return(&mockMatch, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockAway.ID.Hex()).Return(&mockAway, nil)
+
+		// Assertions
+		if assert.NoError(t, h.PutLineups(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), "away lineup: player "+mockHome.Players[11].ID.Hex()+" does not play for the team")
+		}
+	})
+
+	t.Run("Response Conflict", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		cancelled := mockMatch
+		cancelled.Status = model.MatchStatusCancelled
+
+		home := lineup(mockHome, "4-3-3")
+
+		// Setup
+		c, rec := newContext(payload(model.Lineups{Home: &home}))
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().GetMatch(ctx, mockMatch.ID.Hex()).Return(&cancelled, nil)
+
+		// Assertions
+		if assert.NoError(t, h.PutLineups(c)) {
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("Response Unprocessable Entity", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext(`{"home":{"starters":[{"player_id":"ronaldo"}]}}`)
+
+		h := &Handler{
+			matchRepo: mockRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.PutLineups(c)) {
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		}
+	})
+}
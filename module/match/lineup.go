@@ -0,0 +1,162 @@
+package match
+
+import (
+	"strconv"
+
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/formation"
+	"github.com/yezarela/go-soccer/pkg/position"
+)
+
+// buildLineup validates the lineup of a team against its roster and returns it completed
+func buildLineup(l model.Lineup, t model.Team) (model.Lineup, string) {
+
+	res := model.Lineup{
+		TeamID:    t.ID,
+		Formation: l.Formation,
+		Starters:  []model.LineupPlayer{},
+		Bench:     []model.LineupPlayer{},
+	}
+
+	slots, ok := formation.Slots(l.Formation)
+	if !ok {
+		return res, "unknown formation " + l.Formation
+	}
+
+	if len(l.Starters) > model.Starters {
+		return res, "a lineup cannot have more than 11 starters"
+	}
+	if len(l.Starters) < model.Starters {
+		return res, "a lineup must have 11 starters"
+	}
+
+	roster := map[string]model.Player{}
+	for _, p := range t.Players {
+		roster[p.ID.Hex()] = p
+	}
+
+	players := map[string]bool{}
+	shirts := map[int]bool{}
+
+	check := func(lp model.LineupPlayer) (model.LineupPlayer, string) {
+
+		id := lp.PlayerID.Hex()
+
+		p, ok := roster[id]
+		if !ok {
+			return lp, "player " + id + " does not play for the team"
+		}
+		if players[id] {
+			return lp, "player " + id + " is in the lineup twice"
+		}
+		players[id] = true
+
+		if lp.ShirtNumber == 0 {
+			lp.ShirtNumber = p.ShirtNumber
+		}
+		if lp.ShirtNumber == 0 {
+			return lp, "player " + id + " has no shirt number"
+		}
+		if lp.ShirtNumber < 1 || lp.ShirtNumber > 99 {
+			return lp, "shirt_number must be between 1 and 99"
+		}
+		if shirts[lp.ShirtNumber] {
+			return lp, "shirt number " + strconv.Itoa(lp.ShirtNumber) + " is worn twice"
+		}
+		shirts[lp.ShirtNumber] = true
+
+		return lp, ""
+	}
+
+	for _, lp := range l.Bench {
+		if len(lp.Position) > 0 {
+			return res, "position is only allowed for starters"
+		}
+		lp, msg := check(lp)
+		if len(msg) > 0 {
+			return res, msg
+		}
+		res.Bench = append(res.Bench, lp)
+	}
+
+	free := map[string]int{}
+	for _, s := range slots {
+		free[s]++
+	}
+
+	// The starters with a position take their slot first, the others are assigned one
+	auto := map[int]bool{}
+	for i, lp := range l.Starters {
+		lp, msg := check(lp)
+		if len(msg) > 0 {
+			return res, msg
+		}
+
+		if len(lp.Position) > 0 {
+			code, ok := position.Normalize(lp.Position)
+			if !ok {
+				return res, "unknown position " + lp.Position
+			}
+			if free[code] <= 0 {
+				return res, "position " + code + " is not a free slot of the " + l.Formation
+			}
+			free[code]--
+			lp.Position = code
+		} else {
+			auto[i] = true
+		}
+
+		res.Starters = append(res.Starters, lp)
+	}
+
+	// The other starters take the free slots of their positions. When the slots of a starter are taken,
+	// the starters already in them move to other free slots of their positions (augmenting paths),
+	// so a starter only misses a slot when no assignment of the free slots fits all of them
+	var assign func(i int, visited map[string]bool) bool
+	assign = func(i int, visited map[string]bool) bool {
+
+		p := roster[res.Starters[i].PlayerID.Hex()]
+		codes := append([]string{p.Position}, p.Positions...)
+
+		for _, code := range codes {
+			if free[code] > 0 {
+				free[code]--
+				res.Starters[i].Position = code
+				return true
+			}
+		}
+
+		for _, code := range codes {
+			if visited[code] {
+				continue
+			}
+			visited[code] = true
+
+			for j := range res.Starters {
+				if !auto[j] || j == i || res.Starters[j].Position != code {
+					continue
+				}
+				res.Starters[j].Position = ""
+				if assign(j, visited) {
+					res.Starters[i].Position = code
+					return true
+				}
+				res.Starters[j].Position = code
+			}
+		}
+
+		return false
+	}
+
+	for i, lp := range res.Starters {
+		if !auto[i] {
+			continue
+		}
+
+		if !assign(i, map[string]bool{}) {
+			return res, "player " + lp.PlayerID.Hex() + " plays no free slot of the " + l.Formation + ", its position must be set"
+		}
+	}
+
+	return res, ""
+}
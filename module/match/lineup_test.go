@@ -0,0 +1,170 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// squad returns a team whose players play the positions of a 4-3-3 then the bench, with their index as shirt number
+func squad() model.Team {
+
+	t := model.Team{ID: primitive.NewObjectID()}
+
+	positions := []string{
+		model.PositionGoalkeeper, model.PositionRightBack, model.PositionCentreBack, model.PositionCentreBack, model.PositionLeftBack,
+		model.PositionCentralMidfielder, model.PositionDefensiveMidfielder, model.PositionCentralMidfielder,
+		model.PositionRightWinger, model.PositionStriker, model.PositionLeftWinger,
+		model.PositionGoalkeeper, model.PositionStriker,
+	}
+
+	for i, position := range positions {
+		t.Players = append(t.Players, model.Player{ID: primitive.NewObjectID(), Position: position, ShirtNumber: i + 1})
+	}
+
+	return t
+}
+
+// lineup returns the lineup of the 11 first players of a team and its other players on the bench
+func lineup(t model.Team, formation string) model.Lineup {
+
+	l := model.Lineup{Formation: formation}
+
+	for i, p := range t.Players {
+		if i < model.Starters {
+			l.Starters = append(l.Starters, model.LineupPlayer{PlayerID: p.ID})
+		} else {
+			l.Bench = append(l.Bench, model.LineupPlayer{PlayerID: p.ID})
+		}
+	}
+
+	return l
+}
+
+func TestBuildLineup(t *testing.T) {
+
+	t.Run("Slots From The Roster", func(t *testing.T) {
+
+		team := squad()
+		team.Players[10].Positions = []string{model.PositionStriker}
+
+		res, msg := buildLineup(lineup(team, "4-3-3"), team)
+
+		assert.Empty(t, msg)
+		assert.Equal(t, team.ID, res.TeamID)
+		if assert.Len(t, res.Starters, 11) && assert.Len(t, res.Bench, 2) {
+			assert.Equal(t, model.PositionGoalkeeper, res.Starters[0].Position)
+			assert.Equal(t, model.PositionLeftWinger, res.Starters[10].Position)
+			assert.Equal(t, 11, res.Starters[10].ShirtNumber)
+			assert.Equal(t, 13, res.Bench[1].ShirtNumber)
+			assert.Empty(t, res.Bench[1].Position)
+		}
+
+		// The left winger plays striker in a 4-4-2 once the slots of the other starters are taken
+		l := lineup(team, "4-4-2")
+		l.Starters[5].Position = "right midfield"
+		l.Starters[6].Position = model.PositionCentralMidfielder
+		l.Starters[8].Position = model.PositionLeftMidfielder
+
+		res, msg = buildLineup(l, team)
+
+		assert.Empty(t, msg)
+		assert.Equal(t, model.PositionRightMidfielder, res.Starters[5].Position)
+		assert.Equal(t, model.PositionStriker, res.Starters[10].Position)
+	})
+
+	t.Run("Slots Matching", func(t *testing.T) {
+
+		// The right winger prefers to play striker and comes first, taking the slot greedily
+		// would leave none for the striker
+		team := squad()
+		team.Players[8].Position = model.PositionStriker
+		team.Players[8].Positions = []string{model.PositionStriker, model.PositionRightWinger}
+
+		res, msg := buildLineup(lineup(team, "4-3-3"), team)
+
+		assert.Empty(t, msg)
+		assert.Equal(t, model.PositionRightWinger, res.Starters[8].Position)
+		assert.Equal(t, model.PositionStriker, res.Starters[9].Position)
+
+		// Without another position for one of them, one striker is left out
+		team.Players[8].Positions = nil
+
+		_, msg = buildLineup(lineup(team, "4-3-3"), team)
+		assert.Equal(t, "player "+team.Players[9].ID.Hex()+" plays no free slot of the 4-3-3, its position must be set", msg)
+	})
+
+	t.Run("Shirt Numbers", func(t *testing.T) {
+
+		team := squad()
+
+		l := lineup(team, "4-3-3")
+		l.Starters[0].ShirtNumber = 99
+
+		res, msg := buildLineup(l, team)
+
+		assert.Empty(t, msg)
+		assert.Equal(t, 99, res.Starters[0].ShirtNumber)
+
+		// The reserve goalkeeper wears the number of the starter
+		l.Starters[0].ShirtNumber = 12
+
+		_, msg = buildLineup(l, team)
+		assert.Equal(t, "shirt number 12 is worn twice", msg)
+
+		team.Players[12].ShirtNumber = 0
+
+		_, msg = buildLineup(lineup(team, "4-3-3"), team)
+		assert.Equal(t, "player "+team.Players[12].ID.Hex()+" has no shirt number", msg)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+
+		team := squad()
+		stranger := primitive.NewObjectID()
+
+		lineups := map[string]func(l *model.Lineup){
+			"unknown formation 4-3-2": func(l *model.Lineup) {
+				l.Formation = "4-3-2"
+			},
+			"a lineup cannot have more than 11 starters": func(l *model.Lineup) {
+				l.Starters = append(l.Starters, l.Bench[0])
+				l.Bench = l.Bench[1:]
+			},
+			"a lineup must have 11 starters": func(l *model.Lineup) {
+				l.Starters = l.Starters[1:]
+			},
+			"player " + stranger.Hex() + " does not play for the team": func(l *model.Lineup) {
+				l.Bench[0].PlayerID = stranger
+			},
+			"player " + team.Players[9].ID.Hex() + " is in the lineup twice": func(l *model.Lineup) {
+				l.Bench[0].PlayerID = team.Players[9].ID
+			},
+			"shirt_number must be between 1 and 99": func(l *model.Lineup) {
+				l.Starters[3].ShirtNumber = 100
+			},
+			"position is only allowed for starters": func(l *model.Lineup) {
+				l.Bench[0].Position = model.PositionGoalkeeper
+			},
+			"unknown position winger": func(l *model.Lineup) {
+				l.Starters[8].Position = "winger"
+			},
+			"position AM is not a free slot of the 4-3-3": func(l *model.Lineup) {
+				l.Starters[5].Position = model.PositionAttackingMidfielder
+			},
+			"player " + team.Players[9].ID.Hex() + " plays no free slot of the 4-3-3, its position must be set": func(l *model.Lineup) {
+				l.Starters[0], l.Bench[1] = l.Bench[1], l.Starters[0]
+			},
+		}
+
+		for want, fn := range lineups {
+			l := lineup(team, "4-3-3")
+			fn(&l)
+
+			_, msg := buildLineup(l, team)
+			assert.Equal(t, want, msg)
+		}
+	})
+}
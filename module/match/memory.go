@@ -0,0 +1,215 @@
+package match
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/memdb"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryRepository struct {
+	db *memdb.DB
+}
+
+// NewMemoryRepository creates a new match repository stored in memory
+func NewMemoryRepository(db *memdb.DB) Repository {
+	return &memoryRepository{db}
+}
+
+// ListMatch returns a filtered and sorted page of matches, of a team if teamID is not empty
+func (repo *memoryRepository) ListMatch(ctx context.Context, teamID string, params query.Params) ([]model.Match, *query.Page, error) {
+	repo.db.RLock()
+	defer repo.db.RUnlock()
+
+	items := filterMatches(repo.db.Matches, teamID, params)
+
+	return paginate(items, params, len(repo.db.Matches), len(teamID) > 0)
+}
+
+// GetMatch returns a match by id
+func (repo *memoryRepository) GetMatch(ctx context.Context, id string) (*model.Match, error) {
+	repo.db.RLock()
+	defer repo.db.RUnlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	m, ok := repo.db.Matches[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	Derive(&m)
+
+	return &m, nil
+}
+
+// CreateMatch creates a new match
+func (repo *memoryRepository) CreateMatch(ctx context.Context, data model.Match) (*model.Match, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	data.ID = primitive.NewObjectID()
+	data.CreatedAt = storage.Now()
+	data.Events = []model.MatchEvent{}
+
+	repo.db.Matches[data.ID] = data
+	Derive(&data)
+
+	return &data, nil
+}
+
+// DeleteMatch deletes a match and returns the deleted match
+func (repo *memoryRepository) DeleteMatch(ctx context.Context, id string) (*model.Match, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	m, ok := repo.db.Matches[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	delete(repo.db.Matches, oid)
+	Derive(&m)
+
+	return &m, nil
+}
+
+// PatchMatch applies a merge patch to a match, null values remove the field
+func (repo *memoryRepository) PatchMatch(ctx context.Context, id string, patch map[string]interface{}) (*model.Match, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	m, ok := repo.db.Matches[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	applyPatch(&m, patch)
+	repo.db.Matches[oid] = m
+	Derive(&m)
+
+	return &m, nil
+}
+
+// AddEvent appends an event to a match
+func (repo *memoryRepository) AddEvent(ctx context.Context, id string, data model.MatchEvent) (*model.Match, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	m, ok := repo.db.Matches[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	data.ID = primitive.NewObjectID()
+	m.Events = append(append([]model.MatchEvent{}, m.Events...), data)
+
+	repo.db.Matches[oid] = m
+	Derive(&m)
+
+	return &m, nil
+}
+
+// RemoveEvent removes an event from a match
+func (repo *memoryRepository) RemoveEvent(ctx context.Context, id string, eventID string) (*model.Match, error) {
+	op := "match.Repository.RemoveEvent"
+
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	m, ok := repo.db.Matches[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	events, removed := removeEvent(m.Events, eventID)
+	if !removed {
+		return nil, errors.Wrap(ErrEventNotFound, op)
+	}
+	m.Events = events
+
+	repo.db.Matches[oid] = m
+	Derive(&m)
+
+	return &m, nil
+}
+
+// SetLineups replaces the lineups of a match
+func (repo *memoryRepository) SetLineups(ctx context.Context, id string, data model.Lineups) (*model.Match, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	m, ok := repo.db.Matches[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	m.Lineups = &data
+
+	repo.db.Matches[oid] = m
+	Derive(&m)
+
+	return &m, nil
+}
+
+// EnsureIndexes does nothing, the in-memory storage has no indexes
+func (repo *memoryRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// filterMatches returns the sorted matches matching the params, of a team if teamID is not empty
+func filterMatches(all map[primitive.ObjectID]model.Match, teamID string, params query.Params) []model.Match {
+
+	oid, _ := primitive.ObjectIDFromHex(teamID)
+
+	var items []model.Match
+
+	for _, m := range all {
+		if len(teamID) > 0 && m.HomeTeamID != oid && m.AwayTeamID != oid {
+			continue
+		}
+		if params.Match(cursorValues(m)) {
+			Derive(&m)
+			items = append(items, m)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return params.Less(cursorValues(items[i]), cursorValues(items[j]))
+	})
+
+	return items
+}
+
+// paginate returns the page of sorted matches, without total when filtered by team
+func paginate(items []model.Match, params query.Params, total int, byTeam bool) ([]model.Match, *query.Page, error) {
+
+	var count query.Counter
+	if !byTeam {
+		count = query.Total(total)
+	}
+
+	page, n, err := params.NewPage(len(items), func(i int) map[string]interface{} {
+		return cursorValues(items[i])
+	}, count)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return items[:n], page, nil
+}
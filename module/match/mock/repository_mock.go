@@ -0,0 +1,171 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: module/match/repository.go
+
+// Package mock_match is a generated GoMock package.
+package mock_match
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/yezarela/go-soccer/model"
+	query "github.com/yezarela/go-soccer/pkg/query"
+	reflect "reflect"
+)
+
+// MockRepository is a mock of Repository interface
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListMatch mocks base method
+func (m *MockRepository) ListMatch(ctx context.Context, teamID string, params query.Params) ([]model.Match, *query.Page, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMatch", ctx, teamID, params)
+	ret0, _ := ret[0].([]model.Match)
+	ret1, _ := ret[1].(*query.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListMatch indicates an expected call of ListMatch
+func (mr *MockRepositoryMockRecorder) ListMatch(ctx, teamID, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMatch", reflect.TypeOf((*MockRepository)(nil).ListMatch), ctx, teamID, params)
+}
+
+// GetMatch mocks base method
+func (m *MockRepository) GetMatch(ctx context.Context, id string) (*model.Match, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMatch", ctx, id)
+	ret0, _ := ret[0].(*model.Match)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMatch indicates an expected call of GetMatch
+func (mr *MockRepositoryMockRecorder) GetMatch(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMatch", reflect.TypeOf((*MockRepository)(nil).GetMatch), ctx, id)
+}
+
+// CreateMatch mocks base method
+func (m *MockRepository) CreateMatch(ctx context.Context, data model.Match) (*model.Match, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMatch", ctx, data)
+	ret0, _ := ret[0].(*model.Match)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMatch indicates an expected call of CreateMatch
+func (mr *MockRepositoryMockRecorder) CreateMatch(ctx, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMatch", reflect.TypeOf((*MockRepository)(nil).CreateMatch), ctx, data)
+}
+
+// DeleteMatch mocks base method
+func (m *MockRepository) DeleteMatch(ctx context.Context, id string) (*model.Match, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMatch", ctx, id)
+	ret0, _ := ret[0].(*model.Match)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMatch indicates an expected call of DeleteMatch
+func (mr *MockRepositoryMockRecorder) DeleteMatch(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMatch", reflect.TypeOf((*MockRepository)(nil).DeleteMatch), ctx, id)
+}
+
+// PatchMatch mocks base method
+func (m *MockRepository) PatchMatch(ctx context.Context, id string, patch map[string]interface{}) (*model.Match, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchMatch", ctx, id, patch)
+	ret0, _ := ret[0].(*model.Match)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchMatch indicates an expected call of PatchMatch
+func (mr *MockRepositoryMockRecorder) PatchMatch(ctx, id, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchMatch", reflect.TypeOf((*MockRepository)(nil).PatchMatch), ctx, id, patch)
+}
+
+// AddEvent mocks base method
+func (m *MockRepository) AddEvent(ctx context.Context, id string, data model.MatchEvent) (*model.Match, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEvent", ctx, id, data)
+	ret0, _ := ret[0].(*model.Match)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddEvent indicates an expected call of AddEvent
+func (mr *MockRepositoryMockRecorder) AddEvent(ctx, id, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvent", reflect.TypeOf((*MockRepository)(nil).AddEvent), ctx, id, data)
+}
+
+// RemoveEvent mocks base method
+func (m *MockRepository) RemoveEvent(ctx context.Context, id, eventID string) (*model.Match, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveEvent", ctx, id, eventID)
+	ret0, _ := ret[0].(*model.Match)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveEvent indicates an expected call of RemoveEvent
+func (mr *MockRepositoryMockRecorder) RemoveEvent(ctx, id, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveEvent", reflect.TypeOf((*MockRepository)(nil).RemoveEvent), ctx, id, eventID)
+}
+
+// SetLineups mocks base method
+func (m *MockRepository) SetLineups(ctx context.Context, id string, data model.Lineups) (*model.Match, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLineups", ctx, id, data)
+	ret0, _ := ret[0].(*model.Match)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLineups indicates an expected call of SetLineups
+func (mr *MockRepositoryMockRecorder) SetLineups(ctx, id, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLineups", reflect.TypeOf((*MockRepository)(nil).SetLineups), ctx, id, data)
+}
+
+// EnsureIndexes mocks base method
+func (m *MockRepository) EnsureIndexes(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureIndexes", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureIndexes indicates an expected call of EnsureIndexes
+func (mr *MockRepositoryMockRecorder) EnsureIndexes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureIndexes", reflect.TypeOf((*MockRepository)(nil).EnsureIndexes), ctx)
+}
@@ -0,0 +1,338 @@
+package match
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Repository represents repository of pkg match
+type Repository interface {
+	ListMatch(ctx context.Context, teamID string, params query.Params) ([]model.Match, *query.Page, error)
+	GetMatch(ctx context.Context, id string) (*model.Match, error)
+	CreateMatch(ctx context.Context, data model.Match) (*model.Match, error)
+	DeleteMatch(ctx context.Context, id string) (*model.Match, error)
+	PatchMatch(ctx context.Context, id string, patch map[string]interface{}) (*model.Match, error)
+	AddEvent(ctx context.Context, id string, data model.MatchEvent) (*model.Match, error)
+	RemoveEvent(ctx context.Context, id string, eventID string) (*model.Match, error)
+	SetLineups(ctx context.Context, id string, data model.Lineups) (*model.Match, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+// ErrEventNotFound is returned when removing an event which is not in the match
+var ErrEventNotFound = errors.New("event is not in the match")
+
+type repository struct {
+	db *mongo.Database
+}
+
+// Fields are the filterable and sortable fields of a match
+var Fields = query.Fields{
+	"competition_id": query.ID,
+	"home_team_id":   query.ID,
+	"away_team_id":   query.ID,
+	"kickoff":        query.Time,
+	"venue":          query.String,
+	"status":         query.String,
+	"created_at":     query.Time,
+}
+
+// cursorValues returns the sortable values of a match
+func cursorValues(data model.Match) map[string]interface{} {
+
+	// Friendlies have no competition, they sort first like a missing field
+	var competitionID interface{}
+	if data.CompetitionID != nil {
+		competitionID = *data.CompetitionID
+	}
+
+	return map[string]interface{}{
+		"competition_id": competitionID,
+		"home_team_id":   data.HomeTeamID,
+		"away_team_id":   data.AwayTeamID,
+		"kickoff":        data.Kickoff,
+		"venue":          data.Venue,
+		"status":         data.Status,
+		"created_at":     data.CreatedAt,
+		"_id":            data.ID,
+	}
+}
+
+// applyPatch applies a typed merge patch to a match, nil values remove the field
+func applyPatch(m *model.Match, patch map[string]interface{}) {
+
+	for k, v := range patch {
+		switch k {
+		case "kickoff":
+			m.Kickoff, _ = v.(time.Time)
+		case "venue":
+			m.Venue, _ = v.(string)
+		case "status":
+			m.Status, _ = v.(string)
+		case "penalties":
+			m.Penalties, _ = v.(*model.Score)
+		}
+	}
+}
+
+// ListAll returns all the matches matching the filters, of a team if teamID is not empty
+func ListAll(ctx context.Context, repo Repository, teamID string, filters url.Values) ([]model.Match, error) {
+
+	all := []model.Match{}
+
+	err := query.All(filters, Fields, func(params query.Params) (*query.Page, error) {
+		items, page, err := repo.ListMatch(ctx, teamID, params)
+		all = append(all, items...)
+		return page, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
+
+// NewRepository creates a new match repository
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{db}
+}
+
+// ListMatch returns a filtered and sorted page of matches, of a team if teamID is not empty
+func (repo *repository) ListMatch(ctx context.Context, teamID string, params query.Params) ([]model.Match, *query.Page, error) {
+	op := "match.Repository.ListMatch"
+
+	filter := params.MongoFilter()
+
+	if len(teamID) > 0 {
+		oid, _ := primitive.ObjectIDFromHex(teamID)
+		team := bson.M{"$or": bson.A{bson.M{"home_team_id": oid}, bson.M{"away_team_id": oid}}}
+		filter = bson.M{"$and": bson.A{filter, team}}
+	}
+
+	opts := options.Find().SetSort(params.MongoSort()).SetLimit(int64(params.FetchLimit()))
+
+	cur, err := repo.db.Collection("matches").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+	defer cur.Close(ctx)
+
+	// The matches of a team are filtered too
+	var count query.Counter
+	if len(teamID) <= 0 {
+		count = query.EstimatedCount(ctx, repo.db.Collection("matches"))
+	}
+
+	var items []model.Match
+
+	page, err := params.MongoPage(ctx, cur, &items, count)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+
+	for i := range items {
+		Derive(&items[i])
+	}
+
+	return items, page, nil
+}
+
+// GetMatch returns a match by id
+func (repo *repository) GetMatch(ctx context.Context, id string) (*model.Match, error) {
+	op := "match.Repository.GetMatch"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	var data *model.Match
+
+	err := repo.db.Collection("matches").FindOne(ctx, bson.M{"_id": oid}).Decode(&data)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, op)
+	}
+
+	Derive(data)
+
+	return data, nil
+}
+
+// CreateMatch creates a new match
+func (repo *repository) CreateMatch(ctx context.Context, data model.Match) (*model.Match, error) {
+	op := "match.Repository.CreateMatch"
+
+	body := bson.M{
+		"home_team_id": data.HomeTeamID,
+		"away_team_id": data.AwayTeamID,
+		"kickoff":      data.Kickoff,
+		"venue":        data.Venue,
+		"status":       data.Status,
+		"events":       bson.A{},
+		"created_at":   time.Now(),
+	}
+
+	if data.CompetitionID != nil {
+		body["competition_id"] = *data.CompetitionID
+	}
+
+	res, err := repo.db.Collection("matches").InsertOne(ctx, body)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		return repo.GetMatch(ctx, oid.Hex())
+	}
+
+	return nil, nil
+}
+
+// DeleteMatch deletes a match and returns the deleted match
+func (repo *repository) DeleteMatch(ctx context.Context, id string) (*model.Match, error) {
+	op := "match.Repository.DeleteMatch"
+
+	data, err := repo.GetMatch(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if data == nil {
+		return nil, nil
+	}
+
+	_, err = repo.db.Collection("matches").DeleteOne(ctx, bson.M{"_id": data.ID})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return data, nil
+}
+
+// PatchMatch applies a merge patch of typed values to a match, null values remove the field
+func (repo *repository) PatchMatch(ctx context.Context, id string, patch map[string]interface{}) (*model.Match, error) {
+	op := "match.Repository.PatchMatch"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	set := bson.M{}
+	unset := bson.M{}
+
+	for k, v := range patch {
+		if v == nil {
+			unset[k] = ""
+		} else {
+			set[k] = v
+		}
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	if len(update) == 0 {
+		return repo.GetMatch(ctx, id)
+	}
+
+	res, err := repo.db.Collection("matches").UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if res.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	return repo.GetMatch(ctx, id)
+}
+
+// AddEvent appends an event to a match
+func (repo *repository) AddEvent(ctx context.Context, id string, data model.MatchEvent) (*model.Match, error) {
+	op := "match.Repository.AddEvent"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+	data.ID = primitive.NewObjectID()
+
+	res, err := repo.db.Collection("matches").UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$push": bson.M{"events": data}})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if res.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	return repo.GetMatch(ctx, id)
+}
+
+// RemoveEvent removes an event from a match
+func (repo *repository) RemoveEvent(ctx context.Context, id string, eventID string) (*model.Match, error) {
+	op := "match.Repository.RemoveEvent"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+	eoid, _ := primitive.ObjectIDFromHex(eventID)
+
+	res, err := repo.db.Collection("matches").UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$pull": bson.M{"events": bson.M{"_id": eoid}}})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if res.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	if res.ModifiedCount == 0 {
+		return nil, errors.Wrap(ErrEventNotFound, op)
+	}
+
+	return repo.GetMatch(ctx, id)
+}
+
+// SetLineups replaces the lineups of a match
+func (repo *repository) SetLineups(ctx context.Context, id string, data model.Lineups) (*model.Match, error) {
+	op := "match.Repository.SetLineups"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	res, err := repo.db.Collection("matches").UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"lineups": data}})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if res.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	return repo.GetMatch(ctx, id)
+}
+
+// EnsureIndexes creates the indexes of the schedules of the teams and of the competitions
+func (repo *repository) EnsureIndexes(ctx context.Context) error {
+	op := "match.Repository.EnsureIndexes"
+
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "kickoff", Value: 1}}},
+		{Keys: bson.D{{Key: "home_team_id", Value: 1}, {Key: "kickoff", Value: 1}}},
+		{Keys: bson.D{{Key: "away_team_id", Value: 1}, {Key: "kickoff", Value: 1}}},
+		{Keys: bson.D{{Key: "competition_id", Value: 1}, {Key: "kickoff", Value: 1}}},
+	}
+
+	_, err := repo.db.Collection("matches").Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return errors.Wrap(err, op)
+	}
+
+	return nil
+}
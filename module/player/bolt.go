@@ -0,0 +1,280 @@
+package player
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/boltdb"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/storage"
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type boltRepository struct {
+	db *bolt.DB
+}
+
+// NewBoltRepository creates a new player repository stored in a boltdb file
+func NewBoltRepository(db *bolt.DB) Repository {
+	return &boltRepository{db}
+}
+
+// all returns every player of the bucket
+func (repo *boltRepository) all(tx *bolt.Tx) ([]model.Player, error) {
+
+	var items []model.Player
+
+	err := tx.Bucket(boltdb.PlayersBucket).ForEach(func(k, v []byte) error {
+		var p model.Player
+		if err := json.Unmarshal(v, &p); err != nil {
+			return err
+		}
+		items = append(items, p)
+		return nil
+	})
+
+	return items, err
+}
+
+// ListPlayer returns a filtered and sorted page of players
+func (repo *boltRepository) ListPlayer(ctx context.Context, params query.Params) ([]model.Player, *query.Page, error) {
+	op := "player.Repository.ListPlayer"
+
+	var items []model.Player
+	var total int
+
+	err := repo.db.View(func(tx *bolt.Tx) error {
+		all, err := repo.all(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range all {
+			if params.Match(cursorValues(p)) {
+				items = append(items, p)
+			}
+		}
+
+		total = len(all)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return params.Less(cursorValues(items[i]), cursorValues(items[j]))
+	})
+
+	page, n, err := params.NewPage(len(items), func(i int) map[string]interface{} {
+		return cursorValues(items[i])
+	}, query.Total(total))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+
+	return items[:n], page, nil
+}
+
+// GetPlayer returns a player by id
+func (repo *boltRepository) GetPlayer(ctx context.Context, id string) (*model.Player, error) {
+	op := "player.Repository.GetPlayer"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	var data *model.Player
+
+	err := repo.db.View(func(tx *bolt.Tx) error {
+		var p model.Player
+		found, err := boltdb.Get(tx.Bucket(boltdb.PlayersBucket), oid, &p)
+		if found {
+			data = &p
+		}
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return data, nil
+}
+
+// CreatePlayer creates a new player
+func (repo *boltRepository) CreatePlayer(ctx context.Context, data model.Player) (*model.Player, error) {
+	op := "player.Repository.CreatePlayer"
+
+	data.ID = primitive.NewObjectID()
+	data.CreatedAt = storage.Now()
+
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		return boltdb.Put(tx.Bucket(boltdb.PlayersBucket), data.ID, boltdb.Player(data))
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return &data, nil
+}
+
+// update applies fn to a stored player, it returns nil if the player does not exist
+func (repo *boltRepository) update(id string, fn func(p *model.Player)) (*model.Player, error) {
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	var data *model.Player
+
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltdb.PlayersBucket)
+
+		var p model.Player
+		found, err := boltdb.Get(b, oid, &p)
+		if err != nil || !found {
+			return err
+		}
+
+		fn(&p)
+		data = &p
+
+		return boltdb.Put(b, oid, boltdb.Player(p))
+	})
+
+	return data, err
+}
+
+// UpdatePlayer replaces the fields of a player
+func (repo *boltRepository) UpdatePlayer(ctx context.Context, id string, data model.Player) (*model.Player, error) {
+	op := "player.Repository.UpdatePlayer"
+
+	res, err := repo.update(id, func(p *model.Player) {
+		data.ID, data.CreatedAt = p.ID, p.CreatedAt
+		*p = data
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return res, nil
+}
+
+// PatchPlayer applies a typed merge patch to a player, null values remove the field
+func (repo *boltRepository) PatchPlayer(ctx context.Context, id string, patch map[string]interface{}) (*model.Player, error) {
+	op := "player.Repository.PatchPlayer"
+
+	res, err := repo.update(id, func(p *model.Player) {
+		applyPatch(p, patch)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return res, nil
+}
+
+// DeletePlayer deletes a player and removes it from the teams it belongs to
+func (repo *boltRepository) DeletePlayer(ctx context.Context, id string) (*model.Player, error) {
+	op := "player.Repository.DeletePlayer"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	var data *model.Player
+
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		players := tx.Bucket(boltdb.PlayersBucket)
+
+		var p model.Player
+		found, err := boltdb.Get(players, oid, &p)
+		if err != nil || !found {
+			return err
+		}
+
+		if err = players.Delete(oid[:]); err != nil {
+			return err
+		}
+
+		// Keep teams from referencing a player that no longer exists
+		teams := tx.Bucket(boltdb.TeamsBucket)
+		updated := []boltdb.Team{}
+
+		err = teams.ForEach(func(k, v []byte) error {
+			var t boltdb.Team
+			if err := json.Unmarshal(v, &t); err != nil {
+				return err
+			}
+
+			ids := []primitive.ObjectID{}
+			for _, pid := range t.Players {
+				if pid != oid {
+					ids = append(ids, pid)
+				}
+			}
+
+			if len(ids) != len(t.Players) {
+				t.Players = ids
+				updated = append(updated, t)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Buckets cannot be modified while iterating
+		for _, t := range updated {
+			if err := boltdb.Put(teams, t.ID, t); err != nil {
+				return err
+			}
+		}
+
+		data = &p
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return data, nil
+}
+
+// SearchPlayer returns the players matching a text search by relevance
+func (repo *boltRepository) SearchPlayer(ctx context.Context, q string, limit int) ([]model.SearchResult, error) {
+	op := "player.Repository.SearchPlayer"
+
+	res := []model.SearchResult{}
+
+	err := repo.db.View(func(tx *bolt.Tx) error {
+		all, err := repo.all(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range all {
+			score := storage.TextScore(q, storage.Text{Value: p.Name, Weight: 10}, storage.Text{Value: p.Nickname, Weight: 5})
+			if score > 0 {
+				res = append(res, model.SearchResult{Type: model.SearchTypePlayer, Score: score, Data: p})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Score > res[j].Score
+	})
+
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
+
+// EnsureIndexes does nothing, the buckets are created by the schema migrations
+func (repo *boltRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
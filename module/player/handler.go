@@ -1,11 +1,29 @@
 package player
 
 import (
+	"encoding/json"
+	"fmt"
+	"time"
+
 	"github.com/labstack/echo/v4"
 	"github.com/yezarela/go-soccer/model"
 	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/country"
+	"github.com/yezarela/go-soccer/pkg/position"
+	"github.com/yezarela/go-soccer/pkg/query"
+)
+
+const (
+	minHeight      = 120
+	maxHeight      = 230
+	minWeight      = 40
+	maxWeight      = 150
+	maxShirtNumber = 99
 )
 
+// minDateOfBirth is the earliest date of birth of a player
+var minDateOfBirth = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+
 // Handler represents the httphandler for player
 type Handler struct {
 	playerRepo Repository
@@ -20,19 +38,62 @@ func NewHandler(e *echo.Echo, playerRepo Repository) {
 	e.GET("/players", handler.GetAll)
 	e.POST("/players", handler.Post)
 	e.GET("/players/:id", handler.GetByID)
+	e.PUT("/players/:id", handler.Put)
+	e.PATCH("/players/:id", handler.Patch)
+	e.DELETE("/players/:id", handler.Delete)
+	e.GET("/positions", handler.GetPositions)
 }
 
-// GetAll returns list of players
+// GetAll returns a page of players
 func (h *Handler) GetAll(c echo.Context) error {
 
 	ctx := c.Request().Context()
 
-	res, err := h.playerRepo.ListPlayer(ctx)
+	params, err := query.ParseParams(c.QueryParams(), Fields)
+	if err != nil {
+		return api.ResponseBadRequest(c, err.Error())
+	}
+
+	// The positions are stored as codes, an equality also matches the legacy values not normalized yet
+	for i, f := range params.Filters {
+		if f.Field != "position" {
+			continue
+		}
+		switch v := f.Value.(type) {
+		case string:
+			code, _ := position.Normalize(v)
+			params.Filters[i].Value = code
+			if f.Op == "eq" {
+				params.Filters[i].Op = "in"
+				params.Filters[i].Value = values(code)
+			}
+		case []interface{}:
+			in := []interface{}{}
+			for _, item := range v {
+				code, _ := position.Normalize(item.(string))
+				in = append(in, values(code)...)
+			}
+			params.Filters[i].Value = in
+		}
+	}
+
+	res, page, err := h.playerRepo.ListPlayer(ctx, params)
 	if err != nil {
 		return api.ResponseError(c, err)
 	}
 
-	return api.ResponseOK(c, res)
+	return api.ResponsePage(c, res, page)
+}
+
+// values returns the values of a position to filter the players by
+func values(code string) []interface{} {
+
+	res := []interface{}{}
+	for _, v := range position.Values(code) {
+		res = append(res, v)
+	}
+
+	return res
 }
 
 // GetByID returns a player by id
@@ -52,7 +113,7 @@ func (h *Handler) GetByID(c echo.Context) error {
 	return api.ResponseOK(c, res)
 }
 
-// Post creates a new player
+// Post creates a new player, the profile fields are optional
 func (h *Handler) Post(c echo.Context) error {
 
 	ctx := c.Request().Context()
@@ -63,11 +124,10 @@ func (h *Handler) Post(c echo.Context) error {
 		return api.ResponseUnprocessableEntity(c, "invalid body")
 	}
 
-	if len(body.Name) <= 0 {
-		return api.ResponseBadRequest(c, "name cannot be empty")
-	}
-	if len(body.Position) <= 0 {
-		return api.ResponseBadRequest(c, "position cannot be empty")
+	Normalize(&body)
+
+	if msg := ValidatePlayer(body); len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
 	}
 
 	res, err := h.playerRepo.CreatePlayer(ctx, body)
@@ -77,3 +137,305 @@ func (h *Handler) Post(c echo.Context) error {
 
 	return api.ResponseCreated(c, res)
 }
+
+// Put replaces a player by id
+func (h *Handler) Put(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	// Bind would try to bind the id path param into the body
+	var body model.Player
+	err := json.NewDecoder(c.Request().Body).Decode(&body)
+	if err != nil {
+		return api.ResponseUnprocessableEntity(c, "invalid body")
+	}
+
+	Normalize(&body)
+
+	if msg := ValidatePlayer(body); len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
+	}
+
+	res, err := h.playerRepo.UpdatePlayer(ctx, c.Param("id"), body)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested player")
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// Patch partially updates a player by id using JSON merge patch
+func (h *Handler) Patch(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	var body map[string]interface{}
+	err := json.NewDecoder(c.Request().Body).Decode(&body)
+	if err != nil || body == nil {
+		return api.ResponseUnprocessableEntity(c, "invalid body")
+	}
+
+	patch, msg := parsePlayerPatch(body)
+	if len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
+	}
+
+	_, main := patch["position"]
+	_, positions := patch["positions"]
+
+	// The main position must stay one of the playable positions
+	if main || positions {
+		p, err := h.playerRepo.GetPlayer(ctx, c.Param("id"))
+		if err != nil {
+			return api.ResponseError(c, err)
+		}
+		if p == nil {
+			return api.ResponseNotFound(c, "cannot find the requested player")
+		}
+
+		merged := *p
+		applyPatch(&merged, patch)
+		if msg := validatePositions(merged.Position, merged.Positions); len(msg) > 0 {
+			return api.ResponseBadRequest(c, msg)
+		}
+	}
+
+	res, err := h.playerRepo.PatchPlayer(ctx, c.Param("id"), patch)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested player")
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// Delete deletes a player by id
+func (h *Handler) Delete(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	res, err := h.playerRepo.DeletePlayer(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested player")
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// GetPositions returns the positions a player can play, with their line and the aliases accepted for them
+func (h *Handler) GetPositions(c echo.Context) error {
+	return api.ResponseOK(c, position.All())
+}
+
+// Normalize normalizes the positions and the nationality of a player, unknown positions are left as is
+func Normalize(p *model.Player) {
+
+	p.Position, _ = position.Normalize(p.Position)
+	for i := range p.Positions {
+		p.Positions[i], _ = position.Normalize(p.Positions[i])
+	}
+
+	p.Nationality = country.Normalize(p.Nationality)
+}
+
+// ValidatePlayer returns the validation message of a normalized player, empty if valid
+func ValidatePlayer(body model.Player) string {
+
+	if len(body.Name) <= 0 {
+		return "name cannot be empty"
+	}
+	if len(body.Position) <= 0 {
+		return "position cannot be empty"
+	}
+	if !position.Valid(body.Position) {
+		return "unknown position " + body.Position
+	}
+
+	if msg := validatePositions(body.Position, body.Positions); len(msg) > 0 {
+		return msg
+	}
+
+	return validateProfile(body)
+}
+
+// validateProfile returns the validation message of the profile fields of a player, empty if valid
+func validateProfile(body model.Player) string {
+
+	if len(body.DateOfBirth) > 0 {
+		if msg := validateDateOfBirth(body.DateOfBirth); len(msg) > 0 {
+			return msg
+		}
+	}
+	if len(body.Nationality) > 0 && !country.Valid(body.Nationality) {
+		return "nationality must be an ISO 3166 country code"
+	}
+	if len(body.PreferredFoot) > 0 && !validFoot(body.PreferredFoot) {
+		return "unknown preferred_foot " + body.PreferredFoot
+	}
+
+	if body.HeightCm != 0 && (body.HeightCm < minHeight || body.HeightCm > maxHeight) {
+		return fmt.Sprintf("height_cm must be between %d and %d", minHeight, maxHeight)
+	}
+	if body.WeightKg != 0 && (body.WeightKg < minWeight || body.WeightKg > maxWeight) {
+		return fmt.Sprintf("weight_kg must be between %d and %d", minWeight, maxWeight)
+	}
+	if body.ShirtNumber != 0 && (body.ShirtNumber < 1 || body.ShirtNumber > maxShirtNumber) {
+		return fmt.Sprintf("shirt_number must be between 1 and %d", maxShirtNumber)
+	}
+
+	return ""
+}
+
+// validatePositions returns the validation message of the playable positions of a player, empty if valid
+func validatePositions(main string, positions []string) string {
+
+	if len(positions) <= 0 {
+		return ""
+	}
+
+	seen := map[string]bool{}
+	for _, p := range positions {
+		if !position.Valid(p) {
+			return "unknown position " + p
+		}
+		if seen[p] {
+			return "duplicate position " + p
+		}
+		seen[p] = true
+	}
+
+	if !seen[main] {
+		return "positions must contain the position " + main
+	}
+
+	return ""
+}
+
+func validateDateOfBirth(date string) string {
+
+	dob, err := time.Parse(model.DateLayout, date)
+	if err != nil {
+		return "date_of_birth must be a date like 1986-04-16"
+	}
+	if dob.Before(minDateOfBirth) || dob.After(time.Now()) {
+		return "date_of_birth must be a past date"
+	}
+
+	return ""
+}
+
+func validFoot(foot string) bool {
+	for _, f := range model.Feet {
+		if f == foot {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePlayerPatch validates a player merge patch and returns it with typed and normalized values
+func parsePlayerPatch(body map[string]interface{}) (map[string]interface{}, string) {
+
+	patch := map[string]interface{}{}
+
+	for k, v := range body {
+		switch k {
+		case "name":
+			if s, ok := v.(string); !ok || len(s) <= 0 {
+				return nil, k + " cannot be empty"
+			}
+			patch[k] = v
+		case "position":
+			s, ok := v.(string)
+			if !ok || len(s) <= 0 {
+				return nil, k + " cannot be empty"
+			}
+			code, known := position.Normalize(s)
+			if !known {
+				return nil, "unknown position " + s
+			}
+			patch[k] = code
+		case "nickname":
+			if _, ok := v.(string); !ok && v != nil {
+				return nil, k + " must be a string"
+			}
+			patch[k] = v
+		case "date_of_birth", "nationality", "preferred_foot":
+			if v == nil {
+				patch[k] = nil
+				continue
+			}
+			s, ok := v.(string)
+			if !ok || len(s) <= 0 {
+				return nil, k + " must be a non-empty string"
+			}
+
+			var p model.Player
+			switch k {
+			case "date_of_birth":
+				p.DateOfBirth = s
+			case "nationality":
+				s = country.Normalize(s)
+				p.Nationality = s
+			case "preferred_foot":
+				p.PreferredFoot = s
+			}
+			if msg := validateProfile(p); len(msg) > 0 {
+				return nil, msg
+			}
+			patch[k] = s
+		case "height_cm", "weight_kg", "shirt_number":
+			if v == nil {
+				patch[k] = nil
+				continue
+			}
+			n, ok := v.(float64)
+			if !ok || n != float64(int(n)) || n == 0 {
+				return nil, k + " must be a non-zero integer"
+			}
+
+			var p model.Player
+			switch k {
+			case "height_cm":
+				p.HeightCm = int(n)
+			case "weight_kg":
+				p.WeightKg = int(n)
+			case "shirt_number":
+				p.ShirtNumber = int(n)
+			}
+			if msg := validateProfile(p); len(msg) > 0 {
+				return nil, msg
+			}
+			patch[k] = int(n)
+		case "positions":
+			if v == nil {
+				patch[k] = nil
+				continue
+			}
+			positions := []string{}
+			b, _ := json.Marshal(v)
+			if json.Unmarshal(b, &positions) != nil {
+				return nil, k + " must be an array of strings"
+			}
+			for i := range positions {
+				positions[i], _ = position.Normalize(positions[i])
+			}
+			patch[k] = positions
+		default:
+			return nil, k + " cannot be patched"
+		}
+	}
+
+	return patch, ""
+}
@@ -1,11 +1,13 @@
 package player
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/labstack/echo/v4"
@@ -13,6 +15,8 @@ import (
 	"github.com/yezarela/go-soccer/model"
 	playerMock "github.com/yezarela/go-soccer/module/player/mock"
 	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/memdb"
+	"github.com/yezarela/go-soccer/pkg/query"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -101,7 +105,7 @@ func TestPost(t *testing.T) {
 
 		mockPlayer := model.Player{}
 		mockPlayer.Name = "Ronaldo"
-		mockPlayer.Position = "Captain"
+		mockPlayer.Position = "ST"
 
 		mockPayload, _ := json.Marshal(mockPlayer)
 		mockResp, _ := json.Marshal(api.Response{
@@ -160,6 +164,91 @@ func TestPost(t *testing.T) {
 			assert.Equal(t, http.StatusBadRequest, rec.Code)
 		}
 	})
+
+	t.Run("Response Created Profile", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPayload := `{"name":"Luka Modric","position":"midfielder","positions":["midfielder","forward"],"date_of_birth":"1985-09-09",
+			"nationality":"hr","preferred_foot":"right","height_cm":172,"weight_kg":66,"shirt_number":10}`
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/players", strings.NewReader(mockPayload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		// The nationality is stored in upper case and the positions as codes
+		mockRepo.EXPECT().CreatePlayer(ctx, gomock.Any()).DoAndReturn(func(_ interface{}, data model.Player) (*model.Player, error) {
+			assert.Equal(t, "HR", data.Nationality)
+			assert.Equal(t, "CM", data.Position)
+			assert.Equal(t, []string{"CM", "ST"}, data.Positions)
+			assert.Equal(t, 172, data.HeightCm)
+			return &data, nil
+		})
+
+		// Assertions
+		if assert.NoError(t, h.Post(c)) {
+			assert.Equal(t, http.StatusCreated, rec.Code)
+
+			var res struct {
+				Data struct {
+					Age int `json:"age"`
+				} `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			assert.Equal(t, *model.Player{DateOfBirth: "1985-09-09"}.AgeAt(time.Now()), res.Data.Age)
+		}
+	})
+
+	t.Run("Response Bad Request Profile", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		payloads := []string{
+			`{"name":"Ronaldo","position":"captain"}`,
+			`{"name":"Ronaldo","position":"forward","positions":["forward","winger"]}`,
+			`{"name":"Ronaldo","position":"forward","positions":["midfielder"]}`,
+			`{"name":"Ronaldo","position":"forward","positions":["forward","ST"]}`,
+			`{"name":"Ronaldo","position":"forward","date_of_birth":"05/02/1985"}`,
+			`{"name":"Ronaldo","position":"forward","date_of_birth":"2999-01-01"}`,
+			`{"name":"Ronaldo","position":"forward","nationality":"POR"}`,
+			`{"name":"Ronaldo","position":"forward","preferred_foot":"none"}`,
+			`{"name":"Ronaldo","position":"forward","height_cm":18}`,
+			`{"name":"Ronaldo","position":"forward","weight_kg":-80}`,
+			`{"name":"Ronaldo","position":"forward","shirt_number":100}`,
+		}
+
+		for _, mockPayload := range payloads {
+
+			// Setup
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/players", strings.NewReader(mockPayload))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := &Handler{
+				playerRepo: mockRepo,
+			}
+
+			// Assertions
+			if assert.NoError(t, h.Post(c)) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code, mockPayload)
+			}
+		}
+	})
 }
 
 func TestGetAll(t *testing.T) {
@@ -173,12 +262,14 @@ func TestGetAll(t *testing.T) {
 		mockPlayer := model.Player{}
 		mockPlayer.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
 		mockPlayers := []model.Player{mockPlayer}
+		mockPage := &query.Page{}
 
 		mockResp, _ := json.Marshal(api.Response{
 			Meta: api.ResponseMeta{
 				Code: http.StatusOK,
 			},
-			Data: mockPlayers,
+			Data:       mockPlayers,
+			Pagination: mockPage,
 		})
 
 		// Setup
@@ -193,12 +284,600 @@ func TestGetAll(t *testing.T) {
 
 		ctx := c.Request().Context()
 
-		mockRepo.EXPECT().ListPlayer(ctx).Return(mockPlayers, nil)
+		mockRepo.EXPECT().ListPlayer(ctx, query.Params{Limit: query.DefaultLimit, Sort: query.DefaultSort}).Return(mockPlayers, mockPage, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+			assert.Empty(t, rec.Header().Get("Link"))
+		}
+	})
+
+	t.Run("Response OK With Next Page", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPlayer := model.Player{}
+		mockPlayer.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockPlayers := []model.Player{mockPlayer}
+
+		mockPlayer.CreatedAt = time.Date(2020, 9, 22, 20, 24, 1, 0, time.UTC)
+		mockParams := query.Params{Limit: 1, Sort: query.DefaultSort}
+		mockCursor := query.Cursor{Sort: "created_at,_id", Values: []interface{}{mockPlayer.CreatedAt, mockPlayer.ID}}
+		mockPage := &query.Page{NextCursor: mockParams.NextCursor(map[string]interface{}{"created_at": mockPlayer.CreatedAt, "_id": mockPlayer.ID}), HasMore: true}
+		mockParams.Cursor = &mockCursor
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/players?limit=1&cursor="+mockPage.NextCursor, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().ListPlayer(ctx, mockParams).Return(mockPlayers, mockPage, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "<http://example.com/players?cursor="+mockPage.NextCursor+"&limit=1>; rel=\"next\"", rec.Header().Get("Link"))
+		}
+	})
+
+	t.Run("Response OK With Filters", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPlayers := []model.Player{}
+		mockPage := &query.Page{}
+		mockParams := query.Params{
+			Limit: query.DefaultLimit,
+			Filters: []query.Filter{
+				{Field: "created_at", Op: "gte", Value: time.Date(2020, 9, 22, 0, 0, 0, 0, time.UTC)},
+				{Field: "position", Op: "in", Value: values(model.PositionStriker)},
+			},
+			Sort: []query.Sort{{Field: "name", Desc: true}, {Field: "_id"}},
+		}
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/players?position=forward&created_at[gte]=2020-09-22&sort=-name", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().ListPlayer(ctx, mockParams).Return(mockPlayers, mockPage, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("Response OK With Position Codes", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPlayers := []model.Player{}
+		mockPage := &query.Page{}
+		mockParams := query.Params{
+			Limit: query.DefaultLimit,
+			Filters: []query.Filter{
+				{Field: "position", Op: "in", Value: append(append(values(model.PositionStriker), values(model.PositionLeftBack)...), "sweeper")},
+			},
+			Sort: query.DefaultSort,
+		}
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/players?position[in]=ST,left-back,sweeper", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		// Codes and aliases match the codes, unknown values are kept
+		mockRepo.EXPECT().ListPlayer(ctx, mockParams).Return(mockPlayers, mockPage, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("Response OK With Legacy Positions", func(t *testing.T) {
+
+		// Setup
+		repo := NewMemoryRepository(memdb.New())
+		ctx := context.Background()
+
+		legacy, _ := repo.CreatePlayer(ctx, model.Player{Name: "Legacy", Position: "Forward"})
+		normalized, _ := repo.CreatePlayer(ctx, model.Player{Name: "Normalized", Position: model.PositionStriker})
+		repo.CreatePlayer(ctx, model.Player{Name: "Keeper", Position: model.PositionGoalkeeper})
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/players?position=forward&sort=name", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			playerRepo: repo,
+		}
+
+		// Assertions, the players not normalized yet are found too
+		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var res struct {
+				Data []model.Player `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+			if assert.Len(t, res.Data, 2) {
+				assert.Equal(t, legacy.ID, res.Data[0].ID)
+				assert.Equal(t, normalized.ID, res.Data[1].ID)
+			}
+		}
+	})
+
+	t.Run("Response Bad Request Unknown Field", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/players?foo=bar", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/players?cursor=invalid", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
 
 		// Assertions
 		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+}
+
+func TestPut(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPlayer := model.Player{}
+		mockPlayer.Name = "Ronaldo"
+		mockPlayer.Position = "ST"
+		mockPlayerID := "5f6a5d6129b2289c40b7444b"
+
+		mockPayload, _ := json.Marshal(mockPlayer)
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockPlayer,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/players/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockPlayerID)
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().UpdatePlayer(ctx, mockPlayerID, mockPlayer).Return(&mockPlayer, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Put(c)) {
 			assert.Equal(t, http.StatusOK, rec.Code)
 			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
 		}
 	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPlayer := model.Player{}
+		mockPlayer.Name = "Ronaldo"
+
+		mockPayload, _ := json.Marshal(mockPlayer)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/players/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("5f6a5d6129b2289c40b7444b")
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.Put(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPlayer := model.Player{}
+		mockPlayer.Name = "Ronaldo"
+		mockPlayer.Position = "ST"
+		mockPlayerID := "5f6a5d6129b2289c40b7444b"
+
+		mockPayload, _ := json.Marshal(mockPlayer)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/players/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockPlayerID)
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().UpdatePlayer(ctx, mockPlayerID, mockPlayer).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Put(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+}
+
+func TestPatch(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPlayer := model.Player{}
+		mockPlayer.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockPlayer.Name = "Ronaldo"
+		mockPlayer.Position = "ST"
+
+		mockPatch := map[string]interface{}{"name": "Ronaldo", "nickname": nil}
+
+		mockPayload, _ := json.Marshal(mockPatch)
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockPlayer,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/players/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockPlayer.ID.Hex())
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().PatchPlayer(ctx, mockPlayer.ID.Hex(), mockPatch).Return(&mockPlayer, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Patch(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPayload, _ := json.Marshal(map[string]interface{}{"position": nil})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/players/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("5f6a5d6129b2289c40b7444b")
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.Patch(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Response OK Profile", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPlayer := model.Player{}
+		mockPlayer.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockPlayer.Name = "Ronaldo"
+		mockPlayer.Position = "ST"
+
+		mockPayload := `{"positions":["lw","forward"],"nationality":"pt","shirt_number":7,"height_cm":null}`
+		mockPatch := map[string]interface{}{
+			"positions":    []string{"LW", "ST"},
+			"nationality":  "PT",
+			"shirt_number": 7,
+			"height_cm":    nil,
+		}
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(mockPayload))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/players/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockPlayer.ID.Hex())
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		// The positions are checked against the main position of the player
+		mockRepo.EXPECT().GetPlayer(ctx, mockPlayer.ID.Hex()).Return(&mockPlayer, nil)
+		mockRepo.EXPECT().PatchPlayer(ctx, mockPlayer.ID.Hex(), mockPatch).Return(&mockPlayer, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Patch(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request Profile", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPlayer := model.Player{}
+		mockPlayer.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockPlayer.Name = "Ronaldo"
+		mockPlayer.Position = "ST"
+		mockPlayer.Positions = []string{"ST", "LW"}
+
+		mockRepo.EXPECT().GetPlayer(gomock.Any(), mockPlayer.ID.Hex()).Return(&mockPlayer, nil).AnyTimes()
+
+		payloads := []string{
+			`{"position":"keeper"}`,
+			`{"position":"captain"}`,
+			`{"positions":["LW"]}`,
+			`{"positions":["ST","winger"]}`,
+			`{"positions":"forward"}`,
+			`{"date_of_birth":""}`,
+			`{"nationality":"Portugal"}`,
+			`{"preferred_foot":1}`,
+			`{"height_cm":180.5}`,
+			`{"shirt_number":0}`,
+		}
+
+		for _, mockPayload := range payloads {
+
+			// Setup
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(mockPayload))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			c.SetPath("/players/:id")
+			c.SetParamNames("id")
+			c.SetParamValues(mockPlayer.ID.Hex())
+
+			h := &Handler{
+				playerRepo: mockRepo,
+			}
+
+			// Assertions
+			if assert.NoError(t, h.Patch(c)) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code, mockPayload)
+			}
+		}
+	})
+}
+
+func TestDelete(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPlayer := model.Player{}
+		mockPlayer.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockPlayer,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/players/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockPlayer.ID.Hex())
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().DeletePlayer(ctx, mockPlayer.ID.Hex()).Return(&mockPlayer, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Delete(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := playerMock.NewMockRepository(ctrl)
+		mockPlayerID := "5f6a5d6129b2289c40b7444b"
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/players/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockPlayerID)
+
+		h := &Handler{
+			playerRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().DeletePlayer(ctx, mockPlayerID).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Delete(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+}
+
+func TestGetPositions(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/positions", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{}
+
+		// Assertions
+		if assert.NoError(t, h.GetPositions(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var res struct {
+				Data []model.PositionInfo `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			if assert.Len(t, res.Data, len(model.Positions)) {
+				assert.Equal(t, model.PositionGoalkeeper, res.Data[0].Code)
+				assert.Equal(t, model.LineGoalkeeper, res.Data[0].Line)
+			}
+		}
+	})
 }
@@ -0,0 +1,173 @@
+package player
+
+import (
+	"context"
+	"sort"
+
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/memdb"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryRepository struct {
+	db *memdb.DB
+}
+
+// NewMemoryRepository creates a new player repository stored in memory
+func NewMemoryRepository(db *memdb.DB) Repository {
+	return &memoryRepository{db}
+}
+
+// ListPlayer returns a filtered and sorted page of players
+func (repo *memoryRepository) ListPlayer(ctx context.Context, params query.Params) ([]model.Player, *query.Page, error) {
+	repo.db.RLock()
+	defer repo.db.RUnlock()
+
+	var items []model.Player
+
+	for _, p := range repo.db.Players {
+		if params.Match(cursorValues(p)) {
+			items = append(items, p)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return params.Less(cursorValues(items[i]), cursorValues(items[j]))
+	})
+
+	page, n, err := params.NewPage(len(items), func(i int) map[string]interface{} {
+		return cursorValues(items[i])
+	}, query.Total(len(repo.db.Players)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return items[:n], page, nil
+}
+
+// GetPlayer returns a player by id
+func (repo *memoryRepository) GetPlayer(ctx context.Context, id string) (*model.Player, error) {
+	repo.db.RLock()
+	defer repo.db.RUnlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	data, ok := repo.db.Players[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	return &data, nil
+}
+
+// CreatePlayer creates a new player
+func (repo *memoryRepository) CreatePlayer(ctx context.Context, data model.Player) (*model.Player, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	data.ID = primitive.NewObjectID()
+	data.CreatedAt = storage.Now()
+
+	repo.db.Players[data.ID] = data
+
+	return &data, nil
+}
+
+// UpdatePlayer replaces the fields of a player
+func (repo *memoryRepository) UpdatePlayer(ctx context.Context, id string, data model.Player) (*model.Player, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	p, ok := repo.db.Players[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	data.ID, data.CreatedAt = p.ID, p.CreatedAt
+	p = data
+
+	repo.db.Players[oid] = p
+
+	return &p, nil
+}
+
+// PatchPlayer applies a typed merge patch to a player, null values remove the field
+func (repo *memoryRepository) PatchPlayer(ctx context.Context, id string, patch map[string]interface{}) (*model.Player, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	p, ok := repo.db.Players[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	applyPatch(&p, patch)
+
+	repo.db.Players[oid] = p
+
+	return &p, nil
+}
+
+// DeletePlayer deletes a player and removes it from the teams it belongs to
+func (repo *memoryRepository) DeletePlayer(ctx context.Context, id string) (*model.Player, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	data, ok := repo.db.Players[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	delete(repo.db.Players, oid)
+
+	for tid, t := range repo.db.Teams {
+		players := []primitive.ObjectID{}
+		for _, pid := range t.Players {
+			if pid != oid {
+				players = append(players, pid)
+			}
+		}
+		t.Players = players
+		repo.db.Teams[tid] = t
+	}
+
+	return &data, nil
+}
+
+// SearchPlayer returns the players matching a text search by relevance
+func (repo *memoryRepository) SearchPlayer(ctx context.Context, q string, limit int) ([]model.SearchResult, error) {
+	repo.db.RLock()
+	defer repo.db.RUnlock()
+
+	res := []model.SearchResult{}
+
+	for _, p := range repo.db.Players {
+		score := storage.TextScore(q, storage.Text{Value: p.Name, Weight: 10}, storage.Text{Value: p.Nickname, Weight: 5})
+		if score > 0 {
+			res = append(res, model.SearchResult{Type: model.SearchTypePlayer, Score: score, Data: p})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Score > res[j].Score
+	})
+
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
+
+// EnsureIndexes does nothing, the in-memory storage has no indexes
+func (repo *memoryRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,100 @@
+package player
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/position"
+	"github.com/yezarela/go-soccer/pkg/query"
+)
+
+// Migration represents the result of a migration of the stored players
+type Migration struct {
+	Scanned int
+	Updated int
+	// Unknown are the positions which cannot be normalized by player id, they are left as is
+	Unknown map[string][]string
+}
+
+// NormalizePositions rewrites the stored positions of every player to the codes of the taxonomy
+func NormalizePositions(ctx context.Context, repo Repository, dryRun bool) (*Migration, error) {
+
+	op := "player.NormalizePositions"
+
+	res := &Migration{Unknown: map[string][]string{}}
+
+	err := query.All(url.Values{}, Fields, func(params query.Params) (*query.Page, error) {
+
+		players, page, err := repo.ListPlayer(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range players {
+			res.Scanned++
+
+			patch, unknown := positionsPatch(p)
+			if len(unknown) > 0 {
+				res.Unknown[p.ID.Hex()] = unknown
+			}
+			if len(patch) <= 0 {
+				continue
+			}
+
+			res.Updated++
+			if dryRun {
+				continue
+			}
+
+			if _, err := repo.PatchPlayer(ctx, p.ID.Hex(), patch); err != nil {
+				return nil, err
+			}
+		}
+
+		return page, nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return res, nil
+}
+
+// positionsPatch returns the merge patch normalizing the positions of a player and the unknown ones
+func positionsPatch(p model.Player) (map[string]interface{}, []string) {
+
+	patch := map[string]interface{}{}
+	unknown := []string{}
+
+	code, ok := position.Normalize(p.Position)
+	if !ok {
+		unknown = append(unknown, p.Position)
+	}
+	if code != p.Position {
+		patch["position"] = code
+	}
+
+	if len(p.Positions) > 0 {
+		// Aliases of the same position, like forward and striker, are kept once
+		positions := []string{}
+		seen := map[string]bool{}
+		for _, v := range p.Positions {
+			code, ok := position.Normalize(v)
+			if !ok {
+				unknown = append(unknown, v)
+			}
+			if !seen[code] {
+				seen[code] = true
+				positions = append(positions, code)
+			}
+		}
+		if !reflect.DeepEqual(positions, p.Positions) {
+			patch["positions"] = positions
+		}
+	}
+
+	return patch, unknown
+}
@@ -0,0 +1,81 @@
+package player
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/memdb"
+	"github.com/yezarela/go-soccer/pkg/query"
+)
+
+func TestNormalizePositions(t *testing.T) {
+
+	ctx := context.Background()
+
+	// Setup, more players than a page so the migration goes through the pages
+	repo := NewMemoryRepository(memdb.New())
+
+	legacy := []string{"forward", "FW", "Striker", "st", "ST"}
+	rounds := query.MaxLimit/len(legacy) + 1
+	for i := 0; i < rounds; i++ {
+		for _, v := range legacy {
+			_, err := repo.CreatePlayer(ctx, model.Player{Name: "Striker", Position: v})
+			require.NoError(t, err)
+		}
+	}
+
+	// The players already using ST are not updated
+	updated := rounds*(len(legacy)-1) + 1
+
+	keeper, err := repo.CreatePlayer(ctx, model.Player{Name: "Keeper", Position: "Keeper", Positions: []string{"keeper", "sweeper"}})
+	require.NoError(t, err)
+
+	forward, err := repo.CreatePlayer(ctx, model.Player{Name: "Forward", Position: "forward", Positions: []string{"forward", "striker", "CM", "st"}})
+	require.NoError(t, err)
+	updated++
+
+	t.Run("Dry Run", func(t *testing.T) {
+
+		res, err := NormalizePositions(ctx, repo, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, rounds*len(legacy)+2, res.Scanned)
+		assert.Equal(t, updated, res.Updated)
+		assert.Equal(t, map[string][]string{keeper.ID.Hex(): {"sweeper"}}, res.Unknown)
+
+		p, err := repo.GetPlayer(ctx, keeper.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "Keeper", p.Position)
+	})
+
+	t.Run("Migrate", func(t *testing.T) {
+
+		res, err := NormalizePositions(ctx, repo, false)
+		require.NoError(t, err)
+		assert.Equal(t, updated, res.Updated)
+
+		// Unknown positions are kept along with the normalized ones
+		p, err := repo.GetPlayer(ctx, keeper.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, model.PositionGoalkeeper, p.Position)
+		assert.Equal(t, []string{model.PositionGoalkeeper, "sweeper"}, p.Positions)
+
+		// The aliases of the same position are only kept once
+		p, err = repo.GetPlayer(ctx, forward.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, model.PositionStriker, p.Position)
+		assert.Equal(t, []string{model.PositionStriker, model.PositionCentralMidfielder}, p.Positions)
+
+		players, _, err := repo.ListPlayer(ctx, query.Params{Limit: query.MaxLimit, Sort: query.DefaultSort, Filters: []query.Filter{{Field: "position", Op: "eq", Value: "ST"}}})
+		require.NoError(t, err)
+		assert.Len(t, players, query.MaxLimit)
+
+		// A second run has nothing left to update
+		res, err = NormalizePositions(ctx, repo, false)
+		require.NoError(t, err)
+		assert.Equal(t, 0, res.Updated)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/yezarela/go-soccer/model"
+	query "github.com/yezarela/go-soccer/pkg/query"
 	reflect "reflect"
 )
 
@@ -35,18 +36,19 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // ListPlayer mocks base method
-func (m *MockRepository) ListPlayer(ctx context.Context) ([]model.Player, error) {
+func (m *MockRepository) ListPlayer(ctx context.Context, params query.Params) ([]model.Player, *query.Page, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPlayer", ctx)
+	ret := m.ctrl.Call(m, "ListPlayer", ctx, params)
 	ret0, _ := ret[0].([]model.Player)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(*query.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // ListPlayer indicates an expected call of ListPlayer
-func (mr *MockRepositoryMockRecorder) ListPlayer(ctx interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) ListPlayer(ctx, params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPlayer", reflect.TypeOf((*MockRepository)(nil).ListPlayer), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPlayer", reflect.TypeOf((*MockRepository)(nil).ListPlayer), ctx, params)
 }
 
 // GetPlayer mocks base method
@@ -78,3 +80,77 @@ func (mr *MockRepositoryMockRecorder) CreatePlayer(ctx, data interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePlayer", reflect.TypeOf((*MockRepository)(nil).CreatePlayer), ctx, data)
 }
+
+// UpdatePlayer mocks base method
+func (m *MockRepository) UpdatePlayer(ctx context.Context, id string, data model.Player) (*model.Player, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePlayer", ctx, id, data)
+	ret0, _ := ret[0].(*model.Player)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePlayer indicates an expected call of UpdatePlayer
+func (mr *MockRepositoryMockRecorder) UpdatePlayer(ctx, id, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePlayer", reflect.TypeOf((*MockRepository)(nil).UpdatePlayer), ctx, id, data)
+}
+
+// PatchPlayer mocks base method
+func (m *MockRepository) PatchPlayer(ctx context.Context, id string, patch map[string]interface{}) (*model.Player, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchPlayer", ctx, id, patch)
+	ret0, _ := ret[0].(*model.Player)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchPlayer indicates an expected call of PatchPlayer
+func (mr *MockRepositoryMockRecorder) PatchPlayer(ctx, id, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchPlayer", reflect.TypeOf((*MockRepository)(nil).PatchPlayer), ctx, id, patch)
+}
+
+// DeletePlayer mocks base method
+func (m *MockRepository) DeletePlayer(ctx context.Context, id string) (*model.Player, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePlayer", ctx, id)
+	ret0, _ := ret[0].(*model.Player)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePlayer indicates an expected call of DeletePlayer
+func (mr *MockRepositoryMockRecorder) DeletePlayer(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePlayer", reflect.TypeOf((*MockRepository)(nil).DeletePlayer), ctx, id)
+}
+
+// SearchPlayer mocks base method
+func (m *MockRepository) SearchPlayer(ctx context.Context, q string, limit int) ([]model.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchPlayer", ctx, q, limit)
+	ret0, _ := ret[0].([]model.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchPlayer indicates an expected call of SearchPlayer
+func (mr *MockRepositoryMockRecorder) SearchPlayer(ctx, q, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchPlayer", reflect.TypeOf((*MockRepository)(nil).SearchPlayer), ctx, q, limit)
+}
+
+// EnsureIndexes mocks base method
+func (m *MockRepository) EnsureIndexes(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureIndexes", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureIndexes indicates an expected call of EnsureIndexes
+func (mr *MockRepositoryMockRecorder) EnsureIndexes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureIndexes", reflect.TypeOf((*MockRepository)(nil).EnsureIndexes), ctx)
+}
@@ -6,44 +6,158 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/query"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // Repository represents repository of pkg player
 type Repository interface {
-	ListPlayer(ctx context.Context) ([]model.Player, error)
+	ListPlayer(ctx context.Context, params query.Params) ([]model.Player, *query.Page, error)
 	GetPlayer(ctx context.Context, id string) (*model.Player, error)
 	CreatePlayer(ctx context.Context, data model.Player) (*model.Player, error)
+	UpdatePlayer(ctx context.Context, id string, data model.Player) (*model.Player, error)
+	PatchPlayer(ctx context.Context, id string, patch map[string]interface{}) (*model.Player, error)
+	DeletePlayer(ctx context.Context, id string) (*model.Player, error)
+	SearchPlayer(ctx context.Context, q string, limit int) ([]model.SearchResult, error)
+	EnsureIndexes(ctx context.Context) error
 }
 
 type repository struct {
 	db *mongo.Database
 }
 
+// Fields are the filterable and sortable fields of a player
+var Fields = query.Fields{
+	"name":       query.String,
+	"nickname":   query.String,
+	"position":   query.String,
+	"created_at": query.Time,
+}
+
+// cursorValues returns the sortable values of a player
+func cursorValues(data model.Player) map[string]interface{} {
+	return map[string]interface{}{
+		"name":       data.Name,
+		"nickname":   data.Nickname,
+		"position":   data.Position,
+		"created_at": data.CreatedAt,
+		"_id":        data.ID,
+	}
+}
+
+// Document returns the stored fields of a player but its id and creation date, without empty profile fields
+func Document(data model.Player) bson.M {
+
+	doc := bson.M{
+		"name":     data.Name,
+		"nickname": data.Nickname,
+		"position": data.Position,
+	}
+
+	for k, v := range profile(data) {
+		if v != nil {
+			doc[k] = v
+		}
+	}
+
+	return doc
+}
+
+// profile returns the profile fields of a player, nil when empty
+func profile(data model.Player) map[string]interface{} {
+
+	res := map[string]interface{}{
+		"positions":      nil,
+		"date_of_birth":  nil,
+		"nationality":    nil,
+		"preferred_foot": nil,
+		"height_cm":      nil,
+		"weight_kg":      nil,
+		"shirt_number":   nil,
+	}
+
+	if len(data.Positions) > 0 {
+		res["positions"] = data.Positions
+	}
+	if len(data.DateOfBirth) > 0 {
+		res["date_of_birth"] = data.DateOfBirth
+	}
+	if len(data.Nationality) > 0 {
+		res["nationality"] = data.Nationality
+	}
+	if len(data.PreferredFoot) > 0 {
+		res["preferred_foot"] = data.PreferredFoot
+	}
+	if data.HeightCm != 0 {
+		res["height_cm"] = data.HeightCm
+	}
+	if data.WeightKg != 0 {
+		res["weight_kg"] = data.WeightKg
+	}
+	if data.ShirtNumber != 0 {
+		res["shirt_number"] = data.ShirtNumber
+	}
+
+	return res
+}
+
+// applyPatch applies a typed merge patch to a player, nil values remove the field
+func applyPatch(p *model.Player, patch map[string]interface{}) {
+
+	for k, v := range patch {
+		switch k {
+		case "name":
+			p.Name, _ = v.(string)
+		case "nickname":
+			p.Nickname, _ = v.(string)
+		case "position":
+			p.Position, _ = v.(string)
+		case "positions":
+			p.Positions, _ = v.([]string)
+		case "date_of_birth":
+			p.DateOfBirth, _ = v.(string)
+		case "nationality":
+			p.Nationality, _ = v.(string)
+		case "preferred_foot":
+			p.PreferredFoot, _ = v.(string)
+		case "height_cm":
+			p.HeightCm, _ = v.(int)
+		case "weight_kg":
+			p.WeightKg, _ = v.(int)
+		case "shirt_number":
+			p.ShirtNumber, _ = v.(int)
+		}
+	}
+}
+
 // NewRepository creates a new player repository
 func NewRepository(db *mongo.Database) Repository {
 	return &repository{db}
 }
 
-// ListPlayer returns list of players
-func (repo *repository) ListPlayer(ctx context.Context) ([]model.Player, error) {
+// ListPlayer returns a filtered and sorted page of players
+func (repo *repository) ListPlayer(ctx context.Context, params query.Params) ([]model.Player, *query.Page, error) {
 	op := "player.Repository.ListPlayer"
 
-	cur, err := repo.db.Collection("players").Find(ctx, bson.D{})
+	opts := options.Find().SetSort(params.MongoSort()).SetLimit(int64(params.FetchLimit()))
+
+	cur, err := repo.db.Collection("players").Find(ctx, params.MongoFilter(), opts)
 	if err != nil {
-		return nil, errors.Wrap(err, op)
+		return nil, nil, errors.Wrap(err, op)
 	}
 	defer cur.Close(ctx)
 
 	var items []model.Player
 
-	if err = cur.All(ctx, &items); err != nil {
-		return nil, errors.Wrap(err, op)
+	page, err := params.MongoPage(ctx, cur, &items, query.EstimatedCount(ctx, repo.db.Collection("players")))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
 	}
 
-	return items, nil
+	return items, page, nil
 }
 
 // GetPlayer returns a player by id
@@ -69,12 +183,8 @@ func (repo *repository) GetPlayer(ctx context.Context, id string) (*model.Player
 func (repo *repository) CreatePlayer(ctx context.Context, data model.Player) (*model.Player, error) {
 	op := "player.Repository.CreatePlayer"
 
-	body := bson.M{
-		"name":       data.Name,
-		"nickname":   data.Nickname,
-		"position":   data.Position,
-		"created_at": time.Now(),
-	}
+	body := Document(data)
+	body["created_at"] = time.Now()
 
 	res, err := repo.db.Collection("players").InsertOne(ctx, body)
 	if err != nil {
@@ -87,3 +197,164 @@ func (repo *repository) CreatePlayer(ctx context.Context, data model.Player) (*m
 
 	return nil, nil
 }
+
+// UpdatePlayer replaces the fields of a player
+func (repo *repository) UpdatePlayer(ctx context.Context, id string, data model.Player) (*model.Player, error) {
+	op := "player.Repository.UpdatePlayer"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	set := bson.M{
+		"name":     data.Name,
+		"nickname": data.Nickname,
+		"position": data.Position,
+	}
+	unset := bson.M{}
+
+	// The profile fields left empty are removed
+	for k, v := range profile(data) {
+		if v != nil {
+			set[k] = v
+		} else {
+			unset[k] = ""
+		}
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	res, err := repo.db.Collection("players").UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if res.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	return repo.GetPlayer(ctx, id)
+}
+
+// PatchPlayer applies a merge patch of typed values to a player, null values remove the field
+func (repo *repository) PatchPlayer(ctx context.Context, id string, patch map[string]interface{}) (*model.Player, error) {
+	op := "player.Repository.PatchPlayer"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	set := bson.M{}
+	unset := bson.M{}
+
+	for k, v := range patch {
+		if v == nil {
+			unset[k] = ""
+		} else {
+			set[k] = v
+		}
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	if len(update) == 0 {
+		return repo.GetPlayer(ctx, id)
+	}
+
+	res, err := repo.db.Collection("players").UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if res.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	return repo.GetPlayer(ctx, id)
+}
+
+// DeletePlayer deletes a player and removes it from the teams it belongs to
+func (repo *repository) DeletePlayer(ctx context.Context, id string) (*model.Player, error) {
+	op := "player.Repository.DeletePlayer"
+
+	data, err := repo.GetPlayer(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if data == nil {
+		return nil, nil
+	}
+
+	_, err = repo.db.Collection("players").DeleteOne(ctx, bson.M{"_id": data.ID})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	// Keep teams from referencing a player that no longer exists
+	_, err = repo.db.Collection("teams").UpdateMany(ctx, bson.M{"players": data.ID}, bson.M{"$pull": bson.M{"players": data.ID}})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return data, nil
+}
+
+// SearchPlayer returns the players matching a text search by relevance
+func (repo *repository) SearchPlayer(ctx context.Context, q string, limit int) ([]model.SearchResult, error) {
+	op := "player.Repository.SearchPlayer"
+
+	score := bson.M{"score": bson.M{"$meta": "textScore"}}
+	opts := options.Find().SetProjection(score).SetSort(score).SetLimit(int64(limit))
+
+	cur, err := repo.db.Collection("players").Find(ctx, bson.M{"$text": bson.M{"$search": q}}, opts)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+	defer cur.Close(ctx)
+
+	var items []struct {
+		model.Player `bson:",inline"`
+		Score        float64 `bson:"score"`
+	}
+
+	if err = cur.All(ctx, &items); err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	res := []model.SearchResult{}
+
+	for _, item := range items {
+		res = append(res, model.SearchResult{Type: model.SearchTypePlayer, Score: item.Score, Data: item.Player})
+	}
+
+	return res, nil
+}
+
+// EnsureIndexes creates the indexes of players, text indexes ignore diacritics
+func (repo *repository) EnsureIndexes(ctx context.Context) error {
+	op := "player.Repository.EnsureIndexes"
+
+	text := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "name", Value: "text"},
+			{Key: "nickname", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("players_text").
+			SetDefaultLanguage("none").
+			SetWeights(bson.M{"name": 10, "nickname": 5}),
+	}
+
+	_, err := repo.db.Collection("players").Indexes().CreateOne(ctx, text)
+	if err != nil {
+		return errors.Wrap(err, op)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package rating
+
+import (
+	"sync"
+
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/predict"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Snapshot represents what is computed from all the finished matches
+type Snapshot struct {
+	Ratings map[primitive.ObjectID]*model.Rating
+	Samples []predict.Sample
+	Model   predict.Model
+}
+
+// Cache keeps the ratings and the model of the predictions until a match changes, it implements match.Listener
+type Cache struct {
+	mu         sync.Mutex
+	generation uint64
+	snapshot   *Snapshot
+}
+
+// NewCache creates an empty cache of ratings
+func NewCache() *Cache {
+	return &Cache{}
+}
+
+// Get returns the cached snapshot, which must not be modified, the generation to pass to Set and whether it is valid
+func (c *Cache) Get() (*Snapshot, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.snapshot, c.generation, c.snapshot != nil
+}
+
+// Set caches the snapshot computed at a generation, it is dropped if a match changed in the meantime
+func (c *Cache) Set(gen uint64, snapshot *Snapshot) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation != gen {
+		return
+	}
+
+	c.snapshot = snapshot
+}
+
+// MatchChanged invalidates the snapshot
+func (c *Cache) MatchChanged(m model.Match) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.snapshot = nil
+}
@@ -0,0 +1,196 @@
+package rating
+
+import (
+	"context"
+	"net/url"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/module/team"
+	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/elo"
+	"github.com/yezarela/go-soccer/pkg/predict"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Handler represents the httphandler for ratings
+type Handler struct {
+	teamRepo  team.Repository
+	matchRepo match.Repository
+	cache     *Cache
+}
+
+// NewHandler initializes endpoints for ratings and predictions
+func NewHandler(e *echo.Echo, teamRepo team.Repository, matchRepo match.Repository, cache *Cache) {
+	handler := &Handler{
+		teamRepo:  teamRepo,
+		matchRepo: matchRepo,
+		cache:     cache,
+	}
+
+	e.GET("/teams/:id/rating", handler.GetTeam)
+	e.GET("/rankings", handler.GetRankings)
+	e.GET("/matches/:id/prediction", handler.GetPrediction)
+}
+
+// GetTeam returns the rating of a team and its history, a team without finished matches has the initial rating
+func (h *Handler) GetTeam(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	t, err := h.teamRepo.GetTeam(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if t == nil {
+		return api.ResponseNotFound(c, "cannot find the requested team")
+	}
+
+	snapshot, err := h.snapshot(ctx)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	return api.ResponseOK(c, ratingOf(snapshot.Ratings, t.ID))
+}
+
+// GetRankings returns the teams ranked by rating, teams with the same rating share their position
+func (h *Handler) GetRankings(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	limit, err := query.ParseLimit(c.QueryParam("limit"))
+	if err != nil {
+		return api.ResponseBadRequest(c, err.Error())
+	}
+
+	teams, err := team.ListAll(ctx, h.teamRepo, url.Values{})
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	snapshot, err := h.snapshot(ctx)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	return api.ResponseOK(c, rank(teams, snapshot.Ratings, limit))
+}
+
+// GetPrediction returns the probabilities of the outcomes of a match from the matches kicked off before it
+func (h *Handler) GetPrediction(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	m, err := h.matchRepo.GetMatch(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if m == nil {
+		return api.ResponseNotFound(c, "cannot find the requested match")
+	}
+
+	if m.Status == model.MatchStatusCancelled {
+		return api.ResponseConflict(c, "a cancelled match has no prediction")
+	}
+
+	snapshot, err := h.snapshot(ctx)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	home := model.PredictionTeam{TeamID: m.HomeTeamID}
+	home.Rating, home.Form = predict.Before(ratingOf(snapshot.Ratings, m.HomeTeamID), m.ID)
+
+	away := model.PredictionTeam{TeamID: m.AwayTeamID}
+	away.Rating, away.Form = predict.Before(ratingOf(snapshot.Ratings, m.AwayTeamID), m.ID)
+
+	fitted := snapshot.Model
+	if samples := predict.Until(snapshot.Samples, m.Kickoff); len(samples) < len(snapshot.Samples) {
+		fitted = predict.Fit(samples)
+	}
+
+	res := fitted.Predict(predict.Compare(home.Rating, away.Rating, home.Form, away.Form))
+	res.MatchID = m.ID
+	res.Home = home
+	res.Away = away
+
+	return api.ResponseOK(c, res)
+}
+
+// snapshot returns the ratings of the teams and the model of the predictions from all the finished matches
+func (h *Handler) snapshot(ctx context.Context) (*Snapshot, error) {
+
+	res, gen, ok := h.cache.Get()
+	if ok {
+		return res, nil
+	}
+
+	matches, err := match.ListAll(ctx, h.matchRepo, "", url.Values{"status": {model.MatchStatusFinished}})
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := elo.Rate(matches, elo.Default)
+	samples := predict.Samples(matches, ratings)
+	res = &Snapshot{
+		Ratings: ratings,
+		Samples: samples,
+		Model:   predict.Fit(samples),
+	}
+	h.cache.Set(gen, res)
+
+	return res, nil
+}
+
+// ratingOf returns the rating of a team, the initial rating if it played no finished match
+func ratingOf(ratings map[primitive.ObjectID]*model.Rating, teamID primitive.ObjectID) model.Rating {
+
+	if r, ok := ratings[teamID]; ok {
+		return *r
+	}
+
+	return model.Rating{TeamID: teamID, Rating: elo.Default.Initial, History: []model.RatingChange{}}
+}
+
+// rank returns the first rows of the ranking of teams by rating, then by matches played and name
+func rank(teams []model.Team, ratings map[primitive.ObjectID]*model.Rating, limit int) []model.Ranking {
+
+	res := []model.Ranking{}
+	for _, t := range teams {
+		r := ratingOf(ratings, t.ID)
+		res = append(res, model.Ranking{TeamID: t.ID, Name: t.Name, Rating: r.Rating, Played: r.Played})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if a.Rating != b.Rating {
+			return a.Rating > b.Rating
+		}
+		if a.Played != b.Played {
+			return a.Played > b.Played
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.TeamID.Hex() < b.TeamID.Hex()
+	})
+
+	for i := range res {
+		res[i].Position = i + 1
+		if i > 0 && res[i].Rating == res[i-1].Rating {
+			res[i].Position = res[i-1].Position
+		}
+	}
+
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res
+}
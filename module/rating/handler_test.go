@@ -0,0 +1,354 @@
+package rating
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	matchMock "github.com/yezarela/go-soccer/module/match/mock"
+	teamMock "github.com/yezarela/go-soccer/module/team/mock"
+	"github.com/yezarela/go-soccer/pkg/predict"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetTeam(t *testing.T) {
+
+	mockTeam := model.Team{}
+	mockTeam.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+
+	mockMatch := model.Match{}
+	mockMatch.ID = primitive.NewObjectID()
+	mockMatch.HomeTeamID = mockTeam.ID
+	mockMatch.AwayTeamID = primitive.NewObjectID()
+	mockMatch.Status = model.MatchStatusFinished
+	mockMatch.Score = model.Score{Home: 1, Away: 0}
+
+	get := func(h *Handler) *httptest.ResponseRecorder {
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id/rating")
+		c.SetParamNames("id")
+		c.SetParamValues(mockTeam.ID.Hex())
+
+		assert.NoError(t, h.GetTeam(c))
+
+		return rec
+	}
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		h := &Handler{
+			teamRepo:  mockTeamRepo,
+			matchRepo: mockMatchRepo,
+			cache:     NewCache(),
+		}
+
+		// The matches are read once until one changes
+		mockTeamRepo.EXPECT().GetTeam(gomock.Any(), mockTeam.ID.Hex()).Return(&mockTeam, nil).Times(3)
+		mockMatchRepo.EXPECT().ListMatch(gomock.Any(), "", gomock.Any()).Return([]model.Match{mockMatch}, &query.Page{}, nil).Times(2)
+
+		// Assertions
+		for i := 0; i < 2; i++ {
+			rec := get(h)
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var res struct {
+				Data model.Rating `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+			assert.Equal(t, 1507.2, res.Data.Rating)
+			assert.Equal(t, 1, res.Data.Played)
+			if assert.Len(t, res.Data.History, 1) {
+				assert.Equal(t, mockMatch.ID, res.Data.History[0].MatchID)
+				assert.Equal(t, 7.2, res.Data.History[0].Change)
+			}
+		}
+
+		h.cache.MatchChanged(mockMatch)
+		assert.Equal(t, http.StatusOK, get(h).Code)
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		h := &Handler{
+			teamRepo:  mockTeamRepo,
+			matchRepo: mockMatchRepo,
+			cache:     NewCache(),
+		}
+
+		mockTeamRepo.EXPECT().GetTeam(gomock.Any(), mockTeam.ID.Hex()).Return(nil, nil)
+
+		// Assertions
+		assert.Equal(t, http.StatusNotFound, get(h).Code)
+	})
+}
+
+func TestGetRankings(t *testing.T) {
+
+	newContext := func(target string) (echo.Context, *httptest.ResponseRecorder) {
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/rankings")
+
+		return c, rec
+	}
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		mockTeams := []model.Team{
+			{ID: primitive.NewObjectID(), Name: "Milan"},
+			{ID: primitive.NewObjectID(), Name: "Inter"},
+			{ID: primitive.NewObjectID(), Name: "Juventus"},
+			{ID: primitive.NewObjectID(), Name: "Atalanta"},
+		}
+
+		// Two teams drew without advantage, they lose and win the same points
+		mockMatch := model.Match{}
+		mockMatch.HomeTeamID = mockTeams[0].ID
+		mockMatch.AwayTeamID = mockTeams[1].ID
+		mockMatch.Status = model.MatchStatusFinished
+
+		// Setup
+		c, rec := newContext("/?limit=3")
+
+		h := &Handler{
+			teamRepo:  mockTeamRepo,
+			matchRepo: mockMatchRepo,
+			cache:     NewCache(),
+		}
+
+		mockTeamRepo.EXPECT().ListTeam(gomock.Any(), gomock.Any()).Return(mockTeams, &query.Page{}, nil)
+		mockMatchRepo.EXPECT().ListMatch(gomock.Any(), "", gomock.Any()).Return([]model.Match{mockMatch}, &query.Page{}, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetRankings(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var res struct {
+				Data []model.Ranking `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+			// The teams without matches share the initial rating, ordered by name
+			if assert.Len(t, res.Data, 3) {
+				assert.Equal(t, model.Ranking{Position: 1, TeamID: mockTeams[1].ID, Name: "Inter", Rating: 1502.8, Played: 1}, res.Data[0])
+				assert.Equal(t, model.Ranking{Position: 2, TeamID: mockTeams[3].ID, Name: "Atalanta", Rating: 1500}, res.Data[1])
+				assert.Equal(t, model.Ranking{Position: 2, TeamID: mockTeams[2].ID, Name: "Juventus", Rating: 1500}, res.Data[2])
+			}
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext("/?limit=0")
+
+		h := &Handler{
+			teamRepo:  mockTeamRepo,
+			matchRepo: mockMatchRepo,
+			cache:     NewCache(),
+		}
+
+		// Assertions
+		if assert.NoError(t, h.GetRankings(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+}
+
+func TestGetPrediction(t *testing.T) {
+
+	milan := primitive.NewObjectID()
+	inter := primitive.NewObjectID()
+
+	mockFinished := model.Match{}
+	mockFinished.ID = primitive.NewObjectID()
+	mockFinished.HomeTeamID = inter
+	mockFinished.AwayTeamID = milan
+	mockFinished.Kickoff = time.Date(2020, 10, 17, 15, 0, 0, 0, time.UTC)
+	mockFinished.Status = model.MatchStatusFinished
+	mockFinished.Score = model.Score{Home: 0, Away: 2}
+
+	mockMatch := model.Match{}
+	mockMatch.ID, _ = primitive.ObjectIDFromHex("5f8ad3d9c8e4a6b1b0e5d7a1")
+	mockMatch.HomeTeamID = milan
+	mockMatch.AwayTeamID = inter
+	mockMatch.Kickoff = mockFinished.Kickoff.AddDate(0, 0, 7)
+	mockMatch.Status = model.MatchStatusScheduled
+
+	newContext := func() (echo.Context, *httptest.ResponseRecorder) {
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/matches/:id/prediction")
+		c.SetParamNames("id")
+		c.SetParamValues(mockMatch.ID.Hex())
+
+		return c, rec
+	}
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext()
+
+		h := &Handler{
+			teamRepo:  mockTeamRepo,
+			matchRepo: mockMatchRepo,
+			cache:     NewCache(),
+		}
+
+		mockMatchRepo.EXPECT().GetMatch(gomock.Any(), mockMatch.ID.Hex()).Return(&mockMatch, nil)
+		mockMatchRepo.EXPECT().ListMatch(gomock.Any(), "", gomock.Any()).Return([]model.Match{mockFinished}, &query.Page{}, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetPrediction(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var res struct {
+				Data model.Prediction `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+			assert.Equal(t, mockMatch.ID, res.Data.MatchID)
+			assert.Equal(t, model.PredictionTeam{TeamID: milan, Rating: 1519.2, Form: "W"}, res.Data.Home)
+			assert.Equal(t, model.PredictionTeam{TeamID: inter, Rating: 1480.8, Form: "L"}, res.Data.Away)
+
+			// A single match between even teams only tells the average goals
+			assert.Equal(t, model.ExpectedScore{Home: 0.75, Away: 1.6}, res.Data.ExpectedScore)
+			assert.Equal(t, model.Score{Home: 0, Away: 1}, res.Data.LikelyScore)
+			assert.True(t, res.Data.AwayWin > res.Data.HomeWin)
+			assert.InDelta(t, 1, res.Data.HomeWin+res.Data.Draw+res.Data.AwayWin, 0.002)
+		}
+	})
+
+	t.Run("Response OK Finished", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext()
+
+		h := &Handler{
+			teamRepo:  mockTeamRepo,
+			matchRepo: mockMatchRepo,
+			cache:     NewCache(),
+		}
+
+		mockMatchRepo.EXPECT().GetMatch(gomock.Any(), mockMatch.ID.Hex()).Return(&mockFinished, nil)
+		mockMatchRepo.EXPECT().ListMatch(gomock.Any(), "", gomock.Any()).Return([]model.Match{mockFinished}, &query.Page{}, nil)
+
+		// Assertions, the match is not predicted from its own score
+		if assert.NoError(t, h.GetPrediction(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var res struct {
+				Data model.Prediction `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+			assert.Equal(t, model.PredictionTeam{TeamID: inter, Rating: 1500, Form: ""}, res.Data.Home)
+			assert.Equal(t, model.PredictionTeam{TeamID: milan, Rating: 1500, Form: ""}, res.Data.Away)
+			assert.Equal(t, model.ExpectedScore{Home: predict.Default.HomeGoals, Away: predict.Default.AwayGoals}, res.Data.ExpectedScore)
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext()
+
+		h := &Handler{
+			teamRepo:  mockTeamRepo,
+			matchRepo: mockMatchRepo,
+			cache:     NewCache(),
+		}
+
+		mockMatchRepo.EXPECT().GetMatch(gomock.Any(), mockMatch.ID.Hex()).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetPrediction(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("Response Conflict", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext()
+
+		h := &Handler{
+			teamRepo:  mockTeamRepo,
+			matchRepo: mockMatchRepo,
+			cache:     NewCache(),
+		}
+
+		cancelled := mockMatch
+		cancelled.Status = model.MatchStatusCancelled
+		mockMatchRepo.EXPECT().GetMatch(gomock.Any(), mockMatch.ID.Hex()).Return(&cancelled, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetPrediction(c)) {
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,1329 @@
+// Package repotest provides the conformance suite every storage of the repositories must pass
+package repotest
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/competition"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/module/player"
+	"github.com/yezarela/go-soccer/module/stats"
+	"github.com/yezarela/go-soccer/module/team"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// missingID is a valid id which is never stored
+const missingID = "5f6a5d6129b2289c40b7444b"
+
+// Repositories represents the repositories of a storage
+type Repositories struct {
+	Team        team.Repository
+	Player      player.Repository
+	Match       match.Repository
+	Competition competition.Repository
+	Stats       stats.Repository
+}
+
+// Factory returns empty repositories sharing the same storage and a func cleaning it up
+type Factory func(t *testing.T) (Repositories, func())
+
+// Run runs the conformance suite against the repositories of a factory
+func Run(t *testing.T, newRepos Factory) {
+
+	tests := map[string]func(t *testing.T, repos Repositories){
+		"Player Create And Get":       testPlayerCreateAndGet,
+		"Player Not Found":            testPlayerNotFound,
+		"Player List Order":           testPlayerListOrder,
+		"Player List Pagination":      testPlayerListPagination,
+		"Player List Filter And Sort": testPlayerListFilterAndSort,
+		"Player Update":               testPlayerUpdate,
+		"Player Delete":               testPlayerDelete,
+		"Player Search":               testPlayerSearch,
+		"Team Create And Get":         testTeamCreateAndGet,
+		"Team Not Found":              testTeamNotFound,
+		"Team List Pagination":        testTeamListPagination,
+		"Team List Missing Field":     testTeamListMissingField,
+		"Team Update":                 testTeamUpdate,
+		"Team Delete":                 testTeamDelete,
+		"Team Roster":                 testTeamRoster,
+		"Team Roster Concurrent":      testTeamRosterConcurrent,
+		"Team Search":                 testTeamSearch,
+		"Match Create And Get":        testMatchCreateAndGet,
+		"Match List":                  testMatchList,
+		"Match List Missing Field":    testMatchListMissingField,
+		"Match Patch":                 testMatchPatch,
+		"Match Events":                testMatchEvents,
+		"Match Lineups":               testMatchLineups,
+		"Competition Create And Get":  testCompetitionCreateAndGet,
+		"Competition List":            testCompetitionList,
+		"Competition Patch":           testCompetitionPatch,
+		"Competition Matches":         testCompetitionMatches,
+		"Stats":                       testStats,
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			repos, cleanup := newRepos(t)
+			defer cleanup()
+
+			ctx := context.Background()
+			require.NoError(t, repos.Team.EnsureIndexes(ctx))
+			require.NoError(t, repos.Player.EnsureIndexes(ctx))
+			require.NoError(t, repos.Match.EnsureIndexes(ctx))
+			require.NoError(t, repos.Competition.EnsureIndexes(ctx))
+			require.NoError(t, repos.Stats.EnsureIndexes(ctx))
+
+			test(t, repos)
+		})
+	}
+}
+
+func params(t *testing.T, rawQuery string, fields query.Fields) query.Params {
+
+	values, err := url.ParseQuery(rawQuery)
+	require.NoError(t, err)
+
+	p, err := query.ParseParams(values, fields)
+	require.NoError(t, err)
+
+	return p
+}
+
+func createPlayers(t *testing.T, playerRepo player.Repository, names ...string) []model.Player {
+
+	var res []model.Player
+
+	for i, name := range names {
+		position := "forward"
+		if i%2 == 1 {
+			position = "keeper"
+		}
+
+		p, err := playerRepo.CreatePlayer(context.Background(), model.Player{Name: name, Nickname: "nick " + name, Position: position})
+		require.NoError(t, err)
+		require.NotNil(t, p)
+
+		res = append(res, *p)
+	}
+
+	return res
+}
+
+func playerIDs(players []model.Player) []string {
+	var ids []string
+	for _, p := range players {
+		ids = append(ids, p.ID.Hex())
+	}
+	return ids
+}
+
+func teamIDs(teams []model.Team) []string {
+	var ids []string
+	for _, t := range teams {
+		ids = append(ids, t.ID.Hex())
+	}
+	return ids
+}
+
+func testPlayerCreateAndGet(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+
+	created, err := repos.Player.CreatePlayer(ctx, model.Player{Name: "John Doe", Nickname: "Lolo", Position: "forward"})
+	require.NoError(t, err)
+	require.NotNil(t, created)
+
+	assert.False(t, created.ID.IsZero())
+	assert.False(t, created.CreatedAt.IsZero())
+	assert.Equal(t, "John Doe", created.Name)
+	assert.Equal(t, "Lolo", created.Nickname)
+	assert.Equal(t, "forward", created.Position)
+
+	got, err := repos.Player.GetPlayer(ctx, created.ID.Hex())
+	require.NoError(t, err)
+	require.NotNil(t, got)
+
+	assert.Equal(t, created.ID, got.ID)
+	assert.Equal(t, created.Name, got.Name)
+	assert.True(t, created.CreatedAt.Equal(got.CreatedAt))
+	assert.Empty(t, got.Positions)
+	assert.Empty(t, got.DateOfBirth)
+
+	profile := model.Player{
+		Name:          "Luka Modric",
+		Position:      "midfielder",
+		Positions:     []string{"midfielder", "forward"},
+		DateOfBirth:   "1985-09-09",
+		Nationality:   "HR",
+		PreferredFoot: model.FootRight,
+		HeightCm:      172,
+		WeightKg:      66,
+		ShirtNumber:   10,
+	}
+
+	created, err = repos.Player.CreatePlayer(ctx, profile)
+	require.NoError(t, err)
+
+	got, err = repos.Player.GetPlayer(ctx, created.ID.Hex())
+	require.NoError(t, err)
+
+	profile.ID, profile.CreatedAt = got.ID, got.CreatedAt
+	assert.Equal(t, profile, *got)
+}
+
+func testPlayerNotFound(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+
+	for _, id := range []string{missingID, "invalid"} {
+		got, err := repos.Player.GetPlayer(ctx, id)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+
+		updated, err := repos.Player.UpdatePlayer(ctx, id, model.Player{Name: "John Doe", Position: "forward"})
+		assert.NoError(t, err)
+		assert.Nil(t, updated)
+
+		patched, err := repos.Player.PatchPlayer(ctx, id, map[string]interface{}{"name": "John Doe"})
+		assert.NoError(t, err)
+		assert.Nil(t, patched)
+
+		deleted, err := repos.Player.DeletePlayer(ctx, id)
+		assert.NoError(t, err)
+		assert.Nil(t, deleted)
+	}
+}
+
+func testPlayerListOrder(t *testing.T, repos Repositories) {
+
+	created := createPlayers(t, repos.Player, "C", "A", "B")
+
+	items, page, err := repos.Player.ListPlayer(context.Background(), params(t, "", player.Fields))
+	require.NoError(t, err)
+	require.NotNil(t, page)
+
+	assert.Equal(t, playerIDs(created), playerIDs(items))
+	assert.False(t, page.HasMore)
+	if assert.NotNil(t, page.Total) {
+		assert.Equal(t, int64(3), *page.Total)
+	}
+}
+
+func testPlayerListPagination(t *testing.T, repos Repositories) {
+
+	created := createPlayers(t, repos.Player, "A", "B", "C", "D", "E")
+
+	var listed []model.Player
+	cursor := ""
+
+	for i := 0; i < 3; i++ {
+		items, page, err := repos.Player.ListPlayer(context.Background(), params(t, "limit=2&cursor="+cursor, player.Fields))
+		require.NoError(t, err)
+
+		listed = append(listed, items...)
+		cursor = page.NextCursor
+
+		assert.Equal(t, i < 2, page.HasMore)
+	}
+
+	assert.Equal(t, playerIDs(created), playerIDs(listed))
+	assert.Empty(t, cursor)
+}
+
+func testPlayerListFilterAndSort(t *testing.T, repos Repositories) {
+
+	created := createPlayers(t, repos.Player, "A", "B", "C", "D")
+
+	// Forwards are A and C
+	items, page, err := repos.Player.ListPlayer(context.Background(), params(t, "position=forward&sort=-name", player.Fields))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{created[2].ID.Hex(), created[0].ID.Hex()}, playerIDs(items))
+	assert.Nil(t, page.Total)
+
+	items, _, err = repos.Player.ListPlayer(context.Background(), params(t, "name[in]=B,D&sort=name", player.Fields))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{created[1].ID.Hex(), created[3].ID.Hex()}, playerIDs(items))
+
+	// Pages of a sorted list
+	items, page, err = repos.Player.ListPlayer(context.Background(), params(t, "sort=-name&limit=3", player.Fields))
+	require.NoError(t, err)
+	require.True(t, page.HasMore)
+
+	assert.Equal(t, []string{created[3].ID.Hex(), created[2].ID.Hex(), created[1].ID.Hex()}, playerIDs(items))
+
+	items, page, err = repos.Player.ListPlayer(context.Background(), params(t, "sort=-name&limit=3&cursor="+page.NextCursor, player.Fields))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{created[0].ID.Hex()}, playerIDs(items))
+	assert.False(t, page.HasMore)
+}
+
+func testPlayerUpdate(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+	created := createPlayers(t, repos.Player, "A")[0]
+
+	updated, err := repos.Player.UpdatePlayer(ctx, created.ID.Hex(), model.Player{Name: "B", Position: "keeper"})
+	require.NoError(t, err)
+	require.NotNil(t, updated)
+
+	assert.Equal(t, created.ID, updated.ID)
+	assert.Equal(t, "B", updated.Name)
+	assert.Equal(t, "", updated.Nickname)
+	assert.Equal(t, "keeper", updated.Position)
+
+	patched, err := repos.Player.PatchPlayer(ctx, created.ID.Hex(), map[string]interface{}{"nickname": "Lolo"})
+	require.NoError(t, err)
+	require.NotNil(t, patched)
+
+	assert.Equal(t, "B", patched.Name)
+	assert.Equal(t, "Lolo", patched.Nickname)
+
+	patched, err = repos.Player.PatchPlayer(ctx, created.ID.Hex(), map[string]interface{}{"nickname": nil})
+	require.NoError(t, err)
+
+	assert.Equal(t, "", patched.Nickname)
+
+	got, err := repos.Player.GetPlayer(ctx, created.ID.Hex())
+	require.NoError(t, err)
+
+	assert.Equal(t, patched, got)
+
+	// The profile fields are typed in a patch and removed by null
+	patched, err = repos.Player.PatchPlayer(ctx, created.ID.Hex(), map[string]interface{}{
+		"positions":    []string{"keeper", "defender"},
+		"nationality":  "GB-SCT",
+		"shirt_number": 1,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"keeper", "defender"}, patched.Positions)
+	assert.Equal(t, "GB-SCT", patched.Nationality)
+	assert.Equal(t, 1, patched.ShirtNumber)
+
+	patched, err = repos.Player.PatchPlayer(ctx, created.ID.Hex(), map[string]interface{}{"shirt_number": nil, "positions": nil})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, patched.ShirtNumber)
+	assert.Empty(t, patched.Positions)
+	assert.Equal(t, "GB-SCT", patched.Nationality)
+
+	// Replacing a player removes the profile fields left empty
+	updated, err = repos.Player.UpdatePlayer(ctx, created.ID.Hex(), model.Player{Name: "B", Position: "keeper", HeightCm: 190})
+	require.NoError(t, err)
+
+	assert.Equal(t, 190, updated.HeightCm)
+	assert.Empty(t, updated.Nationality)
+	assert.True(t, created.CreatedAt.Equal(updated.CreatedAt))
+}
+
+func testPlayerDelete(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+
+	created, err := repos.Team.CreateTeam(ctx, model.Team{
+		Name:     "AC Milan",
+		Location: "Italy",
+		Players:  []model.Player{{Name: "A", Position: "forward"}, {Name: "B", Position: "keeper"}},
+	})
+	require.NoError(t, err)
+
+	deleted, err := repos.Player.DeletePlayer(ctx, created.Players[0].ID.Hex())
+	require.NoError(t, err)
+	require.NotNil(t, deleted)
+
+	assert.Equal(t, created.Players[0].ID, deleted.ID)
+
+	got, err := repos.Player.GetPlayer(ctx, deleted.ID.Hex())
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	// The player is removed from its team
+	team, err := repos.Team.GetTeam(ctx, created.ID.Hex())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{created.Players[1].ID.Hex()}, playerIDs(team.Players))
+}
+
+func testPlayerSearch(t *testing.T, repos Repositories) {
+
+	createPlayers(t, repos.Player, "Thomas Müller", "John Doe")
+
+	res, err := repos.Player.SearchPlayer(context.Background(), "muller", 10)
+	require.NoError(t, err)
+
+	if assert.Len(t, res, 1) {
+		assert.Equal(t, model.SearchTypePlayer, res[0].Type)
+		assert.True(t, res[0].Score > 0)
+		assert.Equal(t, "Thomas Müller", res[0].Data.(model.Player).Name)
+	}
+
+	res, err = repos.Player.SearchPlayer(context.Background(), "nobody", 10)
+	require.NoError(t, err)
+
+	assert.Empty(t, res)
+}
+
+func testTeamCreateAndGet(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+
+	created, err := repos.Team.CreateTeam(ctx, model.Team{
+		Name:        "AC Milan",
+		Description: "Rossoneri",
+		Location:    "Italy",
+		Players:     []model.Player{{Name: "A", Nickname: "a", Position: "forward"}, {Name: "B", Position: "keeper"}},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, created)
+
+	assert.False(t, created.ID.IsZero())
+	assert.Equal(t, "AC Milan", created.Name)
+	assert.Equal(t, "Rossoneri", created.Description)
+	assert.Equal(t, "Italy", created.Location)
+	require.Len(t, created.Players, 2)
+	assert.Equal(t, "A", created.Players[0].Name)
+	assert.Equal(t, "a", created.Players[0].Nickname)
+
+	got, err := repos.Team.GetTeam(ctx, created.ID.Hex())
+	require.NoError(t, err)
+	require.NotNil(t, got)
+
+	assert.Equal(t, created.ID, got.ID)
+	assert.Equal(t, playerIDs(created.Players), playerIDs(got.Players))
+
+	// The players of the team are players too
+	p, err := repos.Player.GetPlayer(ctx, created.Players[1].ID.Hex())
+	require.NoError(t, err)
+	require.NotNil(t, p)
+
+	assert.Equal(t, "B", p.Name)
+
+	// A team without players has an empty roster
+	empty, err := repos.Team.CreateTeam(ctx, model.Team{Name: "Inter", Location: "Italy"})
+	require.NoError(t, err)
+
+	got, err = repos.Team.GetTeam(ctx, empty.ID.Hex())
+	require.NoError(t, err)
+
+	assert.Empty(t, got.Players)
+}
+
+func testTeamNotFound(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+
+	for _, id := range []string{missingID, "invalid"} {
+		got, err := repos.Team.GetTeam(ctx, id)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+
+		updated, err := repos.Team.UpdateTeam(ctx, id, model.Team{Name: "AC Milan", Location: "Italy"})
+		assert.NoError(t, err)
+		assert.Nil(t, updated)
+
+		patched, err := repos.Team.PatchTeam(ctx, id, map[string]interface{}{"name": "AC Milan"})
+		assert.NoError(t, err)
+		assert.Nil(t, patched)
+
+		deleted, err := repos.Team.DeleteTeam(ctx, id)
+		assert.NoError(t, err)
+		assert.Nil(t, deleted)
+
+		added, err := repos.Team.AddPlayers(ctx, id, []string{missingID})
+		assert.NoError(t, err)
+		assert.Nil(t, added)
+
+		removed, err := repos.Team.RemovePlayer(ctx, id, missingID)
+		assert.NoError(t, err)
+		assert.Nil(t, removed)
+	}
+}
+
+func testTeamListPagination(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+
+	var created []model.Team
+	for _, name := range []string{"C", "A", "B"} {
+		res, err := repos.Team.CreateTeam(ctx, model.Team{Name: name, Location: "Italy", Players: []model.Player{{Name: name, Position: "forward"}}})
+		require.NoError(t, err)
+		created = append(created, *res)
+	}
+
+	items, page, err := repos.Team.ListTeam(ctx, params(t, "limit=2", team.Fields))
+	require.NoError(t, err)
+	require.True(t, page.HasMore)
+
+	assert.Equal(t, teamIDs(created[:2]), teamIDs(items))
+	if assert.NotNil(t, page.Total) {
+		assert.Equal(t, int64(3), *page.Total)
+	}
+
+	// Lists join the players of the teams
+	if assert.Len(t, items[0].Players, 1) {
+		assert.Equal(t, created[0].Players[0].ID, items[0].Players[0].ID)
+	}
+
+	items, page, err = repos.Team.ListTeam(ctx, params(t, "limit=2&cursor="+page.NextCursor, team.Fields))
+	require.NoError(t, err)
+
+	assert.Equal(t, teamIDs(created[2:]), teamIDs(items))
+	assert.False(t, page.HasMore)
+
+	items, _, err = repos.Team.ListTeam(ctx, params(t, "sort=name&name[ne]=B", team.Fields))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{created[1].ID.Hex(), created[0].ID.Hex()}, teamIDs(items))
+}
+
+// pageIDs returns the ids of every page of a list, one item at a time
+func pageIDs(t *testing.T, list func(cursor string) ([]string, *query.Page, error)) []string {
+
+	var res []string
+	cursor := ""
+
+	for i := 0; i < 10; i++ {
+		ids, page, err := list(cursor)
+		require.NoError(t, err)
+
+		res = append(res, ids...)
+		if !page.HasMore {
+			return res
+		}
+		cursor = page.NextCursor
+	}
+
+	t.Fatal("too many pages")
+	return nil
+}
+
+func testTeamListMissingField(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+
+	var created []model.Team
+	for _, desc := range []string{"x", "y", "", "z", "w"} {
+		res, err := repos.Team.CreateTeam(ctx, model.Team{Name: "Team " + desc, Description: desc})
+		require.NoError(t, err)
+		created = append(created, *res)
+	}
+
+	// Removed descriptions sort before the empty one
+	for _, c := range created[3:] {
+		_, err := repos.Team.PatchTeam(ctx, c.ID.Hex(), map[string]interface{}{"description": nil})
+		require.NoError(t, err)
+	}
+
+	for _, sort := range []string{"description", "-description"} {
+		ids := pageIDs(t, func(cursor string) ([]string, *query.Page, error) {
+			items, page, err := repos.Team.ListTeam(ctx, params(t, "limit=1&sort="+sort+"&cursor="+cursor, team.Fields))
+			return teamIDs(items), page, err
+		})
+
+		// Every team is listed once
+		assert.ElementsMatch(t, teamIDs(created), ids, sort)
+		if assert.Len(t, ids, len(created)) {
+			last := ids[len(ids)-1]
+			if sort == "description" {
+				assert.Equal(t, created[1].ID.Hex(), last)
+			} else {
+				assert.Equal(t, created[1].ID.Hex(), ids[0])
+			}
+		}
+	}
+}
+
+func testTeamUpdate(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+
+	created, err := repos.Team.CreateTeam(ctx, model.Team{Name: "AC Milan", Description: "Rossoneri", Location: "Italy", Players: []model.Player{{Name: "A", Position: "forward"}}})
+	require.NoError(t, err)
+
+	// The roster is left untouched
+	updated, err := repos.Team.UpdateTeam(ctx, created.ID.Hex(), model.Team{Name: "Milan", Location: "Milano"})
+	require.NoError(t, err)
+	require.NotNil(t, updated)
+
+	assert.Equal(t, "Milan", updated.Name)
+	assert.Equal(t, "", updated.Description)
+	assert.Equal(t, "Milano", updated.Location)
+	assert.Equal(t, playerIDs(created.Players), playerIDs(updated.Players))
+
+	patched, err := repos.Team.PatchTeam(ctx, created.ID.Hex(), map[string]interface{}{"description": "Diavolo"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Milan", patched.Name)
+	assert.Equal(t, "Diavolo", patched.Description)
+
+	patched, err = repos.Team.PatchTeam(ctx, created.ID.Hex(), map[string]interface{}{"description": nil})
+	require.NoError(t, err)
+
+	assert.Equal(t, "", patched.Description)
+	assert.Equal(t, playerIDs(created.Players), playerIDs(patched.Players))
+}
+
+func testTeamDelete(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+
+	created, err := repos.Team.CreateTeam(ctx, model.Team{Name: "AC Milan", Location: "Italy", Players: []model.Player{{Name: "A", Position: "forward"}}})
+	require.NoError(t, err)
+
+	deleted, err := repos.Team.DeleteTeam(ctx, created.ID.Hex())
+	require.NoError(t, err)
+	require.NotNil(t, deleted)
+
+	assert.Equal(t, created.ID, deleted.ID)
+
+	got, err := repos.Team.GetTeam(ctx, created.ID.Hex())
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	// The players are kept
+	p, err := repos.Player.GetPlayer(ctx, created.Players[0].ID.Hex())
+	assert.NoError(t, err)
+	assert.NotNil(t, p)
+}
+
+func testTeamRoster(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+
+	milan, err := repos.Team.CreateTeam(ctx, model.Team{Name: "AC Milan", Location: "Italy"})
+	require.NoError(t, err)
+	inter, err := repos.Team.CreateTeam(ctx, model.Team{Name: "Inter", Location: "Italy"})
+	require.NoError(t, err)
+
+	players := createPlayers(t, repos.Player, "A", "B")
+
+	// Add players, adding twice is a no-op
+	res, err := repos.Team.AddPlayers(ctx, milan.ID.Hex(), []string{players[0].ID.Hex(), players[1].ID.Hex(), players[0].ID.Hex()})
+	require.NoError(t, err)
+	require.NotNil(t, res)
+
+	assert.Equal(t, playerIDs(players), playerIDs(res.Players))
+
+	res, err = repos.Team.AddPlayers(ctx, milan.ID.Hex(), []string{players[0].ID.Hex()})
+	require.NoError(t, err)
+
+	assert.Equal(t, playerIDs(players), playerIDs(res.Players))
+
+	// A player belongs to one team only
+	_, err = repos.Team.AddPlayers(ctx, inter.ID.Hex(), []string{players[0].ID.Hex()})
+	assert.Equal(t, team.ErrPlayerHasTeam, errors.Cause(err))
+
+	_, err = repos.Team.AddPlayers(ctx, inter.ID.Hex(), []string{missingID})
+	assert.Equal(t, team.ErrPlayerNotFound, errors.Cause(err))
+
+	// Transfer
+	res, err = repos.Team.TransferPlayer(ctx, players[0].ID.Hex(), milan.ID.Hex(), inter.ID.Hex())
+	require.NoError(t, err)
+	require.NotNil(t, res)
+
+	assert.Equal(t, inter.ID, res.ID)
+	assert.Equal(t, []string{players[0].ID.Hex()}, playerIDs(res.Players))
+
+	got, err := repos.Team.GetTeam(ctx, milan.ID.Hex())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{players[1].ID.Hex()}, playerIDs(got.Players))
+
+	_, err = repos.Team.TransferPlayer(ctx, players[0].ID.Hex(), milan.ID.Hex(), inter.ID.Hex())
+	assert.Equal(t, team.ErrPlayerNotInTeam, errors.Cause(err))
+
+	res, err = repos.Team.TransferPlayer(ctx, players[1].ID.Hex(), milan.ID.Hex(), missingID)
+	assert.NoError(t, err)
+	assert.Nil(t, res)
+
+	// Remove
+	res, err = repos.Team.RemovePlayer(ctx, milan.ID.Hex(), players[1].ID.Hex())
+	require.NoError(t, err)
+
+	assert.Empty(t, res.Players)
+
+	_, err = repos.Team.RemovePlayer(ctx, milan.ID.Hex(), players[1].ID.Hex())
+	assert.Equal(t, team.ErrPlayerNotInTeam, errors.Cause(err))
+}
+
+func testTeamRosterConcurrent(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+
+	teams := []model.Team{}
+	for _, name := range []string{"A", "B", "C", "D", "E", "F"} {
+		created, err := repos.Team.CreateTeam(ctx, model.Team{Name: name})
+		require.NoError(t, err)
+		teams = append(teams, *created)
+	}
+
+	p := createPlayers(t, repos.Player, "A")[0]
+
+	// The teams race to sign the same player, only one of them can
+	errs := make([]error, len(teams))
+	var wg sync.WaitGroup
+	for i := range teams {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = repos.Team.AddPlayers(ctx, teams[i].ID.Hex(), []string{p.ID.Hex()})
+		}(i)
+	}
+	wg.Wait()
+
+	signed := 0
+	for i, err := range errs {
+		if err != nil {
+			assert.Equal(t, team.ErrPlayerHasTeam, errors.Cause(err))
+			continue
+		}
+
+		signed++
+		got, err := repos.Team.GetTeam(ctx, teams[i].ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, []string{p.ID.Hex()}, playerIDs(got.Players))
+	}
+	assert.Equal(t, 1, signed)
+
+	// The empty rosters do not conflict
+	_, err := repos.Team.CreateTeam(ctx, model.Team{Name: "G"})
+	assert.NoError(t, err)
+}
+
+func testTeamSearch(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+
+	_, err := repos.Team.CreateTeam(ctx, model.Team{Name: "Bayern München", Location: "Germany"})
+	require.NoError(t, err)
+	_, err = repos.Team.CreateTeam(ctx, model.Team{Name: "TSV 1860", Description: "The other club of Munchen", Location: "Germany"})
+	require.NoError(t, err)
+
+	// The name weighs more than the description
+	res, err := repos.Team.SearchTeam(ctx, "munchen", 10)
+	require.NoError(t, err)
+
+	if assert.Len(t, res, 2) {
+		assert.Equal(t, model.SearchTypeTeam, res[0].Type)
+		assert.Equal(t, "Bayern München", res[0].Data.(model.Team).Name)
+		assert.True(t, res[0].Score > res[1].Score)
+	}
+
+	res, err = repos.Team.SearchTeam(ctx, "germany", 1)
+	require.NoError(t, err)
+
+	assert.Len(t, res, 1)
+}
+
+func createMatch(t *testing.T, repos Repositories, home, away model.Team, kickoff time.Time) model.Match {
+
+	m, err := repos.Match.CreateMatch(context.Background(), model.Match{
+		HomeTeamID: home.ID,
+		AwayTeamID: away.ID,
+		Kickoff:    kickoff,
+		Venue:      home.Location,
+		Status:     model.MatchStatusScheduled,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, m)
+
+	return *m
+}
+
+func createTeams(t *testing.T, repos Repositories, names ...string) []model.Team {
+
+	var res []model.Team
+
+	for _, name := range names {
+		created, err := repos.Team.CreateTeam(context.Background(), model.Team{Name: name, Location: name + " Stadium"})
+		require.NoError(t, err)
+
+		res = append(res, *created)
+	}
+
+	return res
+}
+
+func matchIDs(matches []model.Match) []string {
+	var ids []string
+	for _, m := range matches {
+		ids = append(ids, m.ID.Hex())
+	}
+	return ids
+}
+
+func testMatchCreateAndGet(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+	teams := createTeams(t, repos, "Milan", "Inter")
+	kickoff := time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC)
+
+	created := createMatch(t, repos, teams[0], teams[1], kickoff)
+
+	assert.False(t, created.ID.IsZero())
+	assert.False(t, created.CreatedAt.IsZero())
+	assert.Equal(t, teams[0].ID, created.HomeTeamID)
+	assert.Equal(t, teams[1].ID, created.AwayTeamID)
+	assert.True(t, kickoff.Equal(created.Kickoff))
+	assert.Equal(t, "Milan Stadium", created.Venue)
+	assert.Equal(t, model.MatchStatusScheduled, created.Status)
+
+	got, err := repos.Match.GetMatch(ctx, created.ID.Hex())
+	require.NoError(t, err)
+	require.NotNil(t, got)
+
+	assert.Equal(t, created.ID, got.ID)
+	assert.True(t, kickoff.Equal(got.Kickoff))
+	assert.Equal(t, model.Score{}, got.Score)
+
+	for _, id := range []string{missingID, "invalid"} {
+		got, err := repos.Match.GetMatch(ctx, id)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+
+		patched, err := repos.Match.PatchMatch(ctx, id, map[string]interface{}{"status": model.MatchStatusLive})
+		assert.NoError(t, err)
+		assert.Nil(t, patched)
+
+		deleted, err := repos.Match.DeleteMatch(ctx, id)
+		assert.NoError(t, err)
+		assert.Nil(t, deleted)
+	}
+
+	deleted, err := repos.Match.DeleteMatch(ctx, created.ID.Hex())
+	require.NoError(t, err)
+	require.NotNil(t, deleted)
+	assert.Equal(t, created.ID, deleted.ID)
+
+	got, err = repos.Match.GetMatch(ctx, created.ID.Hex())
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func testMatchList(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+	teams := createTeams(t, repos, "Milan", "Inter", "Juventus")
+
+	day := time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC)
+	matches := []model.Match{
+		createMatch(t, repos, teams[0], teams[1], day.AddDate(0, 0, 14)),
+		createMatch(t, repos, teams[1], teams[2], day),
+		createMatch(t, repos, teams[2], teams[0], day.AddDate(0, 0, 7)),
+	}
+
+	// The matches of a team on both sides
+	items, page, err := repos.Match.ListMatch(ctx, teams[0].ID.Hex(), params(t, "sort=kickoff", match.Fields))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{matches[2].ID.Hex(), matches[0].ID.Hex()}, matchIDs(items))
+	assert.Nil(t, page.Total)
+
+	// A date range
+	items, _, err = repos.Match.ListMatch(ctx, "", params(t, "kickoff[gte]=2020-10-17&kickoff[lt]=2020-10-25&sort=-kickoff", match.Fields))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{matches[2].ID.Hex(), matches[1].ID.Hex()}, matchIDs(items))
+
+	// Pages of the schedule
+	items, page, err = repos.Match.ListMatch(ctx, "", params(t, "sort=kickoff&limit=2", match.Fields))
+	require.NoError(t, err)
+	require.True(t, page.HasMore)
+
+	assert.Equal(t, []string{matches[1].ID.Hex(), matches[2].ID.Hex()}, matchIDs(items))
+	if assert.NotNil(t, page.Total) {
+		assert.Equal(t, int64(3), *page.Total)
+	}
+
+	items, page, err = repos.Match.ListMatch(ctx, "", params(t, "sort=kickoff&limit=2&cursor="+page.NextCursor, match.Fields))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{matches[0].ID.Hex()}, matchIDs(items))
+	assert.False(t, page.HasMore)
+}
+
+func testMatchListMissingField(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+	teams := createTeams(t, repos, "Milan", "Inter")
+
+	day := time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC)
+	competitionID := primitive.NewObjectID()
+
+	league, err := repos.Match.CreateMatch(ctx, model.Match{
+		CompetitionID: &competitionID,
+		HomeTeamID:    teams[0].ID,
+		AwayTeamID:    teams[1].ID,
+		Kickoff:       day,
+		Status:        model.MatchStatusScheduled,
+	})
+	require.NoError(t, err)
+
+	friendlies := []model.Match{
+		createMatch(t, repos, teams[1], teams[0], day.AddDate(0, 0, 7)),
+		createMatch(t, repos, teams[0], teams[1], day.AddDate(0, 0, 14)),
+	}
+
+	// Friendlies have no competition, they sort first
+	ids := pageIDs(t, func(cursor string) ([]string, *query.Page, error) {
+		items, page, err := repos.Match.ListMatch(ctx, "", params(t, "limit=1&sort=competition_id&cursor="+cursor, match.Fields))
+		return matchIDs(items), page, err
+	})
+	assert.Equal(t, []string{friendlies[0].ID.Hex(), friendlies[1].ID.Hex(), league.ID.Hex()}, ids)
+
+	ids = pageIDs(t, func(cursor string) ([]string, *query.Page, error) {
+		items, page, err := repos.Match.ListMatch(ctx, "", params(t, "limit=1&sort=-competition_id&cursor="+cursor, match.Fields))
+		return matchIDs(items), page, err
+	})
+	assert.Equal(t, []string{league.ID.Hex(), friendlies[0].ID.Hex(), friendlies[1].ID.Hex()}, ids)
+}
+
+func testMatchPatch(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+	teams := createTeams(t, repos, "Milan", "Inter")
+	created := createMatch(t, repos, teams[0], teams[1], time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC))
+
+	kickoff := time.Date(2020, 10, 18, 18, 0, 0, 0, time.UTC)
+
+	patched, err := repos.Match.PatchMatch(ctx, created.ID.Hex(), map[string]interface{}{
+		"kickoff":   kickoff,
+		"status":    model.MatchStatusFinished,
+		"venue":     nil,
+		"penalties": &model.Score{Home: 5, Away: 4},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, patched)
+
+	assert.True(t, kickoff.Equal(patched.Kickoff))
+	assert.Equal(t, model.MatchStatusFinished, patched.Status)
+	assert.Equal(t, "", patched.Venue)
+	assert.Equal(t, created.HomeTeamID, patched.HomeTeamID)
+	assert.Equal(t, &model.Score{Home: 5, Away: 4}, patched.Penalties)
+
+	got, err := repos.Match.GetMatch(ctx, created.ID.Hex())
+	require.NoError(t, err)
+
+	assert.Equal(t, patched.Status, got.Status)
+	assert.Equal(t, patched.Penalties, got.Penalties)
+
+	// The shootout is removed with null
+	patched, err = repos.Match.PatchMatch(ctx, created.ID.Hex(), map[string]interface{}{"penalties": nil})
+	require.NoError(t, err)
+
+	assert.Nil(t, patched.Penalties)
+}
+
+func testMatchEvents(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+	teams := createTeams(t, repos, "Milan", "Inter")
+	created := createMatch(t, repos, teams[0], teams[1], time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC))
+
+	assert.Empty(t, created.Events)
+	assert.Equal(t, model.Score{}, created.Score)
+
+	add := func(e model.MatchEvent) model.Match {
+		res, err := repos.Match.AddEvent(ctx, created.ID.Hex(), e)
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		return *res
+	}
+
+	player := primitive.NewObjectID()
+
+	add(model.MatchEvent{Type: model.EventGoal, Minute: 70, TeamID: &teams[1].ID, PlayerID: &player})
+	add(model.MatchEvent{Type: model.EventOwnGoal, Minute: 10, TeamID: &teams[1].ID, PlayerID: &player})
+	res := add(model.MatchEvent{Type: model.EventYellowCard, Minute: 45, AddedTime: 1, TeamID: &teams[0].ID, PlayerID: &player})
+
+	// Events are ordered by time and the score is derived from the goals
+	if assert.Len(t, res.Events, 3) {
+		assert.Equal(t, model.EventOwnGoal, res.Events[0].Type)
+		assert.Equal(t, model.EventYellowCard, res.Events[1].Type)
+		assert.Equal(t, 1, res.Events[1].AddedTime)
+		assert.Equal(t, model.EventGoal, res.Events[2].Type)
+		assert.Equal(t, &teams[1].ID, res.Events[2].TeamID)
+		assert.Nil(t, res.Events[2].PlayerInID)
+	}
+	assert.Equal(t, model.Score{Home: 1, Away: 1}, res.Score)
+
+	// The VAR overturns the goal
+	goal := res.Events[2]
+	res = add(model.MatchEvent{Type: model.EventVAR, Minute: 72, EventID: &goal.ID, Detail: "offside"})
+
+	assert.Equal(t, model.Score{Home: 1, Away: 0}, res.Score)
+
+	got, err := repos.Match.GetMatch(ctx, created.ID.Hex())
+	require.NoError(t, err)
+
+	assert.Equal(t, res.Events, got.Events)
+	assert.Equal(t, res.Score, got.Score)
+
+	items, _, err := repos.Match.ListMatch(ctx, "", params(t, "", match.Fields))
+	require.NoError(t, err)
+
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, res.Score, items[0].Score)
+	}
+
+	// Remove the own goal
+	res2, err := repos.Match.RemoveEvent(ctx, created.ID.Hex(), res.Events[0].ID.Hex())
+	require.NoError(t, err)
+	require.NotNil(t, res2)
+
+	assert.Len(t, res2.Events, 3)
+	assert.Equal(t, model.Score{}, res2.Score)
+
+	_, err = repos.Match.RemoveEvent(ctx, created.ID.Hex(), res.Events[0].ID.Hex())
+	assert.Equal(t, match.ErrEventNotFound, errors.Cause(err))
+
+	for _, id := range []string{missingID, "invalid"} {
+		added, err := repos.Match.AddEvent(ctx, id, model.MatchEvent{Type: model.EventGoal, Minute: 1})
+		assert.NoError(t, err)
+		assert.Nil(t, added)
+
+		removed, err := repos.Match.RemoveEvent(ctx, id, goal.ID.Hex())
+		assert.NoError(t, err)
+		assert.Nil(t, removed)
+	}
+}
+
+func testMatchLineups(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+	teams := createTeams(t, repos, "Milan", "Inter")
+	created := createMatch(t, repos, teams[0], teams[1], time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC))
+
+	assert.Nil(t, created.Lineups)
+
+	lineup := model.Lineup{TeamID: teams[0].ID, Formation: "4-3-3", Starters: []model.LineupPlayer{}, Bench: []model.LineupPlayer{}}
+	for i := 1; i <= 13; i++ {
+		p := model.LineupPlayer{PlayerID: primitive.NewObjectID(), ShirtNumber: i}
+		if i <= model.Starters {
+			p.Position = model.PositionCentreBack
+			lineup.Starters = append(lineup.Starters, p)
+		} else {
+			lineup.Bench = append(lineup.Bench, p)
+		}
+	}
+
+	res, err := repos.Match.SetLineups(ctx, created.ID.Hex(), model.Lineups{Home: &lineup})
+	require.NoError(t, err)
+	require.NotNil(t, res)
+
+	if assert.NotNil(t, res.Lineups) {
+		assert.Equal(t, &lineup, res.Lineups.Home)
+		assert.Nil(t, res.Lineups.Away)
+	}
+
+	got, err := repos.Match.GetMatch(ctx, created.ID.Hex())
+	require.NoError(t, err)
+
+	assert.Equal(t, res.Lineups, got.Lineups)
+
+	// The lineups are replaced
+	away := lineup
+	away.TeamID = teams[1].ID
+	away.Formation = "4-4-2"
+
+	res, err = repos.Match.SetLineups(ctx, created.ID.Hex(), model.Lineups{Away: &away})
+	require.NoError(t, err)
+
+	if assert.NotNil(t, res.Lineups) {
+		assert.Nil(t, res.Lineups.Home)
+		assert.Equal(t, "4-4-2", res.Lineups.Away.Formation)
+	}
+
+	for _, id := range []string{missingID, "invalid"} {
+		set, err := repos.Match.SetLineups(ctx, id, model.Lineups{})
+		assert.NoError(t, err)
+		assert.Nil(t, set)
+	}
+}
+
+func createCompetition(t *testing.T, repos Repositories, name string, teams ...model.Team) model.Competition {
+
+	ids := []primitive.ObjectID{}
+	for _, team := range teams {
+		ids = append(ids, team.ID)
+	}
+
+	c, err := repos.Competition.CreateCompetition(context.Background(), model.Competition{
+		Name:        name,
+		Season:      "2020-21",
+		TeamIDs:     ids,
+		Points:      model.Points{Win: 3, Draw: 1},
+		Tiebreakers: []string{model.TiebreakerHeadToHead, model.TiebreakerGoalDifference},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, c)
+
+	return *c
+}
+
+func testCompetitionCreateAndGet(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+	teams := createTeams(t, repos, "Milan", "Inter")
+
+	created := createCompetition(t, repos, "Serie A", teams...)
+
+	assert.False(t, created.ID.IsZero())
+	assert.False(t, created.CreatedAt.IsZero())
+	assert.Equal(t, "Serie A", created.Name)
+
+	got, err := repos.Competition.GetCompetition(ctx, created.ID.Hex())
+	require.NoError(t, err)
+	require.NotNil(t, got)
+
+	assert.Equal(t, created.ID, got.ID)
+	assert.Equal(t, "2020-21", got.Season)
+	assert.Equal(t, []primitive.ObjectID{teams[0].ID, teams[1].ID}, got.TeamIDs)
+	assert.Equal(t, model.Points{Win: 3, Draw: 1}, got.Points)
+	assert.Equal(t, []string{model.TiebreakerHeadToHead, model.TiebreakerGoalDifference}, got.Tiebreakers)
+	assert.Nil(t, got.Knockout)
+
+	cup, err := repos.Competition.CreateCompetition(ctx, model.Competition{
+		Name:     "Coppa Italia",
+		Type:     model.CompetitionTypeKnockout,
+		TeamIDs:  []primitive.ObjectID{teams[0].ID, teams[1].ID},
+		Knockout: &model.KnockoutRules{Legs: 2, AwayGoals: true},
+	})
+	require.NoError(t, err)
+
+	got, err = repos.Competition.GetCompetition(ctx, cup.ID.Hex())
+	require.NoError(t, err)
+
+	assert.True(t, got.IsKnockout())
+	assert.Equal(t, &model.KnockoutRules{Legs: 2, AwayGoals: true}, got.Knockout)
+	assert.Nil(t, got.GroupStage)
+
+	rules := &model.GroupRules{
+		Groups:     []model.Group{{Name: "A", TeamIDs: []primitive.ObjectID{teams[0].ID, teams[1].ID}}},
+		Legs:       2,
+		Advance:    1,
+		BestPlaced: 1,
+	}
+
+	euro, err := repos.Competition.CreateCompetition(ctx, model.Competition{
+		Name:       "Euro",
+		Type:       model.CompetitionTypeGroups,
+		TeamIDs:    []primitive.ObjectID{teams[0].ID, teams[1].ID},
+		Knockout:   &model.KnockoutRules{Legs: 1},
+		GroupStage: rules,
+	})
+	require.NoError(t, err)
+
+	got, err = repos.Competition.GetCompetition(ctx, euro.ID.Hex())
+	require.NoError(t, err)
+
+	assert.True(t, got.HasGroups())
+	assert.Equal(t, rules, got.GroupStage)
+
+	for _, id := range []string{missingID, "invalid"} {
+		got, err := repos.Competition.GetCompetition(ctx, id)
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+
+		patched, err := repos.Competition.PatchCompetition(ctx, id, map[string]interface{}{"name": "Serie B"})
+		assert.NoError(t, err)
+		assert.Nil(t, patched)
+	}
+}
+
+func testCompetitionList(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+
+	for _, name := range []string{"Serie A", "Premier League", "La Liga"} {
+		createCompetition(t, repos, name)
+	}
+
+	items, page, err := repos.Competition.ListCompetition(ctx, params(t, "sort=name&limit=2", competition.Fields))
+	require.NoError(t, err)
+	require.True(t, page.HasMore)
+
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, "La Liga", items[0].Name)
+		assert.Equal(t, "Premier League", items[1].Name)
+	}
+	if assert.NotNil(t, page.Total) {
+		assert.Equal(t, int64(3), *page.Total)
+	}
+
+	items, page, err = repos.Competition.ListCompetition(ctx, params(t, "sort=name&limit=2&cursor="+page.NextCursor, competition.Fields))
+	require.NoError(t, err)
+
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, "Serie A", items[0].Name)
+	}
+	assert.False(t, page.HasMore)
+
+	items, _, err = repos.Competition.ListCompetition(ctx, params(t, "name=Serie A", competition.Fields))
+	require.NoError(t, err)
+
+	assert.Len(t, items, 1)
+}
+
+func testCompetitionPatch(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+	teams := createTeams(t, repos, "Milan", "Inter", "Juventus")
+	created := createCompetition(t, repos, "Serie A", teams[0], teams[1])
+
+	patched, err := repos.Competition.PatchCompetition(ctx, created.ID.Hex(), map[string]interface{}{
+		"season":      nil,
+		"team_ids":    []primitive.ObjectID{teams[0].ID, teams[1].ID, teams[2].ID},
+		"points":      model.Points{Win: 2, Draw: 1},
+		"tiebreakers": []string{model.TiebreakerFairPlay},
+		"knockout":    &model.KnockoutRules{Legs: 1},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, patched)
+
+	assert.Equal(t, "Serie A", patched.Name)
+	assert.Equal(t, "", patched.Season)
+	assert.Len(t, patched.TeamIDs, 3)
+	assert.Equal(t, model.Points{Win: 2, Draw: 1}, patched.Points)
+	assert.Equal(t, []string{model.TiebreakerFairPlay}, patched.Tiebreakers)
+	assert.Equal(t, &model.KnockoutRules{Legs: 1}, patched.Knockout)
+
+	got, err := repos.Competition.GetCompetition(ctx, created.ID.Hex())
+	require.NoError(t, err)
+
+	assert.Equal(t, patched.TeamIDs, got.TeamIDs)
+	assert.Equal(t, patched.Points, got.Points)
+}
+
+func testCompetitionMatches(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+	teams := createTeams(t, repos, "Milan", "Inter")
+	comp := createCompetition(t, repos, "Serie A", teams...)
+
+	kickoff := time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC)
+
+	// A friendly and a match of the competition
+	createMatch(t, repos, teams[0], teams[1], kickoff)
+
+	m, err := repos.Match.CreateMatch(ctx, model.Match{
+		CompetitionID: &comp.ID,
+		HomeTeamID:    teams[1].ID,
+		AwayTeamID:    teams[0].ID,
+		Kickoff:       kickoff.AddDate(0, 0, 7),
+		Status:        model.MatchStatusScheduled,
+	})
+	require.NoError(t, err)
+
+	if assert.NotNil(t, m.CompetitionID) {
+		assert.Equal(t, comp.ID, *m.CompetitionID)
+	}
+
+	items, err := match.ListAll(ctx, repos.Match, "", url.Values{"competition_id": {comp.ID.Hex()}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{m.ID.Hex()}, matchIDs(items))
+
+	// Friendlies have no competition
+	items, _, err = repos.Match.ListMatch(ctx, "", params(t, "sort=competition_id", match.Fields))
+	require.NoError(t, err)
+
+	if assert.Len(t, items, 2) {
+		assert.Nil(t, items[0].CompetitionID)
+	}
+}
+
+func testStats(t *testing.T, repos Repositories) {
+
+	ctx := context.Background()
+	teams := createTeams(t, repos, "Milan", "Inter")
+	comp := createCompetition(t, repos, "Serie A", teams...)
+	players := createPlayers(t, repos.Player, "Zlatan Ibrahimovic", "Rafael Leao")
+	striker, winger := players[0].ID, players[1].ID
+
+	friendly := func(status string, events ...model.MatchEvent) {
+		m := createMatch(t, repos, teams[0], teams[1], time.Date(2020, 10, 17, 16, 45, 0, 0, time.UTC))
+		_, err := repos.Match.PatchMatch(ctx, m.ID.Hex(), map[string]interface{}{"status": status})
+		require.NoError(t, err)
+		for _, e := range events {
+			_, err := repos.Match.AddEvent(ctx, m.ID.Hex(), e)
+			require.NoError(t, err)
+		}
+	}
+
+	goal := model.MatchEvent{Type: model.EventGoal, Minute: 10, TeamID: &teams[0].ID, PlayerID: &striker, AssistID: &winger}
+
+	// Only the finished matches are counted
+	friendly(model.MatchStatusFinished, goal)
+	friendly(model.MatchStatusLive, goal)
+
+	m, err := repos.Match.CreateMatch(ctx, model.Match{
+		CompetitionID: &comp.ID,
+		HomeTeamID:    teams[0].ID,
+		AwayTeamID:    teams[1].ID,
+		Kickoff:       time.Date(2020, 10, 24, 16, 45, 0, 0, time.UTC),
+		Status:        model.MatchStatusFinished,
+	})
+	require.NoError(t, err)
+	for _, e := range []model.MatchEvent{goal, goal, {Type: model.EventYellowCard, Minute: 30, TeamID: &teams[0].ID, PlayerID: &winger}} {
+		_, err := repos.Match.AddEvent(ctx, m.ID.Hex(), e)
+		require.NoError(t, err)
+	}
+
+	// The assist is stored along with the goal
+	got, err := repos.Match.GetMatch(ctx, m.ID.Hex())
+	require.NoError(t, err)
+	if assert.Len(t, got.Events, 3) {
+		assert.Equal(t, &winger, got.Events[0].AssistID)
+	}
+
+	// Only the goalkeepers and the defenders keep clean sheets, the striker is a forward
+	_, err = repos.Player.PatchPlayer(ctx, winger.Hex(), map[string]interface{}{"position": model.PositionGoalkeeper})
+	require.NoError(t, err)
+
+	res, err := repos.Stats.PlayerStats(ctx, striker.Hex())
+	require.NoError(t, err)
+	require.NotNil(t, res)
+
+	assert.Equal(t, striker, res.PlayerID)
+	assert.Equal(t, model.PlayerTotals{Appearances: 2, Minutes: 180, Goals: 3}, res.Total)
+	if assert.Len(t, res.Competitions, 2) {
+		assert.Equal(t, &comp.ID, res.Competitions[0].CompetitionID)
+		assert.Equal(t, comp.Season, res.Competitions[0].Season)
+		assert.Equal(t, 2, res.Competitions[0].Goals)
+		assert.Nil(t, res.Competitions[1].CompetitionID)
+		assert.Equal(t, 1, res.Competitions[1].Goals)
+	}
+
+	res, err = repos.Stats.PlayerStats(ctx, winger.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, model.PlayerTotals{Appearances: 2, Minutes: 180, Assists: 3, YellowCards: 1, CleanSheets: 2}, res.Total)
+
+	res, err = repos.Stats.PlayerStats(ctx, missingID)
+	require.NoError(t, err)
+	assert.Equal(t, model.PlayerTotals{}, res.Total)
+	assert.Empty(t, res.Competitions)
+
+	scorers, err := repos.Stats.TopScorers(ctx, comp.ID.Hex(), 10)
+	require.NoError(t, err)
+	if assert.Len(t, scorers, 1) {
+		assert.Equal(t, model.Scorer{Position: 1, PlayerID: striker, TeamID: teams[0].ID, PlayerTotals: model.PlayerTotals{Appearances: 1, Minutes: 90, Goals: 2}}, scorers[0])
+	}
+
+	scorers, err = repos.Stats.TopScorers(ctx, missingID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, scorers)
+}
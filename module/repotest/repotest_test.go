@@ -0,0 +1,224 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/competition"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/module/player"
+	"github.com/yezarela/go-soccer/module/stats"
+	"github.com/yezarela/go-soccer/module/team"
+	"github.com/yezarela/go-soccer/pkg/boltdb"
+	"github.com/yezarela/go-soccer/pkg/conn"
+	"github.com/yezarela/go-soccer/pkg/memdb"
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	Run(t, func(t *testing.T) (Repositories, func()) {
+		db := memdb.New()
+
+		repos := Repositories{
+			Team:        team.NewMemoryRepository(db),
+			Player:      player.NewMemoryRepository(db),
+			Match:       match.NewMemoryRepository(db),
+			Competition: competition.NewMemoryRepository(db),
+			Stats:       stats.NewMemoryRepository(db),
+		}
+
+		return repos, func() {}
+	})
+}
+
+func TestBoltRepository(t *testing.T) {
+	Run(t, func(t *testing.T) (Repositories, func()) {
+		dir, err := ioutil.TempDir("", "soccer")
+		require.NoError(t, err)
+
+		db, err := conn.NewBoltDBConnection(filepath.Join(dir, "soccer.db"))
+		require.NoError(t, err)
+
+		repos := Repositories{
+			Team:        team.NewBoltRepository(db),
+			Player:      player.NewBoltRepository(db),
+			Match:       match.NewBoltRepository(db),
+			Competition: competition.NewBoltRepository(db),
+			Stats:       stats.NewBoltRepository(db),
+		}
+
+		return repos, func() {
+			db.Close()
+			os.RemoveAll(dir)
+		}
+	})
+}
+
+func TestBoltPlayerAge(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "soccer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := conn.NewBoltDBConnection(filepath.Join(dir, "soccer.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	players := player.NewBoltRepository(db)
+	teams := team.NewBoltRepository(db)
+
+	p, err := players.CreatePlayer(ctx, model.Player{Name: "Paolo Maldini", DateOfBirth: "1968-06-26"})
+	require.NoError(t, err)
+	_, err = players.UpdatePlayer(ctx, p.ID.Hex(), model.Player{Name: "Paolo Maldini", DateOfBirth: "1968-06-26"})
+	require.NoError(t, err)
+	tm, err := teams.CreateTeam(ctx, model.Team{Name: "Milan", Players: []model.Player{{Name: "Franco Baresi", DateOfBirth: "1960-05-08"}}})
+	require.NoError(t, err)
+
+	// The age is derived when encoding the response, never stored
+	err = db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltdb.PlayersBucket)
+		for _, id := range []primitive.ObjectID{p.ID, tm.Players[0].ID} {
+			assert.NotContains(t, string(b.Get(id[:])), `"age"`)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestMongoRepository(t *testing.T) {
+
+	c, stop := mongoClient(t)
+	defer stop()
+
+	Run(t, func(t *testing.T) (Repositories, func()) {
+		db := c.Database("soccer_test_" + primitive.NewObjectID().Hex())
+
+		repos := Repositories{
+			Team:        team.NewRepository(db),
+			Player:      player.NewRepository(db),
+			Match:       match.NewRepository(db),
+			Competition: competition.NewRepository(db),
+			Stats:       stats.NewRepository(db),
+		}
+
+		return repos, func() {
+			db.Drop(context.Background())
+		}
+	})
+}
+
+// mongoClient connects to the given server, or starts a local one. The test is skipped in short mode
+func mongoClient(t *testing.T) (*mongo.Client, func()) {
+
+	if testing.Short() {
+		t.Skip("Skipping mongodb tests in short mode")
+	}
+
+	// Use the given server, or start a local one
+	uri := os.Getenv("MONGODB_TEST_URI")
+	stop := func() {}
+	if uri == "" {
+		uri, stop = startMongod(t)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, err := conn.NewMongoDBConnection(ctx, uri)
+	if err != nil {
+		stop()
+		t.Fatal(err)
+	}
+
+	return c, func() {
+		c.Disconnect(context.Background())
+		stop()
+	}
+}
+
+// startMongod starts a single node replica set so transactions are supported,
+// the test is skipped when mongod is not installed
+func startMongod(t *testing.T) (string, func()) {
+
+	bin, err := exec.LookPath("mongod")
+	if err != nil {
+		t.Skip("Skipping mongodb conformance, mongod is not installed and MONGODB_TEST_URI is not set")
+	}
+
+	dir, err := ioutil.TempDir("", "mongod")
+	require.NoError(t, err)
+
+	port := freePort(t)
+
+	cmd := exec.Command(bin, "--dbpath", dir, "--port", fmt.Sprint(port), "--bind_ip", "127.0.0.1", "--replSet", "rs0")
+	require.NoError(t, cmd.Start())
+
+	stop := func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+		os.RemoveAll(dir)
+	}
+
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// The node is not primary until the replica set is initiated, connect to it directly
+	c, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://"+addr).SetDirect(true))
+	if err != nil {
+		stop()
+		t.Fatal(err)
+	}
+	defer c.Disconnect(ctx)
+
+	initiate := bson.M{
+		"replSetInitiate": bson.M{"_id": "rs0", "members": bson.A{bson.M{"_id": 0, "host": addr}}},
+	}
+
+	// Retry until mongod accepts connections and the node becomes primary
+	initiated := false
+	for {
+		if !initiated {
+			initiated = c.Database("admin").RunCommand(ctx, initiate).Err() == nil
+		}
+
+		var res bson.M
+		err = c.Database("admin").RunCommand(ctx, bson.M{"isMaster": 1}).Decode(&res)
+		if initiated && err == nil && res["ismaster"] == true {
+			break
+		}
+
+		if ctx.Err() != nil {
+			stop()
+			t.Fatal("mongod did not become primary")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return "mongodb://" + addr + "/?replicaSet=rs0", stop
+}
+
+func freePort(t *testing.T) int {
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port
+}
@@ -0,0 +1,313 @@
+package repotest
+
+import (
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/competition"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/module/player"
+	"github.com/yezarela/go-soccer/module/stats"
+	"github.com/yezarela/go-soccer/module/team"
+	"github.com/yezarela/go-soccer/pkg/conn"
+	"github.com/yezarela/go-soccer/pkg/memdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// season represents the matches of a season created in a storage, the players by team then by shirt
+type season struct {
+	teams   []model.Team
+	players []model.Player
+	comp    model.Competition
+}
+
+// squad are the main positions of the players of a team, the starters then the bench
+var squad = []string{
+	model.PositionGoalkeeper, model.PositionRightBack, model.PositionCentreBack, model.PositionCentreBack, model.PositionLeftBack,
+	model.PositionDefensiveMidfielder, model.PositionCentralMidfielder, model.PositionAttackingMidfielder,
+	model.PositionRightWinger, model.PositionStriker, model.PositionLeftWinger,
+	model.PositionGoalkeeper, model.PositionCentreBack, model.PositionStriker,
+}
+
+// createSeason creates the same season of random matches in any storage: lineups, goals, own goals,
+// cards, substitutions in added time, overturned goals and extra time
+func createSeason(t *testing.T, repos Repositories) season {
+
+	ctx := context.Background()
+	rnd := rand.New(rand.NewSource(7))
+
+	res := season{teams: createTeams(t, repos, "Milan", "Inter")}
+	res.comp = createCompetition(t, repos, "Serie A", res.teams...)
+
+	for _, tm := range res.teams {
+		for i, position := range squad {
+			p, err := repos.Player.CreatePlayer(ctx, model.Player{Name: tm.Name + " " + string(rune('A'+i)), Position: position})
+			require.NoError(t, err)
+			res.players = append(res.players, *p)
+		}
+	}
+
+	for day := 0; day < 12; day++ {
+		home, away := day%2, 1-day%2
+
+		data := model.Match{
+			HomeTeamID: res.teams[home].ID,
+			AwayTeamID: res.teams[away].ID,
+			Kickoff:    time.Date(2020, 9, 20+day, 15, 0, 0, 0, time.UTC),
+			Status:     model.MatchStatusFinished,
+		}
+		// The last matches are friendlies
+		if day < 10 {
+			data.CompetitionID = &res.comp.ID
+		}
+
+		m, err := repos.Match.CreateMatch(ctx, data)
+		require.NoError(t, err)
+		id := m.ID.Hex()
+
+		// The starters play their main position but for a few slots left empty or moved
+		lineups := model.Lineups{}
+		for side, lineup := range []**model.Lineup{&lineups.Home, &lineups.Away} {
+			team := []int{home, away}[side]
+			l := &model.Lineup{TeamID: res.teams[team].ID, Formation: "4-3-3"}
+			for i := 0; i < model.Starters; i++ {
+				p := model.LineupPlayer{PlayerID: res.players[team*len(squad)+i].ID, ShirtNumber: i + 1, Position: squad[i]}
+				switch rnd.Intn(6) {
+				case 0:
+					p.Position = ""
+				case 1:
+					p.Position = squad[rnd.Intn(model.Starters)]
+				}
+				l.Starters = append(l.Starters, p)
+			}
+			*lineup = l
+		}
+		if day%3 != 2 {
+			_, err = repos.Match.SetLineups(ctx, id, lineups)
+			require.NoError(t, err)
+		}
+
+		// The players on the pitch by team, the bench waits
+		pitch := [][]int{{}, {}}
+		bench := [][]int{{}, {}}
+		for team := range pitch {
+			for i := range squad {
+				if i < model.Starters {
+					pitch[team] = append(pitch[team], team*len(squad)+i)
+				} else {
+					bench[team] = append(bench[team], team*len(squad)+i)
+				}
+			}
+		}
+
+		seen := map[primitive.ObjectID]bool{}
+		add := func(e model.MatchEvent) primitive.ObjectID {
+			m, err := repos.Match.AddEvent(ctx, id, e)
+			require.NoError(t, err)
+			for _, added := range m.Events {
+				if !seen[added.ID] {
+					seen[added.ID] = true
+					return added.ID
+				}
+			}
+			return primitive.NilObjectID
+		}
+
+		length := 90
+		if day%4 == 3 {
+			length = 120
+		}
+
+		minute := 0
+		for minute < length {
+			minute += 1 + rnd.Intn(12)
+			if minute > length {
+				break
+			}
+
+			e := model.MatchEvent{Minute: minute}
+			if minute == 45 || minute == 90 {
+				e.AddedTime = rnd.Intn(4)
+			}
+
+			team := rnd.Intn(2)
+			e.TeamID = &res.teams[[]int{home, away}[team]].ID
+			on := pitch[team][rnd.Intn(len(pitch[team]))]
+			e.PlayerID = &res.players[on].ID
+
+			switch r := rnd.Intn(10); {
+			case r < 3:
+				e.Type = model.EventGoal
+				mate := pitch[team][rnd.Intn(len(pitch[team]))]
+				if mate != on {
+					e.AssistID = &res.players[mate].ID
+				}
+			case r < 4:
+				e.Type = model.EventPenalty
+			case r < 5:
+				e.Type = model.EventOwnGoal
+			case r < 6:
+				e.Type = model.EventYellowCard
+			case r < 7 && len(pitch[team]) > 9:
+				e.Type = model.EventRedCard
+				pitch[team] = remove(pitch[team], on)
+			case len(bench[team]) > 0:
+				e.Type = model.EventSubstitution
+				in := bench[team][0]
+				bench[team] = bench[team][1:]
+				e.PlayerInID = &res.players[in].ID
+				pitch[team] = append(remove(pitch[team], on), in)
+			default:
+				e.Type = model.EventYellowCard
+			}
+
+			eventID := add(e)
+
+			// Some goals are overturned, some are scored in the minute of a substitution
+			if e.Type == model.EventGoal && rnd.Intn(5) == 0 {
+				add(model.MatchEvent{Type: model.EventVAR, Minute: minute, EventID: &eventID, Detail: "offside"})
+			}
+			if e.Type == model.EventSubstitution && rnd.Intn(2) == 0 {
+				scorer := pitch[1-team][rnd.Intn(len(pitch[1-team]))]
+				add(model.MatchEvent{Type: model.EventGoal, Minute: minute, AddedTime: e.AddedTime, TeamID: &res.teams[[]int{home, away}[1-team]].ID, PlayerID: &res.players[scorer].ID})
+			}
+		}
+	}
+
+	return res
+}
+
+// remove returns the players without one of them
+func remove(players []int, p int) []int {
+
+	res := []int{}
+	for _, q := range players {
+		if q != p {
+			res = append(res, q)
+		}
+	}
+
+	return res
+}
+
+// index returns the index of an id among ids, -1 if missing
+func index(id primitive.ObjectID, ids []primitive.ObjectID) int {
+	for i, v := range ids {
+		if v == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkStats checks the statistics of a season against those computed in memory on the same season
+func checkStats(t *testing.T, storage season, repo stats.Repository) {
+
+	ctx := context.Background()
+
+	mem := memdb.New()
+	memory := createSeason(t, Repositories{
+		Team:        team.NewMemoryRepository(mem),
+		Player:      player.NewMemoryRepository(mem),
+		Match:       match.NewMemoryRepository(mem),
+		Competition: competition.NewMemoryRepository(mem),
+	})
+	memoryStats := stats.NewMemoryRepository(mem)
+
+	for i := range memory.players {
+		want, err := memoryStats.PlayerStats(ctx, memory.players[i].ID.Hex())
+		require.NoError(t, err)
+
+		got, err := repo.PlayerStats(ctx, storage.players[i].ID.Hex())
+		require.NoError(t, err)
+
+		name := memory.players[i].Name
+		assert.Equal(t, want.Total, got.Total, name)
+
+		if assert.Len(t, got.Competitions, len(want.Competitions), name) {
+			for j, w := range want.Competitions {
+				g := got.Competitions[j]
+				assert.Equal(t, w.CompetitionID == nil, g.CompetitionID == nil, name)
+				assert.Equal(t, w.Season, g.Season, name)
+				assert.Equal(t, w.PlayerTotals, g.PlayerTotals, name)
+			}
+		}
+	}
+
+	want, err := memoryStats.TopScorers(ctx, memory.comp.ID.Hex(), 100)
+	require.NoError(t, err)
+
+	got, err := repo.TopScorers(ctx, storage.comp.ID.Hex(), 100)
+	require.NoError(t, err)
+
+	ids := func(s season) ([]primitive.ObjectID, []primitive.ObjectID) {
+		var players, teams []primitive.ObjectID
+		for _, p := range s.players {
+			players = append(players, p.ID)
+		}
+		for _, tm := range s.teams {
+			teams = append(teams, tm.ID)
+		}
+		return players, teams
+	}
+	memoryPlayers, memoryTeams := ids(memory)
+	players, teams := ids(storage)
+
+	if assert.Len(t, got, len(want)) {
+		for j, w := range want {
+			g := got[j]
+			assert.Equal(t, w.Position, g.Position)
+			assert.Equal(t, index(w.PlayerID, memoryPlayers), index(g.PlayerID, players))
+			assert.Equal(t, index(w.TeamID, memoryTeams), index(g.TeamID, teams))
+			assert.Equal(t, w.PlayerTotals, g.PlayerTotals)
+		}
+	}
+}
+
+// TestMongoStats checks the aggregation pipelines of the mongodb stats repository against the memory one
+func TestMongoStats(t *testing.T) {
+
+	c, stop := mongoClient(t)
+	defer stop()
+
+	db := c.Database("soccer_test_" + primitive.NewObjectID().Hex())
+	defer db.Drop(context.Background())
+
+	repos := Repositories{
+		Team:        team.NewRepository(db),
+		Player:      player.NewRepository(db),
+		Match:       match.NewRepository(db),
+		Competition: competition.NewRepository(db),
+	}
+
+	checkStats(t, createSeason(t, repos), stats.NewRepository(db))
+}
+
+// TestBoltStats checks the bolt stats repository, reading the stored matches and players, against the memory one
+func TestBoltStats(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "soccer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := conn.NewBoltDBConnection(filepath.Join(dir, "soccer.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	repos := Repositories{
+		Team:        team.NewBoltRepository(db),
+		Player:      player.NewBoltRepository(db),
+		Match:       match.NewBoltRepository(db),
+		Competition: competition.NewBoltRepository(db),
+	}
+
+	checkStats(t, createSeason(t, repos), stats.NewBoltRepository(db))
+}
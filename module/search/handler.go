@@ -0,0 +1,66 @@
+package search
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yezarela/go-soccer/module/player"
+	"github.com/yezarela/go-soccer/module/team"
+	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/query"
+)
+
+// Handler represents the httphandler for search
+type Handler struct {
+	teamRepo   team.Repository
+	playerRepo player.Repository
+}
+
+// NewHandler initializes endpoints for search
+func NewHandler(e *echo.Echo, teamRepo team.Repository, playerRepo player.Repository) {
+	handler := &Handler{
+		teamRepo:   teamRepo,
+		playerRepo: playerRepo,
+	}
+
+	e.GET("/search", handler.Search)
+}
+
+// Search returns the players and teams matching a query by relevance
+func (h *Handler) Search(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	q := strings.TrimSpace(c.QueryParam("q"))
+	if len(q) <= 0 {
+		return api.ResponseBadRequest(c, "q cannot be empty")
+	}
+
+	limit, err := query.ParseLimit(c.QueryParam("limit"))
+	if err != nil {
+		return api.ResponseBadRequest(c, err.Error())
+	}
+
+	players, err := h.playerRepo.SearchPlayer(ctx, q, limit)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	teams, err := h.teamRepo.SearchTeam(ctx, q, limit)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	res := append(players, teams...)
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Score > res[j].Score
+	})
+
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return api.ResponseOK(c, res)
+}
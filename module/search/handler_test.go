@@ -0,0 +1,128 @@
+package search
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	playerMock "github.com/yezarela/go-soccer/module/player/mock"
+	teamMock "github.com/yezarela/go-soccer/module/team/mock"
+	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/query"
+)
+
+func TestSearch(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockPlayerRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPlayer := model.Player{Name: "Thomas Müller"}
+		mockTeam := model.Team{Name: "Bayern Munich", Description: "Home of Müller"}
+
+		mockPlayers := []model.SearchResult{{Type: model.SearchTypePlayer, Score: 10, Data: mockPlayer}}
+		mockTeams := []model.SearchResult{{Type: model.SearchTypeTeam, Score: 1.5, Data: mockTeam}}
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: append(mockPlayers, mockTeams...),
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/search?q=muller", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			teamRepo:   mockTeamRepo,
+			playerRepo: mockPlayerRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockPlayerRepo.EXPECT().SearchPlayer(ctx, "muller", query.DefaultLimit).Return(mockPlayers, nil)
+		mockTeamRepo.EXPECT().SearchTeam(ctx, "muller", query.DefaultLimit).Return(mockTeams, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Search(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response OK Ranked By Score", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockPlayerRepo := playerMock.NewMockRepository(ctrl)
+
+		mockPlayers := []model.SearchResult{{Type: model.SearchTypePlayer, Score: 1, Data: model.Player{}}}
+		mockTeams := []model.SearchResult{{Type: model.SearchTypeTeam, Score: 5, Data: model.Team{}}}
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockTeams,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/search?q=milan&limit=1", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			teamRepo:   mockTeamRepo,
+			playerRepo: mockPlayerRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockPlayerRepo.EXPECT().SearchPlayer(ctx, "milan", 1).Return(mockPlayers, nil)
+		mockTeamRepo.EXPECT().SearchTeam(ctx, "milan", 1).Return(mockTeams, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Search(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockPlayerRepo := playerMock.NewMockRepository(ctrl)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/search?q=", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			teamRepo:   mockTeamRepo,
+			playerRepo: mockPlayerRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.Search(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package standings
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// entry represents the standings of a competition and what they were computed from
+type entry struct {
+	generation  uint64
+	competition model.Competition
+	standings   interface{}
+}
+
+// Cache keeps the standings of competitions until one of their matches changes, it implements match.Listener
+type Cache struct {
+	mu          sync.Mutex
+	entries     map[primitive.ObjectID]entry
+	generations map[primitive.ObjectID]uint64
+}
+
+// NewCache creates an empty cache of standings
+func NewCache() *Cache {
+	return &Cache{
+		entries:     map[primitive.ObjectID]entry{},
+		generations: map[primitive.ObjectID]uint64{},
+	}
+}
+
+// Get returns the cached standings of a competition, the generation to pass to Set and whether they are valid
+func (c *Cache) Get(comp model.Competition) (interface{}, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	gen := c.generations[comp.ID]
+
+	// The teams, points or tiebreakers of the competition may have been patched
+	e, ok := c.entries[comp.ID]
+	if !ok || e.generation != gen || !reflect.DeepEqual(e.competition, comp) {
+		return nil, gen, false
+	}
+
+	return e.standings, gen, true
+}
+
+// Set caches the standings of a competition computed at a generation unless a match changed since
+func (c *Cache) Set(comp model.Competition, gen uint64, standings interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generations[comp.ID] != gen {
+		return
+	}
+
+	c.entries[comp.ID] = entry{generation: gen, competition: comp, standings: standings}
+}
+
+// MatchChanged invalidates the standings of the competition of a match
+func (c *Cache) MatchChanged(m model.Match) {
+
+	if m.CompetitionID == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[*m.CompetitionID]++
+	delete(c.entries, *m.CompetitionID)
+}
@@ -0,0 +1,75 @@
+package standings
+
+import (
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/competition"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/groups"
+	"github.com/yezarela/go-soccer/pkg/table"
+)
+
+// Handler represents the httphandler for standings
+type Handler struct {
+	competitionRepo competition.Repository
+	matchRepo       match.Repository
+	cache           *Cache
+}
+
+// NewHandler initializes endpoints for standings
+func NewHandler(e *echo.Echo, competitionRepo competition.Repository, matchRepo match.Repository, cache *Cache) {
+	handler := &Handler{
+		competitionRepo: competitionRepo,
+		matchRepo:       matchRepo,
+		cache:           cache,
+	}
+
+	e.GET("/competitions/:id/standings", handler.Get)
+}
+
+// Get returns the table or the group tables of a competition from its finished matches
+func (h *Handler) Get(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	comp, err := h.competitionRepo.GetCompetition(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if comp == nil {
+		return api.ResponseNotFound(c, "cannot find the requested competition")
+	}
+
+	res, gen, ok := h.cache.Get(*comp)
+	if ok {
+		return api.ResponseOK(c, res)
+	}
+
+	filters := url.Values{
+		"competition_id": {comp.ID.Hex()},
+		"status":         {model.MatchStatusFinished},
+	}
+
+	// The group matches are told from the knockout ones by their order, whatever their status
+	if comp.HasGroups() {
+		filters.Del("status")
+	}
+
+	matches, err := match.ListAll(ctx, h.matchRepo, "", filters)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if comp.HasGroups() {
+		res = groups.Standings(*comp, matches)
+	} else {
+		res = table.Compute(comp.TeamIDs, matches, comp.Points, comp.Tiebreakers)
+	}
+	h.cache.Set(*comp, gen, res)
+
+	return api.ResponseOK(c, res)
+}
@@ -0,0 +1,194 @@
+package standings
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	competitionMock "github.com/yezarela/go-soccer/module/competition/mock"
+	matchMock "github.com/yezarela/go-soccer/module/match/mock"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/table"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGet(t *testing.T) {
+
+	mockCompetition := model.Competition{}
+	mockCompetition.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444d")
+	mockCompetition.TeamIDs = []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+	mockCompetition.Points = table.DefaultPoints
+	mockCompetition.Tiebreakers = table.DefaultTiebreakers
+
+	mockMatch := model.Match{}
+	mockMatch.CompetitionID = &mockCompetition.ID
+	mockMatch.HomeTeamID = mockCompetition.TeamIDs[0]
+	mockMatch.AwayTeamID = mockCompetition.TeamIDs[1]
+	mockMatch.Status = model.MatchStatusFinished
+	mockMatch.Score = model.Score{Home: 0, Away: 2}
+
+	get := func(h *Handler) *httptest.ResponseRecorder {
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/competitions/:id/standings")
+		c.SetParamNames("id")
+		c.SetParamValues(mockCompetition.ID.Hex())
+
+		assert.NoError(t, h.Get(c))
+
+		return rec
+	}
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		h := &Handler{
+			competitionRepo: mockRepo,
+			matchRepo:       mockMatchRepo,
+			cache:           NewCache(),
+		}
+
+		mockRepo.EXPECT().GetCompetition(gomock.Any(), mockCompetition.ID.Hex()).Return(&mockCompetition, nil)
+		mockMatchRepo.EXPECT().ListMatch(gomock.Any(), "", gomock.Any()).Return([]model.Match{mockMatch}, &query.Page{}, nil)
+
+		// Assertions
+		rec := get(h)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		cached, _, ok := h.cache.Get(mockCompetition)
+		res, _ := cached.([]model.Standing)
+		if assert.True(t, ok) && assert.Len(t, res, 2) {
+			assert.Equal(t, mockMatch.AwayTeamID, res[0].TeamID)
+			assert.Equal(t, 3, res[0].Points)
+		}
+	})
+
+	t.Run("Response OK Groups", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		mockGroups := mockCompetition
+		mockGroups.Type = model.CompetitionTypeGroups
+		mockGroups.GroupStage = &model.GroupRules{
+			Groups:  []model.Group{{Name: "A", TeamIDs: mockCompetition.TeamIDs}},
+			Legs:    1,
+			Advance: 1,
+		}
+
+		// Setup
+		h := &Handler{
+			competitionRepo: mockRepo,
+			matchRepo:       mockMatchRepo,
+			cache:           NewCache(),
+		}
+
+		mockRepo.EXPECT().GetCompetition(gomock.Any(), mockCompetition.ID.Hex()).Return(&mockGroups, nil)
+		mockMatchRepo.EXPECT().ListMatch(gomock.Any(), "", gomock.Any()).Return([]model.Match{mockMatch}, &query.Page{}, nil)
+
+		// Assertions
+		rec := get(h)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		cached, _, ok := h.cache.Get(mockGroups)
+		res, _ := cached.(model.GroupStandings)
+		if assert.True(t, ok) && assert.Len(t, res.Groups, 1) {
+			assert.True(t, res.Groups[0].Complete)
+			assert.Equal(t, mockMatch.AwayTeamID, res.Groups[0].Standings[0].TeamID)
+			assert.Equal(t, "A", res.Groups[0].Standings[0].Group)
+			assert.Len(t, res.BestPlaced, 1)
+		}
+	})
+
+	t.Run("Response Cached", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		h := &Handler{
+			competitionRepo: mockRepo,
+			matchRepo:       mockMatchRepo,
+			cache:           NewCache(),
+		}
+
+		// The matches are listed again only after a match of the competition changed
+		mockRepo.EXPECT().GetCompetition(gomock.Any(), mockCompetition.ID.Hex()).Return(&mockCompetition, nil).Times(4)
+		mockMatchRepo.EXPECT().ListMatch(gomock.Any(), "", gomock.Any()).Return([]model.Match{mockMatch}, &query.Page{}, nil).Times(2)
+
+		// Assertions
+		assert.Equal(t, http.StatusOK, get(h).Code)
+		assert.Equal(t, http.StatusOK, get(h).Code)
+
+		h.cache.MatchChanged(model.Match{})
+		assert.Equal(t, http.StatusOK, get(h).Code)
+
+		h.cache.MatchChanged(mockMatch)
+		assert.Equal(t, http.StatusOK, get(h).Code)
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := competitionMock.NewMockRepository(ctrl)
+
+		// Setup
+		h := &Handler{
+			competitionRepo: mockRepo,
+			cache:           NewCache(),
+		}
+
+		mockRepo.EXPECT().GetCompetition(gomock.Any(), mockCompetition.ID.Hex()).Return(nil, nil)
+
+		// Assertions
+		assert.Equal(t, http.StatusNotFound, get(h).Code)
+	})
+}
+
+func TestCache(t *testing.T) {
+
+	comp := model.Competition{ID: primitive.NewObjectID(), Name: "Serie A"}
+	standings := []model.Standing{{Position: 1}}
+
+	cache := NewCache()
+
+	_, gen, ok := cache.Get(comp)
+	assert.False(t, ok)
+
+	// A match changed while the standings were computed
+	cache.MatchChanged(model.Match{CompetitionID: &comp.ID})
+	cache.Set(comp, gen, standings)
+
+	_, gen, ok = cache.Get(comp)
+	assert.False(t, ok)
+
+	cache.Set(comp, gen, standings)
+
+	res, _, ok := cache.Get(comp)
+	assert.True(t, ok)
+	assert.Equal(t, standings, res)
+
+	// The competition was patched
+	comp.TeamIDs = []primitive.ObjectID{primitive.NewObjectID()}
+
+	_, _, ok = cache.Get(comp)
+	assert.False(t, ok)
+}
@@ -0,0 +1,109 @@
+package stats
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/pkg/boltdb"
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type boltRepository struct {
+	db *bolt.DB
+}
+
+// NewBoltRepository creates a new stats repository reading the matches stored in a boltdb file
+func NewBoltRepository(db *bolt.DB) Repository {
+	return &boltRepository{db}
+}
+
+// matches returns the derived finished matches kept by keep, the seasons and the main positions by id
+func (repo *boltRepository) matches(keep func(m model.Match) bool) ([]model.Match, map[primitive.ObjectID]string, map[primitive.ObjectID]string, error) {
+
+	res := []model.Match{}
+	seasons := map[primitive.ObjectID]string{}
+	positions := map[primitive.ObjectID]string{}
+
+	err := repo.db.View(func(tx *bolt.Tx) error {
+		err := tx.Bucket(boltdb.MatchesBucket).ForEach(func(k, v []byte) error {
+			var m model.Match
+			if err := json.Unmarshal(v, &m); err != nil {
+				return err
+			}
+			if m.Status == model.MatchStatusFinished && keep(m) {
+				match.Derive(&m)
+				res = append(res, m)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = tx.Bucket(boltdb.CompetitionsBucket).ForEach(func(k, v []byte) error {
+			var c model.Competition
+			if err := json.Unmarshal(v, &c); err != nil {
+				return err
+			}
+			seasons[c.ID] = c.Season
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(boltdb.PlayersBucket).ForEach(func(k, v []byte) error {
+			var p model.Player
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+			positions[p.ID] = p.Position
+			return nil
+		})
+	})
+
+	return res, seasons, positions, err
+}
+
+// PlayerStats returns the statistics of a player
+func (repo *boltRepository) PlayerStats(ctx context.Context, playerID string) (*model.PlayerStats, error) {
+	op := "stats.Repository.PlayerStats"
+
+	oid, _ := primitive.ObjectIDFromHex(playerID)
+
+	matches, seasons, positions, err := repo.matches(func(m model.Match) bool {
+		return involves(m, oid)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	res := playerStats(oid, matches, seasons, positions)
+
+	return &res, nil
+}
+
+// TopScorers returns the players who scored the most goals in the finished matches of a competition
+func (repo *boltRepository) TopScorers(ctx context.Context, competitionID string, limit int) ([]model.Scorer, error) {
+	op := "stats.Repository.TopScorers"
+
+	oid, _ := primitive.ObjectIDFromHex(competitionID)
+
+	matches, _, positions, err := repo.matches(func(m model.Match) bool {
+		return m.CompetitionID != nil && *m.CompetitionID == oid
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return topScorers(matches, positions, limit), nil
+}
+
+// EnsureIndexes does nothing, matches are scanned from the bucket
+func (repo *boltRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
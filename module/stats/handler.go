@@ -0,0 +1,165 @@
+package stats
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/competition"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/module/player"
+	"github.com/yezarela/go-soccer/module/team"
+	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/table"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// defaultForm is the number of results of the form of a team when last is not given
+const defaultForm = 5
+
+// Handler represents the httphandler for stats
+type Handler struct {
+	statsRepo       Repository
+	playerRepo      player.Repository
+	teamRepo        team.Repository
+	matchRepo       match.Repository
+	competitionRepo competition.Repository
+}
+
+// NewHandler initializes endpoints for stats
+func NewHandler(e *echo.Echo, statsRepo Repository, playerRepo player.Repository, teamRepo team.Repository, matchRepo match.Repository, competitionRepo competition.Repository) {
+	handler := &Handler{
+		statsRepo:       statsRepo,
+		playerRepo:      playerRepo,
+		teamRepo:        teamRepo,
+		matchRepo:       matchRepo,
+		competitionRepo: competitionRepo,
+	}
+
+	e.GET("/players/:id/stats", handler.GetPlayer)
+	e.GET("/teams/:id/stats", handler.GetTeam)
+	e.GET("/teams/:id/head-to-head/:otherId", handler.GetHeadToHead)
+	e.GET("/competitions/:id/top-scorers", handler.GetTopScorers)
+}
+
+// GetPlayer returns the statistics of a player in total and by competition
+func (h *Handler) GetPlayer(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	p, err := h.playerRepo.GetPlayer(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if p == nil {
+		return api.ResponseNotFound(c, "cannot find the requested player")
+	}
+
+	res, err := h.statsRepo.PlayerStats(ctx, p.ID.Hex())
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// GetTopScorers returns the players who scored the most goals in a competition
+func (h *Handler) GetTopScorers(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	limit, err := query.ParseLimit(c.QueryParam("limit"))
+	if err != nil {
+		return api.ResponseBadRequest(c, err.Error())
+	}
+
+	comp, err := h.competitionRepo.GetCompetition(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if comp == nil {
+		return api.ResponseNotFound(c, "cannot find the requested competition")
+	}
+
+	res, err := h.statsRepo.TopScorers(ctx, comp.ID.Hex(), limit)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// GetTeam returns the statistics of a team from its finished matches, of a competition if competition_id is given
+func (h *Handler) GetTeam(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	last := defaultForm
+	if v := c.QueryParam("last"); len(v) > 0 {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > query.MaxLimit {
+			return api.ResponseBadRequest(c, fmt.Sprintf("last must be a number between 1 and %d", query.MaxLimit))
+		}
+		last = n
+	}
+
+	filters := url.Values{"status": {model.MatchStatusFinished}}
+	if v := c.QueryParam("competition_id"); len(v) > 0 {
+		if _, err := primitive.ObjectIDFromHex(v); err != nil {
+			return api.ResponseBadRequest(c, "invalid competition_id")
+		}
+		filters.Set("competition_id", v)
+	}
+
+	t, err := h.teamRepo.GetTeam(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if t == nil {
+		return api.ResponseNotFound(c, "cannot find the requested team")
+	}
+
+	matches, err := match.ListAll(ctx, h.matchRepo, t.ID.Hex(), filters)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	return api.ResponseOK(c, teamStats(t.ID, matches, last))
+}
+
+// GetHeadToHead returns the finished meetings of two teams and their records against each other
+func (h *Handler) GetHeadToHead(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	if c.Param("id") == c.Param("otherId") {
+		return api.ResponseBadRequest(c, "a team cannot meet itself")
+	}
+
+	teams := []*model.Team{}
+	for _, id := range []string{c.Param("id"), c.Param("otherId")} {
+		t, err := h.teamRepo.GetTeam(ctx, id)
+		if err != nil {
+			return api.ResponseError(c, err)
+		}
+		if t == nil {
+			return api.ResponseNotFound(c, "cannot find the requested team")
+		}
+		teams = append(teams, t)
+	}
+
+	filters := url.Values{"status": {model.MatchStatusFinished}}
+
+	matches, err := match.ListAll(ctx, h.matchRepo, teams[0].ID.Hex(), filters)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	return api.ResponseOK(c, table.HeadToHead(teams[0].ID, teams[1].ID, matches))
+}
@@ -0,0 +1,454 @@
+package stats
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	competitionMock "github.com/yezarela/go-soccer/module/competition/mock"
+	matchMock "github.com/yezarela/go-soccer/module/match/mock"
+	playerMock "github.com/yezarela/go-soccer/module/player/mock"
+	statsMock "github.com/yezarela/go-soccer/module/stats/mock"
+	teamMock "github.com/yezarela/go-soccer/module/team/mock"
+	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetPlayer(t *testing.T) {
+
+	mockPlayer := model.Player{}
+	mockPlayer.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+
+	newContext := func() (echo.Context, *httptest.ResponseRecorder) {
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/players/:id/stats")
+		c.SetParamNames("id")
+		c.SetParamValues(mockPlayer.ID.Hex())
+
+		return c, rec
+	}
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := statsMock.NewMockRepository(ctrl)
+		mockPlayerRepo := playerMock.NewMockRepository(ctrl)
+
+		mockStats := model.PlayerStats{
+			PlayerID:     mockPlayer.ID,
+			Total:        model.PlayerTotals{Appearances: 1, Minutes: 90, Goals: 2},
+			Competitions: []model.CompetitionTotals{{Season: "2020-21", PlayerTotals: model.PlayerTotals{Appearances: 1, Minutes: 90, Goals: 2}}},
+		}
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockStats,
+		})
+
+		// Setup
+		c, rec := newContext()
+
+		h := &Handler{
+			statsRepo:  mockRepo,
+			playerRepo: mockPlayerRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockPlayerRepo.EXPECT().GetPlayer(ctx, mockPlayer.ID.Hex()).Return(&mockPlayer, nil)
+		mockRepo.EXPECT().PlayerStats(ctx, mockPlayer.ID.Hex()).Return(&mockStats, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetPlayer(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := statsMock.NewMockRepository(ctrl)
+		mockPlayerRepo := playerMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext()
+
+		h := &Handler{
+			statsRepo:  mockRepo,
+			playerRepo: mockPlayerRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockPlayerRepo.EXPECT().GetPlayer(ctx, mockPlayer.ID.Hex()).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetPlayer(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+}
+
+func TestGetTopScorers(t *testing.T) {
+
+	mockCompetition := model.Competition{}
+	mockCompetition.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444d")
+
+	newContext := func(target string) (echo.Context, *httptest.ResponseRecorder) {
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/competitions/:id/top-scorers")
+		c.SetParamNames("id")
+		c.SetParamValues(mockCompetition.ID.Hex())
+
+		return c, rec
+	}
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := statsMock.NewMockRepository(ctrl)
+		mockCompetitionRepo := competitionMock.NewMockRepository(ctrl)
+
+		mockScorers := []model.Scorer{
+			{Position: 1, PlayerID: primitive.NewObjectID(), TeamID: primitive.NewObjectID(), PlayerTotals: model.PlayerTotals{Goals: 3}},
+		}
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockScorers,
+		})
+
+		// Setup
+		c, rec := newContext("/?limit=5")
+
+		h := &Handler{
+			statsRepo:       mockRepo,
+			competitionRepo: mockCompetitionRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockCompetitionRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(&mockCompetition, nil)
+		mockRepo.EXPECT().TopScorers(ctx, mockCompetition.ID.Hex(), 5).Return(mockScorers, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetTopScorers(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := statsMock.NewMockRepository(ctrl)
+		mockCompetitionRepo := competitionMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext("/")
+
+		h := &Handler{
+			statsRepo:       mockRepo,
+			competitionRepo: mockCompetitionRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockCompetitionRepo.EXPECT().GetCompetition(ctx, mockCompetition.ID.Hex()).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetTopScorers(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := statsMock.NewMockRepository(ctrl)
+		mockCompetitionRepo := competitionMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext("/?limit=1000")
+
+		h := &Handler{
+			statsRepo:       mockRepo,
+			competitionRepo: mockCompetitionRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.GetTopScorers(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+}
+
+func TestGetTeam(t *testing.T) {
+
+	mockTeam := model.Team{}
+	mockTeam.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+
+	newContext := func(target string) (echo.Context, *httptest.ResponseRecorder) {
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id/stats")
+		c.SetParamNames("id")
+		c.SetParamValues(mockTeam.ID.Hex())
+
+		return c, rec
+	}
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		mockMatch := model.Match{}
+		mockMatch.ID = primitive.NewObjectID()
+		mockMatch.HomeTeamID = primitive.NewObjectID()
+		mockMatch.AwayTeamID = mockTeam.ID
+		mockMatch.Status = model.MatchStatusFinished
+		mockMatch.Score = model.Score{Home: 1, Away: 2}
+
+		// Setup
+		c, rec := newContext("/?last=3&competition_id=5f6a5d6129b2289c40b7444c")
+
+		h := &Handler{
+			teamRepo:  mockTeamRepo,
+			matchRepo: mockMatchRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockTeam.ID.Hex()).Return(&mockTeam, nil)
+		mockMatchRepo.EXPECT().ListMatch(ctx, mockTeam.ID.Hex(), gomock.Any()).DoAndReturn(func(_ interface{}, _ string, params query.Params) ([]model.Match, *query.Page, error) {
+			assert.Len(t, params.Filters, 2)
+			return []model.Match{mockMatch}, &query.Page{}, nil
+		})
+
+		// Assertions
+		if assert.NoError(t, h.GetTeam(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var res struct {
+				Data model.TeamStats `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+			assert.Equal(t, "W", res.Data.Form)
+			assert.Equal(t, 1, res.Data.Away.Won)
+			assert.Equal(t, 2.0, res.Data.Total.GoalsForAverage)
+			if assert.NotNil(t, res.Data.BiggestWin) {
+				assert.Equal(t, mockMatch.ID, res.Data.BiggestWin.MatchID)
+			}
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext("/")
+
+		h := &Handler{
+			teamRepo:  mockTeamRepo,
+			matchRepo: mockMatchRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockTeam.ID.Hex()).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetTeam(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		targets := []string{"/?last=0", "/?last=five", "/?last=1000", "/?competition_id=serie-a"}
+
+		for _, target := range targets {
+
+			// Setup
+			c, rec := newContext(target)
+
+			h := &Handler{
+				teamRepo:  mockTeamRepo,
+				matchRepo: mockMatchRepo,
+			}
+
+			// Assertions
+			if assert.NoError(t, h.GetTeam(c)) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+			}
+		}
+	})
+}
+
+func TestGetHeadToHead(t *testing.T) {
+
+	mockTeam := model.Team{}
+	mockTeam.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+	mockOther := model.Team{}
+	mockOther.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444c")
+
+	newContext := func(otherID string) (echo.Context, *httptest.ResponseRecorder) {
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id/head-to-head/:otherId")
+		c.SetParamNames("id", "otherId")
+		c.SetParamValues(mockTeam.ID.Hex(), otherID)
+
+		return c, rec
+	}
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		meeting := model.Match{}
+		meeting.ID = primitive.NewObjectID()
+		meeting.HomeTeamID = mockOther.ID
+		meeting.AwayTeamID = mockTeam.ID
+		meeting.Status = model.MatchStatusFinished
+		meeting.Score = model.Score{Home: 0, Away: 2}
+
+		// The team played other teams too
+		other := model.Match{}
+		other.ID = primitive.NewObjectID()
+		other.HomeTeamID = mockTeam.ID
+		other.AwayTeamID = primitive.NewObjectID()
+		other.Status = model.MatchStatusFinished
+
+		// Setup
+		c, rec := newContext(mockOther.ID.Hex())
+
+		h := &Handler{
+			teamRepo:  mockTeamRepo,
+			matchRepo: mockMatchRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockTeam.ID.Hex()).Return(&mockTeam, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockOther.ID.Hex()).Return(&mockOther, nil)
+		mockMatchRepo.EXPECT().ListMatch(ctx, mockTeam.ID.Hex(), gomock.Any()).Return([]model.Match{meeting, other}, &query.Page{}, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetHeadToHead(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+
+			var res struct {
+				Data model.HeadToHead `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+			assert.Equal(t, 1, res.Data.Played)
+			assert.Equal(t, "W", res.Data.Form)
+			assert.Equal(t, 1, res.Data.Team.Won)
+			assert.Equal(t, 1, res.Data.Other.Lost)
+			if assert.Len(t, res.Data.Meetings, 1) {
+				assert.Equal(t, meeting.ID, res.Data.Meetings[0].MatchID)
+				assert.False(t, res.Data.Meetings[0].Home)
+			}
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext(mockOther.ID.Hex())
+
+		h := &Handler{
+			teamRepo:  mockTeamRepo,
+			matchRepo: mockMatchRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockTeam.ID.Hex()).Return(&mockTeam, nil)
+		mockTeamRepo.EXPECT().GetTeam(ctx, mockOther.ID.Hex()).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetHeadToHead(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockTeamRepo := teamMock.NewMockRepository(ctrl)
+		mockMatchRepo := matchMock.NewMockRepository(ctrl)
+
+		// Setup
+		c, rec := newContext(mockTeam.ID.Hex())
+
+		h := &Handler{
+			teamRepo:  mockTeamRepo,
+			matchRepo: mockMatchRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.GetHeadToHead(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+}
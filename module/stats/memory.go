@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"context"
+
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/match"
+	"github.com/yezarela/go-soccer/pkg/memdb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryRepository struct {
+	db *memdb.DB
+}
+
+// NewMemoryRepository creates a new stats repository reading the matches stored in memory
+func NewMemoryRepository(db *memdb.DB) Repository {
+	return &memoryRepository{db}
+}
+
+// matches returns the derived finished matches kept by keep
+func (repo *memoryRepository) matches(keep func(m model.Match) bool) []model.Match {
+	repo.db.RLock()
+	defer repo.db.RUnlock()
+
+	res := []model.Match{}
+
+	for _, m := range repo.db.Matches {
+		if m.Status == model.MatchStatusFinished && keep(m) {
+			match.Derive(&m)
+			res = append(res, m)
+		}
+	}
+
+	return res
+}
+
+// positions returns the main positions of the players by id
+func (repo *memoryRepository) positions() map[primitive.ObjectID]string {
+	repo.db.RLock()
+	defer repo.db.RUnlock()
+
+	res := map[primitive.ObjectID]string{}
+	for id, p := range repo.db.Players {
+		res[id] = p.Position
+	}
+
+	return res
+}
+
+// PlayerStats returns the statistics of a player
+func (repo *memoryRepository) PlayerStats(ctx context.Context, playerID string) (*model.PlayerStats, error) {
+
+	oid, _ := primitive.ObjectIDFromHex(playerID)
+
+	matches := repo.matches(func(m model.Match) bool {
+		return involves(m, oid)
+	})
+
+	repo.db.RLock()
+	seasons := map[primitive.ObjectID]string{}
+	for id, c := range repo.db.Competitions {
+		seasons[id] = c.Season
+	}
+	repo.db.RUnlock()
+
+	res := playerStats(oid, matches, seasons, repo.positions())
+
+	return &res, nil
+}
+
+// TopScorers returns the players who scored the most goals in the finished matches of a competition
+func (repo *memoryRepository) TopScorers(ctx context.Context, competitionID string, limit int) ([]model.Scorer, error) {
+
+	oid, _ := primitive.ObjectIDFromHex(competitionID)
+
+	matches := repo.matches(func(m model.Match) bool {
+		return m.CompetitionID != nil && *m.CompetitionID == oid
+	})
+
+	return topScorers(matches, repo.positions(), limit), nil
+}
+
+// EnsureIndexes does nothing, matches are scanned in memory
+func (repo *memoryRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
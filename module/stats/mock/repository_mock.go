@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: module/stats/repository.go
+
+// Package mock_stats is a generated GoMock package.
+package mock_stats
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	model "github.com/yezarela/go-soccer/model"
+	reflect "reflect"
+)
+
+// MockRepository is a mock of Repository interface
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// PlayerStats mocks base method
+func (m *MockRepository) PlayerStats(ctx context.Context, playerID string) (*model.PlayerStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlayerStats", ctx, playerID)
+	ret0, _ := ret[0].(*model.PlayerStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlayerStats indicates an expected call of PlayerStats
+func (mr *MockRepositoryMockRecorder) PlayerStats(ctx, playerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlayerStats", reflect.TypeOf((*MockRepository)(nil).PlayerStats), ctx, playerID)
+}
+
+// TopScorers mocks base method
+func (m *MockRepository) TopScorers(ctx context.Context, competitionID string, limit int) ([]model.Scorer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopScorers", ctx, competitionID, limit)
+	ret0, _ := ret[0].([]model.Scorer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TopScorers indicates an expected call of TopScorers
+func (mr *MockRepositoryMockRecorder) TopScorers(ctx, competitionID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopScorers", reflect.TypeOf((*MockRepository)(nil).TopScorers), ctx, competitionID, limit)
+}
+
+// EnsureIndexes mocks base method
+func (m *MockRepository) EnsureIndexes(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureIndexes", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureIndexes indicates an expected call of EnsureIndexes
+func (mr *MockRepositoryMockRecorder) EnsureIndexes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureIndexes", reflect.TypeOf((*MockRepository)(nil).EnsureIndexes), ctx)
+}
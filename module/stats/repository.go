@@ -0,0 +1,348 @@
+package stats
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Repository represents repository of pkg stats, the statistics are aggregated from the finished matches
+type Repository interface {
+	PlayerStats(ctx context.Context, playerID string) (*model.PlayerStats, error)
+	TopScorers(ctx context.Context, competitionID string, limit int) ([]model.Scorer, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+type repository struct {
+	db *mongo.Database
+}
+
+// totals represents the statistics summed by the pipelines, by competition or by player
+type totals struct {
+	ID          *primitive.ObjectID `bson:"_id"`
+	Season      string              `bson:"season"`
+	Appearances int                 `bson:"appearances"`
+	Minutes     int                 `bson:"minutes"`
+	Goals       int                 `bson:"goals"`
+	Penalties   int                 `bson:"penalties"`
+	OwnGoals    int                 `bson:"own_goals"`
+	Assists     int                 `bson:"assists"`
+	YellowCards int                 `bson:"yellow_cards"`
+	RedCards    int                 `bson:"red_cards"`
+	CleanSheets int                 `bson:"clean_sheets"`
+	LastGoal    struct {
+		TeamID primitive.ObjectID `bson:"team_id"`
+	} `bson:"last_goal"`
+}
+
+func (t totals) playerTotals() model.PlayerTotals {
+	return model.PlayerTotals{
+		Appearances: t.Appearances,
+		Minutes:     t.Minutes,
+		Goals:       t.Goals,
+		Penalties:   t.Penalties,
+		OwnGoals:    t.OwnGoals,
+		Assists:     t.Assists,
+		YellowCards: t.YellowCards,
+		RedCards:    t.RedCards,
+		CleanSheets: t.CleanSheets,
+	}
+}
+
+// NewRepository creates a new stats repository
+func NewRepository(db *mongo.Database) Repository {
+	return &repository{db}
+}
+
+// isSet returns the expression of whether a field is neither missing nor null
+func isSet(field string) bson.M {
+	return bson.M{"$ne": bson.A{bson.M{"$ifNull": bson.A{field, nil}}, nil}}
+}
+
+// events returns the expression of the events of a match matching cond, the event is $$e
+func events(cond interface{}) bson.M {
+	return bson.M{"$filter": bson.M{"input": bson.M{"$ifNull": bson.A{"$events", bson.A{}}}, "as": "e", "cond": cond}}
+}
+
+// count returns the expression of the number of events of a match matching cond
+func count(cond interface{}) bson.M {
+	return bson.M{"$size": events(cond)}
+}
+
+// last returns the expression of the last minute of the events of a match matching cond, null without any
+func last(cond interface{}) bson.M {
+	return bson.M{"$max": bson.M{"$map": bson.M{"input": events(cond), "as": "e", "in": "$$e.minute"}}}
+}
+
+// eventAt is the expression of the time of the event $$e like at
+var eventAt = bson.M{"$add": bson.A{bson.M{"$multiply": bson.A{"$$e.minute", addedTimes}}, bson.M{"$ifNull": bson.A{"$$e.added_time", 0}}}}
+
+// lastAt returns the expression of the last time of the events of a match matching cond, null without any
+func lastAt(cond interface{}) bson.M {
+	return bson.M{"$max": bson.M{"$map": bson.M{"input": events(cond), "as": "e", "in": eventAt}}}
+}
+
+// concededBy returns the condition of whether the event $$e is a goal conceded by a team of a match
+func concededBy(teamID string) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"$and": bson.A{bson.M{"$in": bson.A{"$$e.type", bson.A{model.EventGoal, model.EventPenalty}}}, bson.M{"$ne": bson.A{"$$e.team_id", teamID}}}},
+		bson.M{"$and": bson.A{bson.M{"$eq": bson.A{"$$e.type", model.EventOwnGoal}}, bson.M{"$eq": bson.A{"$$e.team_id", teamID}}}},
+	}}
+}
+
+// startersOf returns the expression of the starters of a lineup of a match with their team and slot
+func startersOf(lineup string) bson.M {
+	return bson.M{"$map": bson.M{
+		"input": bson.M{"$ifNull": bson.A{lineup + ".starters", bson.A{}}},
+		"as":    "p",
+		"in":    bson.M{"player_id": "$$p.player_id", "team_id": lineup + ".team_id", "position": "$$p.position"},
+	}}
+}
+
+// appearanceStages returns the stages turning finished matches into one document by match and kept player
+func appearanceStages(keep bson.M) mongo.Pipeline {
+
+	// The events of the player of the document, and the events of a player involving another one
+	own := func(types ...string) bson.M {
+		return bson.M{"$and": bson.A{bson.M{"$eq": bson.A{"$$e.player_id", "$player_id"}}, bson.M{"$in": bson.A{"$$e.type", types}}}}
+	}
+	involving := func(typ, field string) bson.M {
+		return bson.M{"$and": bson.A{bson.M{"$eq": bson.A{"$$e.type", typ}}, isSet("$$e.player_id"), bson.M{"$eq": bson.A{"$$e." + field, "$player_id"}}}}
+	}
+	others := func(typ, field string) bson.M {
+		return bson.M{"$map": bson.M{
+			"input": events(bson.M{"$and": bson.A{bson.M{"$eq": bson.A{"$$e.type", typ}}, isSet("$$e.player_id"), isSet("$$e." + field)}}),
+			"as":    "e",
+			"in":    "$$e." + field,
+		}}
+	}
+
+	return mongo.Pipeline{
+		// A match went to extra time when an event happened after the full time or it was decided by penalties
+		{{Key: "$addFields", Value: bson.M{
+			"overturned": bson.M{"$map": bson.M{"input": events(bson.M{"$eq": bson.A{"$$e.type", model.EventVAR}}), "as": "e", "in": "$$e.event_id"}},
+			"length": bson.M{"$cond": bson.A{
+				bson.M{"$or": bson.A{isSet("$penalties"), bson.M{"$gt": bson.A{bson.M{"$max": "$events.minute"}, fullTime}}}},
+				extraTime,
+				fullTime,
+			}},
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"events":   events(bson.M{"$and": bson.A{bson.M{"$not": bson.A{bson.M{"$in": bson.A{"$$e._id", "$overturned"}}}}, isSet("$$e.team_id")}}),
+			"starters": bson.M{"$concatArrays": bson.A{startersOf("$lineups.home"), startersOf("$lineups.away")}},
+		}}},
+		// A player appears when it starts or an event involves it
+		{{Key: "$addFields", Value: bson.M{
+			"player_id": bson.M{"$setUnion": bson.A{
+				"$starters.player_id",
+				bson.M{"$map": bson.M{"input": events(isSet("$$e.player_id")), "as": "e", "in": "$$e.player_id"}},
+				others(model.EventGoal, "assist_id"),
+				others(model.EventSubstitution, "player_in_id"),
+			}},
+		}}},
+		{{Key: "$unwind", Value: "$player_id"}},
+		{{Key: "$match", Value: keep}},
+		{{Key: "$lookup", Value: bson.M{"from": "players", "localField": "player_id", "foreignField": "_id", "as": "player"}}},
+		// A player plays from the kickoff unless it came on and until the end unless it went off or was sent off
+		{{Key: "$addFields", Value: bson.M{
+			"starter": bson.M{"$arrayElemAt": bson.A{bson.M{"$filter": bson.M{"input": "$starters", "as": "s", "cond": bson.M{"$eq": bson.A{"$$s.player_id", "$player_id"}}}}, 0}},
+			"on":      bson.M{"$ifNull": bson.A{last(involving(model.EventSubstitution, "player_in_id")), 0}},
+			"off":     last(own(model.EventSubstitution, model.EventRedCard)),
+			"on_at":   bson.M{"$ifNull": bson.A{lastAt(involving(model.EventSubstitution, "player_in_id")), 0}},
+			"off_at":  lastAt(own(model.EventSubstitution, model.EventRedCard)),
+			"event_team": bson.M{"$arrayElemAt": bson.A{bson.M{"$map": bson.M{
+				"input": events(bson.M{"$or": bson.A{
+					bson.M{"$eq": bson.A{"$$e.player_id", "$player_id"}},
+					involving(model.EventGoal, "assist_id"),
+					involving(model.EventSubstitution, "player_in_id"),
+				}}),
+				"as": "e",
+				"in": "$$e.team_id",
+			}}, 0}},
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"team_id":  bson.M{"$ifNull": bson.A{"$starter.team_id", "$event_team"}},
+			"position": bson.M{"$ifNull": bson.A{"$starter.position", bson.M{"$arrayElemAt": bson.A{"$player.position", 0}}}},
+			"until": bson.M{"$cond": bson.A{
+				bson.M{"$and": bson.A{isSet("$off"), bson.M{"$lt": bson.A{"$off", "$length"}}}},
+				"$off",
+				"$length",
+			}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":            0,
+			"competition_id": 1,
+			"kickoff":        1,
+			"player_id":      1,
+			"team_id":        1,
+			"appearances":    bson.M{"$literal": 1},
+			"minutes":        bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{"$until", "$on"}}}},
+			"goals":          count(own(model.EventGoal, model.EventPenalty)),
+			"penalties":      count(own(model.EventPenalty)),
+			"own_goals":      count(own(model.EventOwnGoal)),
+			"assists":        count(involving(model.EventGoal, "assist_id")),
+			"yellow_cards":   count(own(model.EventYellowCard)),
+			"red_cards":      count(own(model.EventRedCard)),
+			// The goals conceded while the player was on the pitch
+			"clean_sheets": bson.M{"$cond": bson.A{
+				bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{count(bson.M{"$and": bson.A{
+						concededBy("$team_id"),
+						bson.M{"$gte": bson.A{eventAt, "$on_at"}},
+						bson.M{"$or": bson.A{bson.M{"$not": bson.A{isSet("$off_at")}}, bson.M{"$lt": bson.A{eventAt, "$off_at"}}}},
+					}}), 0}},
+					bson.M{"$in": bson.A{"$position", defensive()}},
+				}},
+				1,
+				0,
+			}},
+		}}},
+	}
+}
+
+// sums returns the accumulators of a group summing the statistics of the appearances
+func sums(id interface{}) bson.M {
+
+	res := bson.M{"_id": id}
+	for _, field := range []string{"appearances", "minutes", "goals", "penalties", "own_goals", "assists", "yellow_cards", "red_cards", "clean_sheets"} {
+		res[field] = bson.M{"$sum": "$" + field}
+	}
+
+	return res
+}
+
+// aggregate decodes the results of a pipeline on the matches
+func (repo *repository) aggregate(ctx context.Context, pipeline mongo.Pipeline) ([]totals, error) {
+
+	cur, err := repo.db.Collection("matches").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var res []totals
+	if err = cur.All(ctx, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// PlayerStats returns the statistics of a player summed by competition
+func (repo *repository) PlayerStats(ctx context.Context, playerID string) (*model.PlayerStats, error) {
+	op := "stats.Repository.PlayerStats"
+
+	oid, _ := primitive.ObjectIDFromHex(playerID)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"status": model.MatchStatusFinished,
+			"$or": bson.A{
+				bson.M{"events.player_id": oid},
+				bson.M{"events.player_in_id": oid},
+				bson.M{"events.assist_id": oid},
+				bson.M{"lineups.home.starters.player_id": oid},
+				bson.M{"lineups.away.starters.player_id": oid},
+			},
+		}}},
+	}
+	pipeline = append(pipeline, appearanceStages(bson.M{"player_id": oid})...)
+
+	// Friendlies are grouped under a null competition, the competitions are ordered from the most recent match
+	group := sums("$competition_id")
+	group["last"] = bson.M{"$max": "$kickoff"}
+
+	pipeline = append(pipeline, mongo.Pipeline{
+		{{Key: "$group", Value: group}},
+		{{Key: "$sort", Value: bson.D{{Key: "last", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$lookup", Value: bson.M{"from": "competitions", "localField": "_id", "foreignField": "_id", "as": "competition"}}},
+		{{Key: "$addFields", Value: bson.M{
+			"season": bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$competition.season", 0}}, ""}},
+		}}},
+	}...)
+
+	items, err := repo.aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	res := &model.PlayerStats{
+		PlayerID:     oid,
+		Competitions: []model.CompetitionTotals{},
+	}
+
+	for _, item := range items {
+		add(&res.Total, item.playerTotals())
+		res.Competitions = append(res.Competitions, model.CompetitionTotals{
+			CompetitionID: item.ID,
+			Season:        item.Season,
+			PlayerTotals:  item.playerTotals(),
+		})
+	}
+
+	return res, nil
+}
+
+// TopScorers returns the players who scored the most goals in the finished matches of a competition
+func (repo *repository) TopScorers(ctx context.Context, competitionID string, limit int) ([]model.Scorer, error) {
+	op := "stats.Repository.TopScorers"
+
+	oid, _ := primitive.ObjectIDFromHex(competitionID)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"competition_id": oid, "status": model.MatchStatusFinished}}},
+	}
+	pipeline = append(pipeline, appearanceStages(bson.M{})...)
+
+	// The team of a scorer is the team of its last match with a goal, matches without goals are null
+	group := sums("$player_id")
+	group["last_goal"] = bson.M{"$max": bson.M{"$cond": bson.A{
+		bson.M{"$gt": bson.A{"$goals", 0}},
+		bson.M{"kickoff": "$kickoff", "team_id": "$team_id"},
+		nil,
+	}}}
+
+	pipeline = append(pipeline, mongo.Pipeline{
+		{{Key: "$group", Value: group}},
+		{{Key: "$match", Value: bson.M{"goals": bson.M{"$gt": 0}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "goals", Value: -1}, {Key: "assists", Value: -1}, {Key: "minutes", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}...)
+
+	items, err := repo.aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	res := []model.Scorer{}
+	for _, item := range items {
+		res = append(res, model.Scorer{PlayerID: *item.ID, TeamID: item.LastGoal.TeamID, PlayerTotals: item.playerTotals()})
+	}
+	rank(res)
+
+	return res, nil
+}
+
+// EnsureIndexes creates the indexes of the matches of a player
+func (repo *repository) EnsureIndexes(ctx context.Context) error {
+	op := "stats.Repository.EnsureIndexes"
+
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "events.player_id", Value: 1}}},
+		{Keys: bson.D{{Key: "events.player_in_id", Value: 1}}},
+		{Keys: bson.D{{Key: "events.assist_id", Value: 1}}},
+		{Keys: bson.D{{Key: "lineups.home.starters.player_id", Value: 1}}},
+		{Keys: bson.D{{Key: "lineups.away.starters.player_id", Value: 1}}},
+	}
+
+	_, err := repo.db.Collection("matches").Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return errors.Wrap(err, op)
+	}
+
+	return nil
+}
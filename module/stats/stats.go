@@ -0,0 +1,340 @@
+package stats
+
+import (
+	"sort"
+
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// fullTime is the length of a match in minutes
+	fullTime = 90
+	// extraTime is the length of a match which went to extra time
+	extraTime = 120
+	// addedTimes is the number of added minutes a minute can have, to order the events by time
+	addedTimes = 1000
+)
+
+// at returns the time of an event, ordering the events by minute then added time
+func at(e model.MatchEvent) int {
+	return e.Minute*addedTimes + e.AddedTime
+}
+
+// appearance represents what a player did in a match, for the team of its events
+type appearance struct {
+	teamID primitive.ObjectID
+	totals model.PlayerTotals
+}
+
+// starters returns the starting lineups of a match, empty until announced
+func starters(m model.Match) []model.Lineup {
+
+	if m.Lineups == nil {
+		return nil
+	}
+
+	var res []model.Lineup
+	for _, l := range []*model.Lineup{m.Lineups.Home, m.Lineups.Away} {
+		if l != nil {
+			res = append(res, *l)
+		}
+	}
+
+	return res
+}
+
+// involves returns whether a player starts a match or is involved in one of its events
+func involves(m model.Match, playerID primitive.ObjectID) bool {
+	for _, l := range starters(m) {
+		for _, p := range l.Starters {
+			if p.PlayerID == playerID {
+				return true
+			}
+		}
+	}
+	for _, e := range m.Events {
+		for _, id := range []*primitive.ObjectID{e.PlayerID, e.PlayerInID, e.AssistID} {
+			if id != nil && *id == playerID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// length returns the minutes played in a match, 120 when it went to extra time
+func length(m model.Match) int {
+
+	if m.Penalties != nil {
+		return extraTime
+	}
+	for _, e := range m.Events {
+		if e.Minute > fullTime {
+			return extraTime
+		}
+	}
+
+	return fullTime
+}
+
+// keepsCleanSheets returns whether a position is credited with clean sheets, goalkeepers and defenders only
+func keepsCleanSheets(code string) bool {
+	line := model.PositionLines[code]
+	return line == model.LineGoalkeeper || line == model.LineDefence
+}
+
+// defensive returns the codes of the positions credited with clean sheets
+func defensive() []string {
+
+	res := []string{}
+	for _, code := range model.Positions {
+		if keepsCleanSheets(code) {
+			res = append(res, code)
+		}
+	}
+
+	return res
+}
+
+// appearances returns the appearances of the players of a derived match by player id
+func appearances(m model.Match, positions map[primitive.ObjectID]string) map[primitive.ObjectID]*appearance {
+
+	res := map[primitive.ObjectID]*appearance{}
+	on := map[primitive.ObjectID]int{}
+	off := map[primitive.ObjectID]int{}
+	onAt := map[primitive.ObjectID]int{}
+	offAt := map[primitive.ObjectID]int{}
+	slots := map[primitive.ObjectID]string{}
+
+	// The times of the goals conceded by each team
+	conceded := map[primitive.ObjectID][]int{}
+
+	appear := func(id, teamID primitive.ObjectID) *appearance {
+		a, ok := res[id]
+		if !ok {
+			a = &appearance{teamID: teamID}
+			a.totals.Appearances = 1
+			res[id] = a
+		}
+		return a
+	}
+
+	for _, l := range starters(m) {
+		for _, p := range l.Starters {
+			appear(p.PlayerID, l.TeamID)
+			slots[p.PlayerID] = p.Position
+		}
+	}
+
+	overturned := map[primitive.ObjectID]bool{}
+	for _, e := range m.Events {
+		if e.Type == model.EventVAR && e.EventID != nil {
+			overturned[*e.EventID] = true
+		}
+	}
+
+	for _, e := range m.Events {
+		if overturned[e.ID] || e.TeamID == nil {
+			continue
+		}
+
+		other := m.HomeTeamID
+		if *e.TeamID == m.HomeTeamID {
+			other = m.AwayTeamID
+		}
+
+		switch e.Type {
+		case model.EventGoal, model.EventPenalty:
+			conceded[other] = append(conceded[other], at(e))
+		case model.EventOwnGoal:
+			conceded[*e.TeamID] = append(conceded[*e.TeamID], at(e))
+		}
+
+		if e.PlayerID == nil {
+			continue
+		}
+
+		a := appear(*e.PlayerID, *e.TeamID)
+
+		switch e.Type {
+		case model.EventGoal:
+			a.totals.Goals++
+			if e.AssistID != nil {
+				appear(*e.AssistID, *e.TeamID).totals.Assists++
+			}
+		case model.EventPenalty:
+			a.totals.Goals++
+			a.totals.Penalties++
+		case model.EventOwnGoal:
+			a.totals.OwnGoals++
+		case model.EventYellowCard:
+			a.totals.YellowCards++
+		case model.EventRedCard:
+			a.totals.RedCards++
+			off[*e.PlayerID] = e.Minute
+			offAt[*e.PlayerID] = at(e)
+		case model.EventSubstitution:
+			off[*e.PlayerID] = e.Minute
+			offAt[*e.PlayerID] = at(e)
+			if e.PlayerInID != nil {
+				appear(*e.PlayerInID, *e.TeamID)
+				on[*e.PlayerInID] = e.Minute
+				onAt[*e.PlayerInID] = at(e)
+			}
+		}
+	}
+
+	end := length(m)
+
+	for id, a := range res {
+		until := end
+		if minute, ok := off[id]; ok && minute < until {
+			until = minute
+		}
+		if minutes := until - on[id]; minutes > 0 {
+			a.totals.Minutes = minutes
+		}
+
+		// Only the goals conceded while the player was on the pitch count against its clean sheet
+		goals := 0
+		for _, t := range conceded[a.teamID] {
+			if _, ok := offAt[id]; t >= onAt[id] && (!ok || t < offAt[id]) {
+				goals++
+			}
+		}
+		// The position of its slot in the starting lineup, otherwise its main position
+		slot := slots[id]
+		if len(slot) <= 0 {
+			slot = positions[id]
+		}
+		if goals == 0 && keepsCleanSheets(slot) {
+			a.totals.CleanSheets = 1
+		}
+	}
+
+	return res
+}
+
+// add adds the statistics of src to dst
+func add(dst *model.PlayerTotals, src model.PlayerTotals) {
+	dst.Appearances += src.Appearances
+	dst.Minutes += src.Minutes
+	dst.Goals += src.Goals
+	dst.Penalties += src.Penalties
+	dst.OwnGoals += src.OwnGoals
+	dst.Assists += src.Assists
+	dst.YellowCards += src.YellowCards
+	dst.RedCards += src.RedCards
+	dst.CleanSheets += src.CleanSheets
+}
+
+// playerStats sums the statistics of a player over derived finished matches by competition, most recent first
+func playerStats(playerID primitive.ObjectID, matches []model.Match, seasons, positions map[primitive.ObjectID]string) model.PlayerStats {
+
+	res := model.PlayerStats{
+		PlayerID:     playerID,
+		Competitions: []model.CompetitionTotals{},
+	}
+
+	sorted := append([]model.Match{}, matches...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Kickoff.After(sorted[j].Kickoff)
+	})
+
+	// Friendlies are grouped under the nil id
+	index := map[primitive.ObjectID]int{}
+
+	for _, m := range sorted {
+		a, ok := appearances(m, positions)[playerID]
+		if !ok {
+			continue
+		}
+
+		add(&res.Total, a.totals)
+
+		key := primitive.NilObjectID
+		if m.CompetitionID != nil {
+			key = *m.CompetitionID
+		}
+
+		i, ok := index[key]
+		if !ok {
+			i = len(res.Competitions)
+			index[key] = i
+			res.Competitions = append(res.Competitions, model.CompetitionTotals{
+				CompetitionID: m.CompetitionID,
+				Season:        seasons[key],
+			})
+		}
+		add(&res.Competitions[i].PlayerTotals, a.totals)
+	}
+
+	return res
+}
+
+// topScorers ranks the players who scored in derived finished matches by goals, assists then fewer minutes
+func topScorers(matches []model.Match, positions map[primitive.ObjectID]string, limit int) []model.Scorer {
+
+	sorted := append([]model.Match{}, matches...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Kickoff.Before(sorted[j].Kickoff)
+	})
+
+	scorers := map[primitive.ObjectID]*model.Scorer{}
+
+	for _, m := range sorted {
+		for id, a := range appearances(m, positions) {
+			s, ok := scorers[id]
+			if !ok {
+				s = &model.Scorer{PlayerID: id}
+				scorers[id] = s
+			}
+			add(&s.PlayerTotals, a.totals)
+			if a.totals.Goals > 0 {
+				s.TeamID = a.teamID
+			}
+		}
+	}
+
+	res := []model.Scorer{}
+	for _, s := range scorers {
+		if s.Goals > 0 {
+			res = append(res, *s)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if a.Goals != b.Goals {
+			return a.Goals > b.Goals
+		}
+		if a.Assists != b.Assists {
+			return a.Assists > b.Assists
+		}
+		if a.Minutes != b.Minutes {
+			return a.Minutes < b.Minutes
+		}
+		return a.PlayerID.Hex() < b.PlayerID.Hex()
+	})
+
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	rank(res)
+
+	return res
+}
+
+// rank sets the positions of ranked scorers, players with the same goals, assists and minutes share their position
+func rank(scorers []model.Scorer) {
+	for i := range scorers {
+		scorers[i].Position = i + 1
+		if i > 0 {
+			a, b := scorers[i-1], scorers[i]
+			if a.Goals == b.Goals && a.Assists == b.Assists && a.Minutes == b.Minutes {
+				scorers[i].Position = a.Position
+			}
+		}
+	}
+}
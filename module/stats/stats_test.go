@@ -0,0 +1,230 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/match"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAppearances(t *testing.T) {
+
+	home := primitive.NewObjectID()
+	away := primitive.NewObjectID()
+	striker := primitive.NewObjectID()
+	winger := primitive.NewObjectID()
+	sub := primitive.NewObjectID()
+	defender := primitive.NewObjectID()
+
+	event := func(typ string, minute int, teamID, playerID primitive.ObjectID) model.MatchEvent {
+		return model.MatchEvent{ID: primitive.NewObjectID(), Type: typ, Minute: minute, TeamID: &teamID, PlayerID: &playerID}
+	}
+
+	goal := event(model.EventGoal, 10, home, striker)
+	goal.AssistID = &winger
+	overturned := event(model.EventGoal, 50, home, striker)
+	overturned.AssistID = &winger
+	change := event(model.EventSubstitution, 60, home, winger)
+	change.PlayerInID = &sub
+
+	m := model.Match{HomeTeamID: home, AwayTeamID: away, Events: []model.MatchEvent{
+		goal,
+		event(model.EventYellowCard, 30, away, defender),
+		overturned,
+		{ID: primitive.NewObjectID(), Type: model.EventVAR, Minute: 51, EventID: &overturned.ID, Detail: "offside"},
+		change,
+		event(model.EventPenalty, 80, home, sub),
+		event(model.EventRedCard, 85, away, defender),
+	}}
+	match.Derive(&m)
+
+	// Only the goalkeepers and the defenders keep clean sheets, the forwards do not
+	positions := map[primitive.ObjectID]string{
+		striker:  model.PositionStriker,
+		winger:   model.PositionLeftWinger,
+		sub:      model.PositionCentreBack,
+		defender: model.PositionCentreBack,
+	}
+
+	res := appearances(m, positions)
+
+	if assert.Len(t, res, 4) {
+		assert.Equal(t, model.PlayerTotals{Appearances: 1, Minutes: 90, Goals: 1}, res[striker].totals)
+		assert.Equal(t, model.PlayerTotals{Appearances: 1, Minutes: 60, Assists: 1}, res[winger].totals)
+		assert.Equal(t, model.PlayerTotals{Appearances: 1, Minutes: 30, Goals: 1, Penalties: 1, CleanSheets: 1}, res[sub].totals)
+		assert.Equal(t, model.PlayerTotals{Appearances: 1, Minutes: 85, YellowCards: 1, RedCards: 1}, res[defender].totals)
+		assert.Equal(t, away, res[defender].teamID)
+	}
+
+	// The slot of a starter comes before its main position
+	m.Lineups = &model.Lineups{Home: &model.Lineup{TeamID: home, Starters: []model.LineupPlayer{{PlayerID: striker, Position: model.PositionStriker}, {PlayerID: winger, Position: model.PositionRightBack}}}}
+
+	assert.Equal(t, 0, appearances(m, positions)[striker].totals.CleanSheets)
+	assert.Equal(t, 1, appearances(m, positions)[winger].totals.CleanSheets)
+	assert.Equal(t, 0, appearances(m, nil)[sub].totals.CleanSheets)
+
+	m.Lineups = nil
+
+	// Extra time is played after the full time
+	m.Events = append(m.Events, event(model.EventGoal, 105, away, defender))
+	match.Derive(&m)
+
+	assert.Equal(t, 120, appearances(m, positions)[striker].totals.Minutes)
+	assert.Equal(t, 0, appearances(m, positions)[sub].totals.CleanSheets)
+
+	// The starters appear even without events
+	keeper := primitive.NewObjectID()
+	m.Lineups = &model.Lineups{Away: &model.Lineup{TeamID: away, Starters: []model.LineupPlayer{{PlayerID: keeper}, {PlayerID: defender}}}}
+
+	res = appearances(m, positions)
+
+	assert.Len(t, res, 5)
+	assert.Equal(t, model.PlayerTotals{Appearances: 1, Minutes: 120}, res[keeper].totals)
+	assert.Equal(t, 1, res[defender].totals.Appearances)
+	assert.True(t, involves(m, keeper))
+	assert.False(t, involves(m, primitive.NewObjectID()))
+}
+
+func TestCleanSheets(t *testing.T) {
+
+	home := primitive.NewObjectID()
+	away := primitive.NewObjectID()
+	starter := primitive.NewObjectID()
+	sub := primitive.NewObjectID()
+	late := primitive.NewObjectID()
+	scorer := primitive.NewObjectID()
+
+	change := func(minute, added int, off, on primitive.ObjectID) model.MatchEvent {
+		return model.MatchEvent{ID: primitive.NewObjectID(), Type: model.EventSubstitution, Minute: minute, AddedTime: added, TeamID: &home, PlayerID: &off, PlayerInID: &on}
+	}
+	goal := func(minute, added int) model.MatchEvent {
+		return model.MatchEvent{ID: primitive.NewObjectID(), Type: model.EventGoal, Minute: minute, AddedTime: added, TeamID: &away, PlayerID: &scorer}
+	}
+
+	// The starter goes off at 0-0, the sub concedes right after coming on and is replaced in added time
+	m := model.Match{HomeTeamID: home, AwayTeamID: away, Events: []model.MatchEvent{
+		change(60, 0, starter, sub),
+		goal(60, 0),
+		change(90, 2, sub, late),
+	}}
+	match.Derive(&m)
+
+	positions := map[primitive.ObjectID]string{
+		starter: model.PositionCentreBack,
+		sub:     model.PositionCentreBack,
+		late:    model.PositionCentreBack,
+	}
+
+	res := appearances(m, positions)
+
+	assert.Equal(t, 1, res[starter].totals.CleanSheets)
+	assert.Equal(t, 0, res[sub].totals.CleanSheets)
+	assert.Equal(t, 1, res[late].totals.CleanSheets)
+
+	// A goal in added time before the change is conceded by the player going off
+	m.Events = append(m.Events, goal(90, 1))
+	match.Derive(&m)
+
+	res = appearances(m, positions)
+
+	assert.Equal(t, 0, res[sub].totals.CleanSheets)
+	assert.Equal(t, 1, res[late].totals.CleanSheets)
+
+	// A goal after the change is conceded by the player coming on
+	m.Events = append(m.Events, goal(90, 3))
+	match.Derive(&m)
+
+	assert.Equal(t, 0, appearances(m, positions)[late].totals.CleanSheets)
+}
+
+func TestPlayerStats(t *testing.T) {
+
+	home := primitive.NewObjectID()
+	away := primitive.NewObjectID()
+	league := primitive.NewObjectID()
+	cup := primitive.NewObjectID()
+	striker := primitive.NewObjectID()
+
+	game := func(competitionID *primitive.ObjectID, day int, minutes ...int) model.Match {
+		m := model.Match{
+			CompetitionID: competitionID,
+			HomeTeamID:    home,
+			AwayTeamID:    away,
+			Kickoff:       time.Date(2020, 10, day, 15, 0, 0, 0, time.UTC),
+			Status:        model.MatchStatusFinished,
+		}
+		for _, minute := range minutes {
+			m.Events = append(m.Events, model.MatchEvent{ID: primitive.NewObjectID(), Type: model.EventGoal, Minute: minute, TeamID: &home, PlayerID: &striker})
+		}
+		match.Derive(&m)
+		return m
+	}
+
+	matches := []model.Match{
+		game(&league, 1, 10),
+		game(&cup, 2, 20, 30),
+		game(nil, 3, 40),
+		game(&league, 4, 50, 60, 70),
+		game(&league, 5),
+	}
+
+	res := playerStats(striker, matches, map[primitive.ObjectID]string{league: "2020-21", cup: "2020"}, map[primitive.ObjectID]string{striker: model.PositionStriker})
+
+	assert.Equal(t, striker, res.PlayerID)
+	assert.Equal(t, model.PlayerTotals{Appearances: 4, Minutes: 360, Goals: 7}, res.Total)
+
+	// The competitions are ordered from the most recent match, friendlies have no competition
+	if assert.Len(t, res.Competitions, 3) {
+		assert.Equal(t, &league, res.Competitions[0].CompetitionID)
+		assert.Equal(t, "2020-21", res.Competitions[0].Season)
+		assert.Equal(t, 2, res.Competitions[0].Appearances)
+		assert.Equal(t, 4, res.Competitions[0].Goals)
+		assert.Nil(t, res.Competitions[1].CompetitionID)
+		assert.Equal(t, "", res.Competitions[1].Season)
+		assert.Equal(t, &cup, res.Competitions[2].CompetitionID)
+		assert.Equal(t, 2, res.Competitions[2].Goals)
+	}
+}
+
+func TestTopScorers(t *testing.T) {
+
+	home := primitive.NewObjectID()
+	away := primitive.NewObjectID()
+	players := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+
+	var events []model.MatchEvent
+	score := func(player, teamID primitive.ObjectID, assist *primitive.ObjectID) {
+		events = append(events, model.MatchEvent{ID: primitive.NewObjectID(), Type: model.EventGoal, Minute: len(events) + 1, TeamID: &teamID, PlayerID: &player, AssistID: assist})
+	}
+
+	score(players[0], home, nil)
+	score(players[0], home, &players[1])
+	score(players[1], home, nil)
+	score(players[2], away, nil)
+	score(players[3], away, &players[2])
+
+	m := model.Match{HomeTeamID: home, AwayTeamID: away, Status: model.MatchStatusFinished, Events: events}
+	match.Derive(&m)
+
+	res := topScorers([]model.Match{m}, nil, 10)
+
+	// Players with the same goals, assists and minutes share their position
+	if assert.Len(t, res, 4) {
+		assert.Equal(t, players[0], res[0].PlayerID)
+		assert.Equal(t, 1, res[0].Position)
+		assert.Equal(t, 2, res[0].Goals)
+		assert.Equal(t, home, res[0].TeamID)
+
+		assert.Equal(t, 2, res[1].Position)
+		assert.Equal(t, 2, res[2].Position)
+		assert.ElementsMatch(t, []primitive.ObjectID{players[1], players[2]}, []primitive.ObjectID{res[1].PlayerID, res[2].PlayerID})
+
+		assert.Equal(t, players[3], res[3].PlayerID)
+		assert.Equal(t, 4, res[3].Position)
+	}
+
+	assert.Len(t, topScorers([]model.Match{m}, nil, 2), 2)
+}
@@ -0,0 +1,102 @@
+package stats
+
+import (
+	"math"
+	"sort"
+
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/table"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// record adds a result to a record, its averages are set once all the results are added
+func record(r *model.TeamRecord, res model.TeamResult) {
+
+	r.Played++
+	r.GoalsFor += res.GoalsFor
+	r.GoalsAgainst += res.GoalsAgainst
+
+	switch res.Result {
+	case model.ResultWin:
+		r.Won++
+	case model.ResultDraw:
+		r.Drawn++
+	case model.ResultLoss:
+		r.Lost++
+	}
+
+	if res.GoalsAgainst == 0 {
+		r.CleanSheets++
+	}
+}
+
+// average sets the averages of a record, rounded to two decimals
+func average(r *model.TeamRecord) {
+
+	if r.Played <= 0 {
+		return
+	}
+
+	r.GoalsForAverage = math.Round(float64(r.GoalsFor)/float64(r.Played)*100) / 100
+	r.GoalsAgainstAverage = math.Round(float64(r.GoalsAgainst)/float64(r.Played)*100) / 100
+}
+
+// teamStats returns the statistics of a team from its derived finished matches
+func teamStats(teamID primitive.ObjectID, matches []model.Match, last int) model.TeamStats {
+
+	res := model.TeamStats{
+		TeamID: teamID,
+		Last:   []model.TeamResult{},
+	}
+
+	sorted := append([]model.Match{}, matches...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Kickoff.Before(sorted[j].Kickoff)
+	})
+
+	results := []model.TeamResult{}
+
+	for _, m := range sorted {
+		if m.Status != model.MatchStatusFinished || (m.HomeTeamID != teamID && m.AwayTeamID != teamID) {
+			continue
+		}
+
+		r := table.Result(m, teamID)
+		results = append(results, r)
+
+		record(&res.Total, r)
+		if r.Home {
+			record(&res.Home, r)
+		} else {
+			record(&res.Away, r)
+		}
+
+		switch r.Result {
+		case model.ResultWin:
+			if table.Bigger(r, res.BiggestWin) {
+				win := r
+				res.BiggestWin = &win
+			}
+		case model.ResultLoss:
+			if table.Bigger(r, res.BiggestLoss) {
+				loss := r
+				res.BiggestLoss = &loss
+			}
+		}
+	}
+
+	average(&res.Total)
+	average(&res.Home)
+	average(&res.Away)
+
+	if len(results) > last {
+		results = results[len(results)-last:]
+	}
+
+	res.Last = results
+	for _, r := range results {
+		res.Form += r.Result
+	}
+
+	return res
+}
@@ -0,0 +1,72 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTeamStats(t *testing.T) {
+
+	team := primitive.NewObjectID()
+	rival := primitive.NewObjectID()
+
+	day := 0
+	game := func(home, away primitive.ObjectID, homeGoals, awayGoals int) model.Match {
+		day++
+		return model.Match{
+			ID:         primitive.NewObjectID(),
+			HomeTeamID: home,
+			AwayTeamID: away,
+			Kickoff:    time.Date(2020, 10, day, 15, 0, 0, 0, time.UTC),
+			Status:     model.MatchStatusFinished,
+			Score:      model.Score{Home: homeGoals, Away: awayGoals},
+		}
+	}
+
+	matches := []model.Match{
+		game(team, rival, 3, 0),
+		game(rival, team, 2, 1),
+		game(team, rival, 1, 1),
+		game(rival, team, 0, 3),
+		game(team, rival, 0, 4),
+		game(team, rival, 2, 0),
+	}
+
+	// Scheduled matches and the matches of other teams are left out
+	scheduled := game(team, rival, 0, 0)
+	scheduled.Status = model.MatchStatusScheduled
+	other := game(rival, primitive.NewObjectID(), 9, 0)
+
+	// The matches are read by kickoff whatever their order
+	res := teamStats(team, append([]model.Match{matches[5], scheduled, other}, matches[:5]...), 4)
+
+	assert.Equal(t, team, res.TeamID)
+	assert.Equal(t, "DWLW", res.Form)
+	if assert.Len(t, res.Last, 4) {
+		assert.Equal(t, matches[2].ID, res.Last[0].MatchID)
+		assert.Equal(t, rival, res.Last[1].OpponentID)
+		assert.False(t, res.Last[1].Home)
+		assert.Equal(t, 3, res.Last[1].GoalsFor)
+	}
+
+	assert.Equal(t, model.TeamRecord{Played: 6, Won: 3, Drawn: 1, Lost: 2, GoalsFor: 10, GoalsAgainst: 7, GoalsForAverage: 1.67, GoalsAgainstAverage: 1.17, CleanSheets: 3}, res.Total)
+	assert.Equal(t, model.TeamRecord{Played: 4, Won: 2, Drawn: 1, Lost: 1, GoalsFor: 6, GoalsAgainst: 5, GoalsForAverage: 1.5, GoalsAgainstAverage: 1.25, CleanSheets: 2}, res.Home)
+	assert.Equal(t, 2, res.Away.Played)
+
+	// The biggest wins tie on the difference and the goals, the most recent is kept
+	if assert.NotNil(t, res.BiggestWin) {
+		assert.Equal(t, matches[3].ID, res.BiggestWin.MatchID)
+	}
+	if assert.NotNil(t, res.BiggestLoss) {
+		assert.Equal(t, matches[4].ID, res.BiggestLoss.MatchID)
+	}
+
+	empty := teamStats(team, nil, 5)
+	assert.Equal(t, "", empty.Form)
+	assert.Empty(t, empty.Last)
+	assert.Nil(t, empty.BiggestWin)
+}
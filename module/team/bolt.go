@@ -0,0 +1,467 @@
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/boltdb"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/storage"
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type boltRepository struct {
+	db *bolt.DB
+}
+
+// NewBoltRepository creates a new team repository stored in a boltdb file
+func NewBoltRepository(db *bolt.DB) Repository {
+	return &boltRepository{db}
+}
+
+// all returns every stored team
+func (repo *boltRepository) all(tx *bolt.Tx) ([]boltdb.Team, error) {
+
+	var items []boltdb.Team
+
+	err := tx.Bucket(boltdb.TeamsBucket).ForEach(func(k, v []byte) error {
+		var t boltdb.Team
+		if err := json.Unmarshal(v, &t); err != nil {
+			return err
+		}
+		items = append(items, t)
+		return nil
+	})
+
+	return items, err
+}
+
+// toModel joins a stored team with its existing players like the $lookup of the mongodb repository
+func (repo *boltRepository) toModel(tx *bolt.Tx, t boltdb.Team) (model.Team, error) {
+
+	data := model.Team{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Location:    t.Location,
+		Players:     []model.Player{},
+		CreatedAt:   t.CreatedAt,
+	}
+
+	players := tx.Bucket(boltdb.PlayersBucket)
+
+	for _, pid := range t.Players {
+		var p model.Player
+		found, err := boltdb.Get(players, pid, &p)
+		if err != nil {
+			return data, err
+		}
+		if found {
+			data.Players = append(data.Players, p)
+		}
+	}
+
+	return data, nil
+}
+
+// getTeam returns a joined team by id, nil if it does not exist
+func (repo *boltRepository) getTeam(tx *bolt.Tx, oid primitive.ObjectID) (*model.Team, error) {
+
+	var t boltdb.Team
+
+	found, err := boltdb.Get(tx.Bucket(boltdb.TeamsBucket), oid, &t)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	data, err := repo.toModel(tx, t)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// ListTeam returns a filtered and sorted page of teams
+func (repo *boltRepository) ListTeam(ctx context.Context, params query.Params) ([]model.Team, *query.Page, error) {
+	op := "team.Repository.ListTeam"
+
+	var items []model.Team
+	var total int
+
+	err := repo.db.View(func(tx *bolt.Tx) error {
+		all, err := repo.all(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, t := range all {
+			data, err := repo.toModel(tx, t)
+			if err != nil {
+				return err
+			}
+			if params.Match(cursorValues(data)) {
+				items = append(items, data)
+			}
+		}
+
+		total = len(all)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return params.Less(cursorValues(items[i]), cursorValues(items[j]))
+	})
+
+	page, n, err := params.NewPage(len(items), func(i int) map[string]interface{} {
+		return cursorValues(items[i])
+	}, query.Total(total))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
+	}
+
+	return items[:n], page, nil
+}
+
+// GetTeam returns a team by id
+func (repo *boltRepository) GetTeam(ctx context.Context, id string) (*model.Team, error) {
+	op := "team.Repository.GetTeam"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	var data *model.Team
+
+	err := repo.db.View(func(tx *bolt.Tx) error {
+		var err error
+		data, err = repo.getTeam(tx, oid)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return data, nil
+}
+
+// CreateTeam creates a new team and its players in a single transaction
+func (repo *boltRepository) CreateTeam(ctx context.Context, data model.Team) (*model.Team, error) {
+	op := "team.Repository.CreateTeam"
+
+	now := storage.Now()
+
+	t := boltdb.Team{
+		ID:          primitive.NewObjectID(),
+		Name:        data.Name,
+		Description: data.Description,
+		Location:    data.Location,
+		Players:     []primitive.ObjectID{},
+		CreatedAt:   now,
+	}
+
+	var res *model.Team
+
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		players := tx.Bucket(boltdb.PlayersBucket)
+
+		for _, p := range data.Players {
+			p.ID = primitive.NewObjectID()
+			p.CreatedAt = now
+			if err := boltdb.Put(players, p.ID, boltdb.Player(p)); err != nil {
+				return err
+			}
+			t.Players = append(t.Players, p.ID)
+		}
+
+		if err := boltdb.Put(tx.Bucket(boltdb.TeamsBucket), t.ID, t); err != nil {
+			return err
+		}
+
+		var err error
+		res, err = repo.getTeam(tx, t.ID)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return res, nil
+}
+
+// update applies fn to a stored team and returns the joined team, nil if the team does not exist
+func (repo *boltRepository) update(id string, fn func(tx *bolt.Tx, t *boltdb.Team) error) (*model.Team, error) {
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	var data *model.Team
+
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		teams := tx.Bucket(boltdb.TeamsBucket)
+
+		var t boltdb.Team
+		found, err := boltdb.Get(teams, oid, &t)
+		if err != nil || !found {
+			return err
+		}
+
+		if err = fn(tx, &t); err != nil {
+			return err
+		}
+
+		if err = boltdb.Put(teams, oid, t); err != nil {
+			return err
+		}
+
+		data, err = repo.getTeam(tx, oid)
+		return err
+	})
+
+	return data, err
+}
+
+// UpdateTeam replaces the fields of a team, the roster is left untouched
+func (repo *boltRepository) UpdateTeam(ctx context.Context, id string, data model.Team) (*model.Team, error) {
+	op := "team.Repository.UpdateTeam"
+
+	res, err := repo.update(id, func(tx *bolt.Tx, t *boltdb.Team) error {
+		t.Name = data.Name
+		t.Description = data.Description
+		t.Location = data.Location
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return res, nil
+}
+
+// PatchTeam applies a JSON merge patch to a team, null values remove the field
+func (repo *boltRepository) PatchTeam(ctx context.Context, id string, patch map[string]interface{}) (*model.Team, error) {
+	op := "team.Repository.PatchTeam"
+
+	res, err := repo.update(id, func(tx *bolt.Tx, t *boltdb.Team) error {
+		for k, v := range patch {
+			s, _ := v.(string)
+			switch k {
+			case "name":
+				t.Name = s
+			case "description":
+				t.Description = s
+			case "location":
+				t.Location = s
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return res, nil
+}
+
+// DeleteTeam deletes a team and returns the deleted team, the players are kept
+func (repo *boltRepository) DeleteTeam(ctx context.Context, id string) (*model.Team, error) {
+	op := "team.Repository.DeleteTeam"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	var data *model.Team
+
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		data, err = repo.getTeam(tx, oid)
+		if err != nil || data == nil {
+			return err
+		}
+
+		return tx.Bucket(boltdb.TeamsBucket).Delete(oid[:])
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return data, nil
+}
+
+// AddPlayers attaches existing players to a team, a player can only belong to one team
+func (repo *boltRepository) AddPlayers(ctx context.Context, id string, playerIDs []string) (*model.Team, error) {
+	op := "team.Repository.AddPlayers"
+
+	res, err := repo.update(id, func(tx *bolt.Tx, t *boltdb.Team) error {
+
+		players := tx.Bucket(boltdb.PlayersBucket)
+		seen := map[primitive.ObjectID]bool{}
+		oids := []primitive.ObjectID{}
+
+		for _, pid := range playerIDs {
+			poid, _ := primitive.ObjectIDFromHex(pid)
+			if seen[poid] {
+				continue
+			}
+			seen[poid] = true
+
+			if players.Get(poid[:]) == nil {
+				return ErrPlayerNotFound
+			}
+			oids = append(oids, poid)
+		}
+
+		all, err := repo.all(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, other := range all {
+			if other.ID == t.ID {
+				continue
+			}
+			for _, pid := range other.Players {
+				if seen[pid] {
+					return ErrPlayerHasTeam
+				}
+			}
+		}
+
+		for _, poid := range oids {
+			if !containsID(t.Players, poid) {
+				t.Players = append(t.Players, poid)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return res, nil
+}
+
+// RemovePlayer detaches a player from a team, the player itself is kept
+func (repo *boltRepository) RemovePlayer(ctx context.Context, id string, playerID string) (*model.Team, error) {
+	op := "team.Repository.RemovePlayer"
+
+	poid, _ := primitive.ObjectIDFromHex(playerID)
+
+	res, err := repo.update(id, func(tx *bolt.Tx, t *boltdb.Team) error {
+		if !containsID(t.Players, poid) {
+			return ErrPlayerNotInTeam
+		}
+		t.Players = removeID(t.Players, poid)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return res, nil
+}
+
+// TransferPlayer moves a player from one team to another in a single transaction
+func (repo *boltRepository) TransferPlayer(ctx context.Context, playerID string, fromID string, toID string) (*model.Team, error) {
+	op := "team.Repository.TransferPlayer"
+
+	poid, _ := primitive.ObjectIDFromHex(playerID)
+	fromOID, _ := primitive.ObjectIDFromHex(fromID)
+	toOID, _ := primitive.ObjectIDFromHex(toID)
+
+	var data *model.Team
+
+	err := repo.db.Update(func(tx *bolt.Tx) error {
+		teams := tx.Bucket(boltdb.TeamsBucket)
+
+		var from, to boltdb.Team
+
+		found, err := boltdb.Get(teams, fromOID, &from)
+		if err != nil || !found {
+			return err
+		}
+
+		found, err = boltdb.Get(teams, toOID, &to)
+		if err != nil || !found {
+			return err
+		}
+
+		if !containsID(from.Players, poid) {
+			return ErrPlayerNotInTeam
+		}
+
+		from.Players = removeID(from.Players, poid)
+		if !containsID(to.Players, poid) {
+			to.Players = append(to.Players, poid)
+		}
+
+		if err = boltdb.Put(teams, fromOID, from); err != nil {
+			return err
+		}
+		if err = boltdb.Put(teams, toOID, to); err != nil {
+			return err
+		}
+
+		data, err = repo.getTeam(tx, toOID)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return data, nil
+}
+
+// SearchTeam returns the teams matching a text search by relevance
+func (repo *boltRepository) SearchTeam(ctx context.Context, q string, limit int) ([]model.SearchResult, error) {
+	op := "team.Repository.SearchTeam"
+
+	res := []model.SearchResult{}
+
+	err := repo.db.View(func(tx *bolt.Tx) error {
+		all, err := repo.all(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, t := range all {
+			score := storage.TextScore(q,
+				storage.Text{Value: t.Name, Weight: 10},
+				storage.Text{Value: t.Location, Weight: 5},
+				storage.Text{Value: t.Description, Weight: 1},
+			)
+			if score <= 0 {
+				continue
+			}
+
+			data, err := repo.toModel(tx, t)
+			if err != nil {
+				return err
+			}
+			res = append(res, model.SearchResult{Type: model.SearchTypeTeam, Score: score, Data: data})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Score > res[j].Score
+	})
+
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
+
+// EnsureIndexes does nothing, the buckets are created by the schema migrations
+func (repo *boltRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
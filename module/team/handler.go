@@ -1,9 +1,15 @@
 package team
 
 import (
+	"encoding/json"
+
 	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
 	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/player"
 	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Handler represents the httphandler for team
@@ -11,6 +17,16 @@ type Handler struct {
 	teamRepo Repository
 }
 
+// playersBody represents the payload to add players to a team
+type playersBody struct {
+	Players []string `json:"players"`
+}
+
+// transferBody represents the payload to transfer a player to another team
+type transferBody struct {
+	TeamID string `json:"team_id"`
+}
+
 // NewHandler initializes endpoints for team
 func NewHandler(e *echo.Echo, teamRepo Repository) {
 	handler := &Handler{
@@ -20,19 +36,30 @@ func NewHandler(e *echo.Echo, teamRepo Repository) {
 	e.GET("/teams", handler.GetAll)
 	e.POST("/teams", handler.Post)
 	e.GET("/teams/:id", handler.GetByID)
+	e.PUT("/teams/:id", handler.Put)
+	e.PATCH("/teams/:id", handler.Patch)
+	e.DELETE("/teams/:id", handler.Delete)
+	e.POST("/teams/:id/players", handler.PostPlayers)
+	e.DELETE("/teams/:id/players/:playerId", handler.DeletePlayer)
+	e.POST("/teams/:id/players/:playerId/transfer", handler.TransferPlayer)
 }
 
-// GetAll returns list of teams
+// GetAll returns a page of teams
 func (h *Handler) GetAll(c echo.Context) error {
 
 	ctx := c.Request().Context()
 
-	res, err := h.teamRepo.ListTeam(ctx)
+	params, err := query.ParseParams(c.QueryParams(), Fields)
+	if err != nil {
+		return api.ResponseBadRequest(c, err.Error())
+	}
+
+	res, page, err := h.teamRepo.ListTeam(ctx, params)
 	if err != nil {
 		return api.ResponseError(c, err)
 	}
 
-	return api.ResponseOK(c, res)
+	return api.ResponsePage(c, res, page)
 }
 
 // GetByID returns a team by id
@@ -63,26 +90,230 @@ func (h *Handler) Post(c echo.Context) error {
 		return api.ResponseUnprocessableEntity(c, "invalid body")
 	}
 
+	for i := range body.Players {
+		player.Normalize(&body.Players[i])
+	}
+
+	if msg := validateTeam(body); len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
+	}
+
+	res, err := h.teamRepo.CreateTeam(ctx, body)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	return api.ResponseCreated(c, res)
+}
+
+// Put replaces a team by id
+func (h *Handler) Put(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	// Bind would try to bind the id path param into the body
+	var body model.Team
+	err := json.NewDecoder(c.Request().Body).Decode(&body)
+	if err != nil {
+		return api.ResponseUnprocessableEntity(c, "invalid body")
+	}
+
+	// The roster is only changed by the players endpoints
+	if body.Players != nil {
+		return api.ResponseBadRequest(c, "players cannot be replaced, use POST /teams/:id/players and DELETE /teams/:id/players/:playerId")
+	}
+
+	if msg := validateTeam(body); len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
+	}
+
+	res, err := h.teamRepo.UpdateTeam(ctx, c.Param("id"), body)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested team")
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// Patch partially updates a team by id using JSON merge patch
+func (h *Handler) Patch(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	var body map[string]interface{}
+	err := json.NewDecoder(c.Request().Body).Decode(&body)
+	if err != nil || body == nil {
+		return api.ResponseUnprocessableEntity(c, "invalid body")
+	}
+
+	if msg := validateTeamPatch(body); len(msg) > 0 {
+		return api.ResponseBadRequest(c, msg)
+	}
+
+	res, err := h.teamRepo.PatchTeam(ctx, c.Param("id"), body)
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested team")
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// Delete deletes a team by id
+func (h *Handler) Delete(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	res, err := h.teamRepo.DeleteTeam(ctx, c.Param("id"))
+	if err != nil {
+		return api.ResponseError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested team")
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// PostPlayers adds existing players to a team
+func (h *Handler) PostPlayers(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	var body playersBody
+	err := json.NewDecoder(c.Request().Body).Decode(&body)
+	if err != nil {
+		return api.ResponseUnprocessableEntity(c, "invalid body")
+	}
+
+	if len(body.Players) <= 0 {
+		return api.ResponseBadRequest(c, "players cannot be empty")
+	}
+
+	for _, id := range body.Players {
+		if _, err := primitive.ObjectIDFromHex(id); err != nil {
+			return api.ResponseBadRequest(c, "invalid player id "+id)
+		}
+	}
+
+	res, err := h.teamRepo.AddPlayers(ctx, c.Param("id"), body.Players)
+	if err != nil {
+		return responseRosterError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested team")
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// DeletePlayer removes a player from a team
+func (h *Handler) DeletePlayer(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	res, err := h.teamRepo.RemovePlayer(ctx, c.Param("id"), c.Param("playerId"))
+	if err != nil {
+		return responseRosterError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested team")
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// TransferPlayer moves a player from a team to another and returns the destination team
+func (h *Handler) TransferPlayer(c echo.Context) error {
+
+	ctx := c.Request().Context()
+
+	var body transferBody
+	err := json.NewDecoder(c.Request().Body).Decode(&body)
+	if err != nil {
+		return api.ResponseUnprocessableEntity(c, "invalid body")
+	}
+
+	if len(body.TeamID) <= 0 {
+		return api.ResponseBadRequest(c, "team_id cannot be empty")
+	}
+	if body.TeamID == c.Param("id") {
+		return api.ResponseBadRequest(c, "team_id must be a different team")
+	}
+
+	res, err := h.teamRepo.TransferPlayer(ctx, c.Param("playerId"), c.Param("id"), body.TeamID)
+	if err != nil {
+		return responseRosterError(c, err)
+	}
+
+	if res == nil {
+		return api.ResponseNotFound(c, "cannot find the requested team")
+	}
+
+	return api.ResponseOK(c, res)
+}
+
+// responseRosterError maps roster errors of the repository to responses
+func responseRosterError(c echo.Context, err error) error {
+
+	switch errors.Cause(err) {
+	case ErrPlayerNotFound:
+		return api.ResponseNotFound(c, "cannot find the requested player")
+	case ErrPlayerNotInTeam:
+		return api.ResponseNotFound(c, ErrPlayerNotInTeam.Error())
+	case ErrPlayerHasTeam:
+		return api.ResponseConflict(c, ErrPlayerHasTeam.Error())
+	}
+
+	return api.ResponseError(c, err)
+}
+
+// validateTeam returns the validation message of a team, empty if valid
+func validateTeam(body model.Team) string {
+
 	if len(body.Name) <= 0 {
-		return api.ResponseBadRequest(c, "name cannot be empty")
+		return "name cannot be empty"
 	}
 	if len(body.Location) <= 0 {
-		return api.ResponseBadRequest(c, "location cannot be empty")
+		return "location cannot be empty"
 	}
 
 	for _, p := range body.Players {
-		if len(p.Name) <= 0 {
-			return api.ResponseBadRequest(c, "player name cannot be empty")
-		}
-		if len(p.Position) <= 0 {
-			return api.ResponseBadRequest(c, "player position cannot be empty")
+		if msg := player.ValidatePlayer(p); len(msg) > 0 {
+			return "player " + msg
 		}
 	}
 
-	res, err := h.teamRepo.CreateTeam(ctx, body)
-	if err != nil {
-		return api.ResponseError(c, err)
+	return ""
+}
+
+// validateTeamPatch returns the validation message of a team merge patch, empty if valid
+func validateTeamPatch(body map[string]interface{}) string {
+
+	for k, v := range body {
+		switch k {
+		case "name", "location":
+			if s, ok := v.(string); !ok || len(s) <= 0 {
+				return k + " cannot be empty"
+			}
+		case "description":
+			if _, ok := v.(string); !ok && v != nil {
+				return k + " must be a string"
+			}
+		default:
+			return k + " cannot be patched"
+		}
 	}
 
-	return api.ResponseCreated(c, res)
+	return ""
 }
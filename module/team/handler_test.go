@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/labstack/echo/v4"
@@ -13,6 +14,7 @@ import (
 	"github.com/yezarela/go-soccer/model"
 	teamMock "github.com/yezarela/go-soccer/module/team/mock"
 	"github.com/yezarela/go-soccer/pkg/api"
+	"github.com/yezarela/go-soccer/pkg/query"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -160,6 +162,38 @@ func TestPost(t *testing.T) {
 			assert.Equal(t, http.StatusBadRequest, rec.Code)
 		}
 	})
+
+	t.Run("Response Bad Request Player", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		// The players of a team are validated like the players created alone
+		payloads := []string{
+			`{"name":"Arsenal","location":"London","players":[{"name":"Bukayo Saka","position":"RW","nationality":"EN"}]}`,
+			`{"name":"Arsenal","location":"London","players":[{"name":"Bukayo Saka","position":"winger"}]}`,
+		}
+
+		for _, mockPayload := range payloads {
+
+			// Setup
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/teams", strings.NewReader(mockPayload))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			h := &Handler{
+				teamRepo: mockRepo,
+			}
+
+			// Assertions
+			if assert.NoError(t, h.Post(c)) {
+				assert.Equal(t, http.StatusBadRequest, rec.Code, mockPayload)
+			}
+		}
+	})
 }
 
 func TestGetAll(t *testing.T) {
@@ -173,12 +207,14 @@ func TestGetAll(t *testing.T) {
 		mockTeam := model.Team{}
 		mockTeam.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
 		mockTeams := []model.Team{mockTeam}
+		mockPage := &query.Page{}
 
 		mockResp, _ := json.Marshal(api.Response{
 			Meta: api.ResponseMeta{
 				Code: http.StatusOK,
 			},
-			Data: mockTeams,
+			Data:       mockTeams,
+			Pagination: mockPage,
 		})
 
 		// Setup
@@ -193,12 +229,712 @@ func TestGetAll(t *testing.T) {
 
 		ctx := c.Request().Context()
 
-		mockRepo.EXPECT().ListTeam(ctx).Return(mockTeams, nil)
+		mockRepo.EXPECT().ListTeam(ctx, query.Params{Limit: query.DefaultLimit, Sort: query.DefaultSort}).Return(mockTeams, mockPage, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+			assert.Empty(t, rec.Header().Get("Link"))
+		}
+	})
+
+	t.Run("Response OK With Next Page", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		mockTeam := model.Team{}
+		mockTeam.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockTeams := []model.Team{mockTeam}
+
+		mockTeam.CreatedAt = time.Date(2020, 9, 22, 20, 24, 1, 0, time.UTC)
+		mockParams := query.Params{Limit: 1, Sort: query.DefaultSort}
+		mockCursor := query.Cursor{Sort: "created_at,_id", Values: []interface{}{mockTeam.CreatedAt, mockTeam.ID}}
+		mockPage := &query.Page{NextCursor: mockParams.NextCursor(map[string]interface{}{"created_at": mockTeam.CreatedAt, "_id": mockTeam.ID}), HasMore: true}
+		mockParams.Cursor = &mockCursor
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/teams?limit=1&cursor="+mockPage.NextCursor, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().ListTeam(ctx, mockParams).Return(mockTeams, mockPage, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "<http://example.com/teams?cursor="+mockPage.NextCursor+"&limit=1>; rel=\"next\"", rec.Header().Get("Link"))
+		}
+	})
+
+	t.Run("Response OK With Filters", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		mockTeams := []model.Team{}
+		mockPage := &query.Page{}
+		mockParams := query.Params{
+			Limit: query.DefaultLimit,
+			Filters: []query.Filter{
+				{Field: "created_at", Op: "gte", Value: time.Date(2020, 9, 22, 0, 0, 0, 0, time.UTC)},
+				{Field: "location", Op: "eq", Value: "Italy"},
+			},
+			Sort: []query.Sort{{Field: "name", Desc: true}, {Field: "_id"}},
+		}
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/teams?location=Italy&created_at[gte]=2020-09-22&sort=-name", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().ListTeam(ctx, mockParams).Return(mockTeams, mockPage, nil)
+
+		// Assertions
+		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request Unknown Field", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/teams?foo=bar", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/teams?cursor=invalid", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
 
 		// Assertions
 		if assert.NoError(t, h.GetAll(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+}
+
+func TestPut(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		mockTeam := model.Team{}
+		mockTeam.Name = "Arsenal"
+		mockTeam.Location = "London"
+		mockTeamID := "5f6a5d6129b2289c40b7444b"
+
+		mockPayload, _ := json.Marshal(mockTeam)
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockTeam,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockTeamID)
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().UpdateTeam(ctx, mockTeamID, mockTeam).Return(&mockTeam, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Put(c)) {
 			assert.Equal(t, http.StatusOK, rec.Code)
 			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
 		}
 	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		mockTeam := model.Team{}
+		mockTeam.Name = "Arsenal"
+
+		mockPayload, _ := json.Marshal(mockTeam)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("5f6a5d6129b2289c40b7444b")
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.Put(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request With Players", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		mockTeam := model.Team{}
+		mockTeam.Name = "Arsenal"
+		mockTeam.Location = "London"
+		mockTeam.Players = []model.Player{{Name: "Thierry Henry", Position: model.PositionStriker}}
+
+		mockPayload, _ := json.Marshal(mockTeam)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("5f6a5d6129b2289c40b7444b")
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		// Assertions, the players would not be stored
+		if assert.NoError(t, h.Put(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), "POST /teams/:id/players")
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		mockTeam := model.Team{}
+		mockTeam.Name = "Arsenal"
+		mockTeam.Location = "London"
+		mockTeamID := "5f6a5d6129b2289c40b7444b"
+
+		mockPayload, _ := json.Marshal(mockTeam)
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockTeamID)
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().UpdateTeam(ctx, mockTeamID, mockTeam).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Put(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+}
+
+func TestPatch(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		mockTeam := model.Team{}
+		mockTeam.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockTeam.Name = "Arsenal"
+		mockTeam.Location = "London"
+
+		mockPatch := map[string]interface{}{"name": "Arsenal", "description": nil}
+
+		mockPayload, _ := json.Marshal(mockPatch)
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockTeam,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockTeam.ID.Hex())
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().PatchTeam(ctx, mockTeam.ID.Hex(), mockPatch).Return(&mockTeam, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Patch(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		mockPayload, _ := json.Marshal(map[string]interface{}{"location": nil})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPatch, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id")
+		c.SetParamNames("id")
+		c.SetParamValues("5f6a5d6129b2289c40b7444b")
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.Patch(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+}
+
+func TestDelete(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		mockTeam := model.Team{}
+		mockTeam.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockTeam,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockTeam.ID.Hex())
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().DeleteTeam(ctx, mockTeam.ID.Hex()).Return(&mockTeam, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Delete(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+		mockTeamID := "5f6a5d6129b2289c40b7444b"
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id")
+		c.SetParamNames("id")
+		c.SetParamValues(mockTeamID)
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().DeleteTeam(ctx, mockTeamID).Return(nil, nil)
+
+		// Assertions
+		if assert.NoError(t, h.Delete(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+}
+
+func TestPostPlayers(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		mockPlayer := model.Player{}
+		mockPlayer.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b74448")
+
+		mockTeam := model.Team{}
+		mockTeam.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockTeam.Players = []model.Player{mockPlayer}
+
+		mockPlayerIDs := []string{mockPlayer.ID.Hex()}
+
+		mockPayload, _ := json.Marshal(map[string]interface{}{"players": mockPlayerIDs})
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockTeam,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id/players")
+		c.SetParamNames("id")
+		c.SetParamValues(mockTeam.ID.Hex())
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().AddPlayers(ctx, mockTeam.ID.Hex(), mockPlayerIDs).Return(&mockTeam, nil)
+
+		// Assertions
+		if assert.NoError(t, h.PostPlayers(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Conflict", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+		mockTeamID := "5f6a5d6129b2289c40b7444b"
+		mockPlayerIDs := []string{"5f6a5d6129b2289c40b74448"}
+
+		mockPayload, _ := json.Marshal(map[string]interface{}{"players": mockPlayerIDs})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id/players")
+		c.SetParamNames("id")
+		c.SetParamValues(mockTeamID)
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().AddPlayers(ctx, mockTeamID, mockPlayerIDs).Return(nil, ErrPlayerHasTeam)
+
+		// Assertions
+		if assert.NoError(t, h.PostPlayers(c)) {
+			assert.Equal(t, http.StatusConflict, rec.Code)
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		mockPayload, _ := json.Marshal(map[string]interface{}{"players": []string{"invalid"}})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id/players")
+		c.SetParamNames("id")
+		c.SetParamValues("5f6a5d6129b2289c40b7444b")
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.PostPlayers(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
+}
+
+func TestDeletePlayer(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		mockTeam := model.Team{}
+		mockTeam.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444b")
+		mockPlayerID := "5f6a5d6129b2289c40b74448"
+
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockTeam,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id/players/:playerId")
+		c.SetParamNames("id", "playerId")
+		c.SetParamValues(mockTeam.ID.Hex(), mockPlayerID)
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().RemovePlayer(ctx, mockTeam.ID.Hex(), mockPlayerID).Return(&mockTeam, nil)
+
+		// Assertions
+		if assert.NoError(t, h.DeletePlayer(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Not Found", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+		mockTeamID := "5f6a5d6129b2289c40b7444b"
+		mockPlayerID := "5f6a5d6129b2289c40b74448"
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodDelete, "/", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id/players/:playerId")
+		c.SetParamNames("id", "playerId")
+		c.SetParamValues(mockTeamID, mockPlayerID)
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().RemovePlayer(ctx, mockTeamID, mockPlayerID).Return(nil, ErrPlayerNotInTeam)
+
+		// Assertions
+		if assert.NoError(t, h.DeletePlayer(c)) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		}
+	})
+}
+
+func TestTransferPlayer(t *testing.T) {
+
+	t.Run("Response OK", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+
+		mockPlayer := model.Player{}
+		mockPlayer.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b74448")
+
+		mockTeam := model.Team{}
+		mockTeam.ID, _ = primitive.ObjectIDFromHex("5f6a5d6129b2289c40b7444c")
+		mockTeam.Players = []model.Player{mockPlayer}
+		mockFromID := "5f6a5d6129b2289c40b7444b"
+
+		mockPayload, _ := json.Marshal(map[string]interface{}{"team_id": mockTeam.ID.Hex()})
+		mockResp, _ := json.Marshal(api.Response{
+			Meta: api.ResponseMeta{
+				Code: http.StatusOK,
+			},
+			Data: mockTeam,
+		})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id/players/:playerId/transfer")
+		c.SetParamNames("id", "playerId")
+		c.SetParamValues(mockFromID, mockPlayer.ID.Hex())
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		ctx := c.Request().Context()
+
+		mockRepo.EXPECT().TransferPlayer(ctx, mockPlayer.ID.Hex(), mockFromID, mockTeam.ID.Hex()).Return(&mockTeam, nil)
+
+		// Assertions
+		if assert.NoError(t, h.TransferPlayer(c)) {
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, string(mockResp), strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	})
+
+	t.Run("Response Bad Request", func(t *testing.T) {
+
+		// Mock
+		ctrl := gomock.NewController(t)
+		mockRepo := teamMock.NewMockRepository(ctrl)
+		mockTeamID := "5f6a5d6129b2289c40b7444b"
+
+		mockPayload, _ := json.Marshal(map[string]interface{}{"team_id": mockTeamID})
+
+		// Setup
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(mockPayload)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		c.SetPath("/teams/:id/players/:playerId/transfer")
+		c.SetParamNames("id", "playerId")
+		c.SetParamValues(mockTeamID, "5f6a5d6129b2289c40b74448")
+
+		h := &Handler{
+			teamRepo: mockRepo,
+		}
+
+		// Assertions
+		if assert.NoError(t, h.TransferPlayer(c)) {
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+		}
+	})
 }
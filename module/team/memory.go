@@ -0,0 +1,358 @@
+package team
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/memdb"
+	"github.com/yezarela/go-soccer/pkg/query"
+	"github.com/yezarela/go-soccer/pkg/storage"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryRepository struct {
+	db *memdb.DB
+}
+
+// NewMemoryRepository creates a new team repository stored in memory
+func NewMemoryRepository(db *memdb.DB) Repository {
+	return &memoryRepository{db}
+}
+
+// toModel joins a stored team with its existing players, the database must be locked
+func (repo *memoryRepository) toModel(t memdb.Team) model.Team {
+
+	data := model.Team{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Location:    t.Location,
+		Players:     []model.Player{},
+		CreatedAt:   t.CreatedAt,
+	}
+
+	for _, pid := range t.Players {
+		if p, ok := repo.db.Players[pid]; ok {
+			data.Players = append(data.Players, p)
+		}
+	}
+
+	return data
+}
+
+// ListTeam returns a filtered and sorted page of teams
+func (repo *memoryRepository) ListTeam(ctx context.Context, params query.Params) ([]model.Team, *query.Page, error) {
+	repo.db.RLock()
+	defer repo.db.RUnlock()
+
+	var items []model.Team
+
+	for _, t := range repo.db.Teams {
+		data := repo.toModel(t)
+		if params.Match(cursorValues(data)) {
+			items = append(items, data)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return params.Less(cursorValues(items[i]), cursorValues(items[j]))
+	})
+
+	page, n, err := params.NewPage(len(items), func(i int) map[string]interface{} {
+		return cursorValues(items[i])
+	}, query.Total(len(repo.db.Teams)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return items[:n], page, nil
+}
+
+// GetTeam returns a team by id
+func (repo *memoryRepository) GetTeam(ctx context.Context, id string) (*model.Team, error) {
+	repo.db.RLock()
+	defer repo.db.RUnlock()
+
+	return repo.getTeam(id), nil
+}
+
+// getTeam returns a team by id, the database must be locked by the caller
+func (repo *memoryRepository) getTeam(id string) *model.Team {
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	t, ok := repo.db.Teams[oid]
+	if !ok {
+		return nil
+	}
+
+	data := repo.toModel(t)
+
+	return &data
+}
+
+// CreateTeam creates a new team and its players
+func (repo *memoryRepository) CreateTeam(ctx context.Context, data model.Team) (*model.Team, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	now := storage.Now()
+
+	t := memdb.Team{
+		ID:          primitive.NewObjectID(),
+		Name:        data.Name,
+		Description: data.Description,
+		Location:    data.Location,
+		Players:     []primitive.ObjectID{},
+		CreatedAt:   now,
+	}
+
+	for _, p := range data.Players {
+		p.ID = primitive.NewObjectID()
+		p.CreatedAt = now
+		repo.db.Players[p.ID] = p
+		t.Players = append(t.Players, p.ID)
+	}
+
+	repo.db.Teams[t.ID] = t
+
+	res := repo.toModel(t)
+
+	return &res, nil
+}
+
+// UpdateTeam replaces the fields of a team, the roster is left untouched
+func (repo *memoryRepository) UpdateTeam(ctx context.Context, id string, data model.Team) (*model.Team, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	t, ok := repo.db.Teams[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	t.Name = data.Name
+	t.Description = data.Description
+	t.Location = data.Location
+
+	repo.db.Teams[oid] = t
+
+	return repo.getTeam(id), nil
+}
+
+// PatchTeam applies a JSON merge patch to a team, null values remove the field
+func (repo *memoryRepository) PatchTeam(ctx context.Context, id string, patch map[string]interface{}) (*model.Team, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	t, ok := repo.db.Teams[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	for k, v := range patch {
+		s, _ := v.(string)
+		switch k {
+		case "name":
+			t.Name = s
+		case "description":
+			t.Description = s
+		case "location":
+			t.Location = s
+		}
+	}
+
+	repo.db.Teams[oid] = t
+
+	return repo.getTeam(id), nil
+}
+
+// DeleteTeam deletes a team and returns the deleted team, the players are kept
+func (repo *memoryRepository) DeleteTeam(ctx context.Context, id string) (*model.Team, error) {
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	data := repo.getTeam(id)
+	if data == nil {
+		return nil, nil
+	}
+
+	delete(repo.db.Teams, data.ID)
+
+	return data, nil
+}
+
+// AddPlayers attaches existing players to a team, a player can only belong to one team
+func (repo *memoryRepository) AddPlayers(ctx context.Context, id string, playerIDs []string) (*model.Team, error) {
+	op := "team.Repository.AddPlayers"
+
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	t, ok := repo.db.Teams[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	oids := []primitive.ObjectID{}
+	seen := map[primitive.ObjectID]bool{}
+
+	for _, pid := range playerIDs {
+		poid, _ := primitive.ObjectIDFromHex(pid)
+		if seen[poid] {
+			continue
+		}
+		seen[poid] = true
+
+		if _, ok := repo.db.Players[poid]; !ok {
+			return nil, errors.Wrap(ErrPlayerNotFound, op)
+		}
+		oids = append(oids, poid)
+	}
+
+	for tid, other := range repo.db.Teams {
+		if tid == oid {
+			continue
+		}
+		for _, pid := range other.Players {
+			if seen[pid] {
+				return nil, errors.Wrap(ErrPlayerHasTeam, op)
+			}
+		}
+	}
+
+	players := append([]primitive.ObjectID{}, t.Players...)
+	for _, poid := range oids {
+		if !containsID(players, poid) {
+			players = append(players, poid)
+		}
+	}
+	t.Players = players
+
+	repo.db.Teams[oid] = t
+
+	return repo.getTeam(id), nil
+}
+
+// RemovePlayer detaches a player from a team, the player itself is kept
+func (repo *memoryRepository) RemovePlayer(ctx context.Context, id string, playerID string) (*model.Team, error) {
+	op := "team.Repository.RemovePlayer"
+
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+	poid, _ := primitive.ObjectIDFromHex(playerID)
+
+	t, ok := repo.db.Teams[oid]
+	if !ok {
+		return nil, nil
+	}
+
+	if !containsID(t.Players, poid) {
+		return nil, errors.Wrap(ErrPlayerNotInTeam, op)
+	}
+
+	t.Players = removeID(t.Players, poid)
+	repo.db.Teams[oid] = t
+
+	return repo.getTeam(id), nil
+}
+
+// TransferPlayer moves a player from one team to another and returns the destination team
+func (repo *memoryRepository) TransferPlayer(ctx context.Context, playerID string, fromID string, toID string) (*model.Team, error) {
+	op := "team.Repository.TransferPlayer"
+
+	repo.db.Lock()
+	defer repo.db.Unlock()
+
+	poid, _ := primitive.ObjectIDFromHex(playerID)
+	fromOID, _ := primitive.ObjectIDFromHex(fromID)
+	toOID, _ := primitive.ObjectIDFromHex(toID)
+
+	from, ok := repo.db.Teams[fromOID]
+	if !ok {
+		return nil, nil
+	}
+
+	to, ok := repo.db.Teams[toOID]
+	if !ok {
+		return nil, nil
+	}
+
+	if !containsID(from.Players, poid) {
+		return nil, errors.Wrap(ErrPlayerNotInTeam, op)
+	}
+
+	from.Players = removeID(from.Players, poid)
+	if !containsID(to.Players, poid) {
+		to.Players = append(append([]primitive.ObjectID{}, to.Players...), poid)
+	}
+
+	repo.db.Teams[fromOID] = from
+	repo.db.Teams[toOID] = to
+
+	return repo.getTeam(toID), nil
+}
+
+// SearchTeam returns the teams matching a text search by relevance
+func (repo *memoryRepository) SearchTeam(ctx context.Context, q string, limit int) ([]model.SearchResult, error) {
+	repo.db.RLock()
+	defer repo.db.RUnlock()
+
+	res := []model.SearchResult{}
+
+	for _, t := range repo.db.Teams {
+		score := storage.TextScore(q,
+			storage.Text{Value: t.Name, Weight: 10},
+			storage.Text{Value: t.Location, Weight: 5},
+			storage.Text{Value: t.Description, Weight: 1},
+		)
+		if score > 0 {
+			res = append(res, model.SearchResult{Type: model.SearchTypeTeam, Score: score, Data: repo.toModel(t)})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Score > res[j].Score
+	})
+
+	if len(res) > limit {
+		res = res[:limit]
+	}
+
+	return res, nil
+}
+
+// EnsureIndexes does nothing, the in-memory storage has no indexes
+func (repo *memoryRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+func containsID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// removeID returns a copy of ids without id
+func removeID(ids []primitive.ObjectID, id primitive.ObjectID) []primitive.ObjectID {
+	res := []primitive.ObjectID{}
+	for _, v := range ids {
+		if v != id {
+			res = append(res, v)
+		}
+	}
+	return res
+}
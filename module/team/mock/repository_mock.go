@@ -8,6 +8,7 @@ import (
 	context "context"
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/yezarela/go-soccer/model"
+	query "github.com/yezarela/go-soccer/pkg/query"
 	reflect "reflect"
 )
 
@@ -35,18 +36,19 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // ListTeam mocks base method
-func (m *MockRepository) ListTeam(ctx context.Context) ([]model.Team, error) {
+func (m *MockRepository) ListTeam(ctx context.Context, params query.Params) ([]model.Team, *query.Page, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTeam", ctx)
+	ret := m.ctrl.Call(m, "ListTeam", ctx, params)
 	ret0, _ := ret[0].([]model.Team)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(*query.Page)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // ListTeam indicates an expected call of ListTeam
-func (mr *MockRepositoryMockRecorder) ListTeam(ctx interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) ListTeam(ctx, params interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTeam", reflect.TypeOf((*MockRepository)(nil).ListTeam), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTeam", reflect.TypeOf((*MockRepository)(nil).ListTeam), ctx, params)
 }
 
 // GetTeam mocks base method
@@ -78,3 +80,122 @@ func (mr *MockRepositoryMockRecorder) CreateTeam(ctx, data interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTeam", reflect.TypeOf((*MockRepository)(nil).CreateTeam), ctx, data)
 }
+
+// UpdateTeam mocks base method
+func (m *MockRepository) UpdateTeam(ctx context.Context, id string, data model.Team) (*model.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTeam", ctx, id, data)
+	ret0, _ := ret[0].(*model.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTeam indicates an expected call of UpdateTeam
+func (mr *MockRepositoryMockRecorder) UpdateTeam(ctx, id, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTeam", reflect.TypeOf((*MockRepository)(nil).UpdateTeam), ctx, id, data)
+}
+
+// PatchTeam mocks base method
+func (m *MockRepository) PatchTeam(ctx context.Context, id string, patch map[string]interface{}) (*model.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchTeam", ctx, id, patch)
+	ret0, _ := ret[0].(*model.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchTeam indicates an expected call of PatchTeam
+func (mr *MockRepositoryMockRecorder) PatchTeam(ctx, id, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchTeam", reflect.TypeOf((*MockRepository)(nil).PatchTeam), ctx, id, patch)
+}
+
+// DeleteTeam mocks base method
+func (m *MockRepository) DeleteTeam(ctx context.Context, id string) (*model.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTeam", ctx, id)
+	ret0, _ := ret[0].(*model.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTeam indicates an expected call of DeleteTeam
+func (mr *MockRepositoryMockRecorder) DeleteTeam(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTeam", reflect.TypeOf((*MockRepository)(nil).DeleteTeam), ctx, id)
+}
+
+// AddPlayers mocks base method
+func (m *MockRepository) AddPlayers(ctx context.Context, id string, playerIDs []string) (*model.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPlayers", ctx, id, playerIDs)
+	ret0, _ := ret[0].(*model.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddPlayers indicates an expected call of AddPlayers
+func (mr *MockRepositoryMockRecorder) AddPlayers(ctx, id, playerIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPlayers", reflect.TypeOf((*MockRepository)(nil).AddPlayers), ctx, id, playerIDs)
+}
+
+// RemovePlayer mocks base method
+func (m *MockRepository) RemovePlayer(ctx context.Context, id, playerID string) (*model.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemovePlayer", ctx, id, playerID)
+	ret0, _ := ret[0].(*model.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemovePlayer indicates an expected call of RemovePlayer
+func (mr *MockRepositoryMockRecorder) RemovePlayer(ctx, id, playerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePlayer", reflect.TypeOf((*MockRepository)(nil).RemovePlayer), ctx, id, playerID)
+}
+
+// TransferPlayer mocks base method
+func (m *MockRepository) TransferPlayer(ctx context.Context, playerID, fromID, toID string) (*model.Team, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferPlayer", ctx, playerID, fromID, toID)
+	ret0, _ := ret[0].(*model.Team)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferPlayer indicates an expected call of TransferPlayer
+func (mr *MockRepositoryMockRecorder) TransferPlayer(ctx, playerID, fromID, toID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferPlayer", reflect.TypeOf((*MockRepository)(nil).TransferPlayer), ctx, playerID, fromID, toID)
+}
+
+// SearchTeam mocks base method
+func (m *MockRepository) SearchTeam(ctx context.Context, q string, limit int) ([]model.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTeam", ctx, q, limit)
+	ret0, _ := ret[0].([]model.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTeam indicates an expected call of SearchTeam
+func (mr *MockRepositoryMockRecorder) SearchTeam(ctx, q, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTeam", reflect.TypeOf((*MockRepository)(nil).SearchTeam), ctx, q, limit)
+}
+
+// EnsureIndexes mocks base method
+func (m *MockRepository) EnsureIndexes(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureIndexes", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureIndexes indicates an expected call of EnsureIndexes
+func (mr *MockRepositoryMockRecorder) EnsureIndexes(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureIndexes", reflect.TypeOf((*MockRepository)(nil).EnsureIndexes), ctx)
+}
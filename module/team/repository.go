@@ -2,49 +2,217 @@ package team
 
 import (
 	"context"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/module/player"
+	"github.com/yezarela/go-soccer/pkg/query"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // Repository represents repository of pkg team
 type Repository interface {
-	ListTeam(ctx context.Context) ([]model.Team, error)
+	ListTeam(ctx context.Context, params query.Params) ([]model.Team, *query.Page, error)
 	GetTeam(ctx context.Context, id string) (*model.Team, error)
 	CreateTeam(ctx context.Context, data model.Team) (*model.Team, error)
+	UpdateTeam(ctx context.Context, id string, data model.Team) (*model.Team, error)
+	PatchTeam(ctx context.Context, id string, patch map[string]interface{}) (*model.Team, error)
+	DeleteTeam(ctx context.Context, id string) (*model.Team, error)
+	AddPlayers(ctx context.Context, id string, playerIDs []string) (*model.Team, error)
+	RemovePlayer(ctx context.Context, id string, playerID string) (*model.Team, error)
+	TransferPlayer(ctx context.Context, playerID string, fromID string, toID string) (*model.Team, error)
+	SearchTeam(ctx context.Context, q string, limit int) ([]model.SearchResult, error)
+	EnsureIndexes(ctx context.Context) error
 }
 
+var (
+	// ErrPlayerNotFound is returned when a player added to a team does not exist
+	ErrPlayerNotFound = errors.New("player does not exist")
+	// ErrPlayerHasTeam is returned when a player already belongs to another team
+	ErrPlayerHasTeam = errors.New("player already belongs to another team")
+	// ErrPlayerNotInTeam is returned when a player does not belong to the team
+	ErrPlayerNotInTeam = errors.New("player does not belong to the team")
+)
+
 type repository struct {
+	db     *mongo.Database
+	writer writer
+}
+
+// errTransactionUnsupported is returned when the server does not support transactions
+var errTransactionUnsupported = errors.New("transactions are not supported")
+
+// writer writes the documents of the teams and their players, tests simulate its failures
+type writer interface {
+	transaction(ctx context.Context, fn func(ctx context.Context) error) error
+	insertPlayers(ctx context.Context, players []interface{}) ([]interface{}, error)
+	insertTeam(ctx context.Context, team interface{}) (interface{}, error)
+	deletePlayers(ctx context.Context, ids []interface{}) error
+	countTeams(ctx context.Context, ids []primitive.ObjectID) (int64, error)
+	pullPlayer(ctx context.Context, teamID primitive.ObjectID, playerID primitive.ObjectID) (bool, error)
+	addPlayer(ctx context.Context, teamID primitive.ObjectID, playerID primitive.ObjectID) (bool, error)
+}
+
+type mongoWriter struct {
 	db *mongo.Database
 }
 
+func (w *mongoWriter) transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	err := w.db.Client().UseSession(ctx, func(sc mongo.SessionContext) error {
+		_, err := sc.WithTransaction(sc, func(sc mongo.SessionContext) (interface{}, error) {
+			return nil, fn(sc)
+		})
+		return err
+	})
+
+	// Standalone servers reject the transaction number sent with the first write
+	if err != nil && strings.Contains(err.Error(), "Transaction numbers are only allowed") {
+		return errTransactionUnsupported
+	}
+
+	return err
+}
+
+func (w *mongoWriter) insertPlayers(ctx context.Context, players []interface{}) ([]interface{}, error) {
+
+	res, err := w.db.Collection("players").InsertMany(ctx, players)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.InsertedIDs, nil
+}
+
+func (w *mongoWriter) insertTeam(ctx context.Context, team interface{}) (interface{}, error) {
+
+	res, err := w.db.Collection("teams").InsertOne(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.InsertedID, nil
+}
+
+func (w *mongoWriter) deletePlayers(ctx context.Context, ids []interface{}) error {
+	_, err := w.db.Collection("players").DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+func (w *mongoWriter) countTeams(ctx context.Context, ids []primitive.ObjectID) (int64, error) {
+	return w.db.Collection("teams").CountDocuments(ctx, bson.M{"_id": bson.M{"$in": ids}})
+}
+
+func (w *mongoWriter) pullPlayer(ctx context.Context, teamID primitive.ObjectID, playerID primitive.ObjectID) (bool, error) {
+
+	res, err := w.db.Collection("teams").UpdateOne(ctx, bson.M{"_id": teamID, "players": playerID}, bson.M{"$pull": bson.M{"players": playerID}})
+	if err != nil {
+		return false, err
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+func (w *mongoWriter) addPlayer(ctx context.Context, teamID primitive.ObjectID, playerID primitive.ObjectID) (bool, error) {
+
+	res, err := w.db.Collection("teams").UpdateOne(ctx, bson.M{"_id": teamID}, bson.M{"$addToSet": bson.M{"players": playerID}})
+	if err != nil {
+		return false, err
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+// isDuplicateKey returns whether a write was rejected by a unique index
+func isDuplicateKey(err error) bool {
+
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		for _, e := range we.WriteErrors {
+			if e.Code == 11000 {
+				return true
+			}
+		}
+	}
+
+	var ce mongo.CommandError
+	return errors.As(err, &ce) && ce.Code == 11000
+}
+
+// lookupPlayers replaces the player ids stored on a team with the player documents
+var lookupPlayers = bson.M{"$lookup": bson.M{"from": "players", "localField": "players", "foreignField": "_id", "as": "players"}}
+
+// Fields are the filterable and sortable fields of a team
+var Fields = query.Fields{
+	"name":        query.String,
+	"description": query.String,
+	"location":    query.String,
+	"created_at":  query.Time,
+}
+
+// cursorValues returns the sortable values of a team
+func cursorValues(data model.Team) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        data.Name,
+		"description": data.Description,
+		"location":    data.Location,
+		"created_at":  data.CreatedAt,
+		"_id":         data.ID,
+	}
+}
+
+// ListAll returns all the teams matching the filters, reading every page of the repository
+func ListAll(ctx context.Context, repo Repository, filters url.Values) ([]model.Team, error) {
+
+	all := []model.Team{}
+
+	err := query.All(filters, Fields, func(params query.Params) (*query.Page, error) {
+		items, page, err := repo.ListTeam(ctx, params)
+		all = append(all, items...)
+		return page, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return all, nil
+}
+
 // NewRepository creates a new team repository
 func NewRepository(db *mongo.Database) Repository {
-	return &repository{db}
+	return &repository{db, &mongoWriter{db}}
 }
 
-// ListTeam returns list of teams
-func (repo *repository) ListTeam(ctx context.Context) ([]model.Team, error) {
+// ListTeam returns a filtered and sorted page of teams
+func (repo *repository) ListTeam(ctx context.Context, params query.Params) ([]model.Team, *query.Page, error) {
 	op := "team.Repository.ListTeam"
 
-	lookup := bson.D{{"$lookup", bson.D{{"from", "players"}, {"localField", "players"}, {"foreignField", "_id"}, {"as", "players"}}}}
+	pipeline := []bson.M{
+		{"$match": params.MongoFilter()},
+		{"$sort": params.MongoSort()},
+		{"$limit": params.FetchLimit()},
+		lookupPlayers,
+	}
 
-	cur, err := repo.db.Collection("teams").Aggregate(ctx, mongo.Pipeline{lookup})
+	cur, err := repo.db.Collection("teams").Aggregate(ctx, pipeline)
 	if err != nil {
-		return nil, errors.Wrap(err, op)
+		return nil, nil, errors.Wrap(err, op)
 	}
 
 	var items []model.Team
 
-	if err = cur.All(ctx, &items); err != nil {
-		return nil, errors.Wrap(err, op)
+	page, err := params.MongoPage(ctx, cur, &items, query.EstimatedCount(ctx, repo.db.Collection("teams")))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, op)
 	}
 
-	return items, nil
+	return items, page, nil
 }
 
 // GetTeam returns a team by id
@@ -53,10 +221,9 @@ func (repo *repository) GetTeam(ctx context.Context, id string) (*model.Team, er
 
 	oid, _ := primitive.ObjectIDFromHex(id)
 
-	lookup1 := bson.D{{"$lookup", bson.D{{"from", "players"}, {"localField", "players"}, {"foreignField", "_id"}, {"as", "players"}}}}
-	lookup2 := bson.D{{"$match", bson.D{{"_id", oid}}}}
+	match := bson.M{"$match": bson.M{"_id": oid}}
 
-	cur, err := repo.db.Collection("teams").Aggregate(ctx, mongo.Pipeline{lookup1, lookup2})
+	cur, err := repo.db.Collection("teams").Aggregate(ctx, []bson.M{match, lookupPlayers})
 	if err != nil {
 		return nil, errors.Wrap(err, op)
 	}
@@ -74,45 +241,424 @@ func (repo *repository) GetTeam(ctx context.Context, id string) (*model.Team, er
 	return nil, nil
 }
 
-// CreateTeam creates a new team
+// CreateTeam creates a new team and its players in a transaction when the server supports them
 func (repo *repository) CreateTeam(ctx context.Context, data model.Team) (*model.Team, error) {
 	op := "team.Repository.CreateTeam"
 
+	var res *model.Team
+
+	err := repo.writer.transaction(ctx, func(ctx context.Context) error {
+		team, _, err := repo.insertTeam(ctx, data)
+		res = team
+		return err
+	})
+
+	if errors.Cause(err) == errTransactionUnsupported {
+		res, err = repo.insertTeamWithCompensation(ctx, data)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return res, nil
+}
+
+// insertTeam inserts the players then the team, returning the inserted player ids even on failure
+func (repo *repository) insertTeam(ctx context.Context, data model.Team) (*model.Team, []interface{}, error) {
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	res := &model.Team{
+		Name:        data.Name,
+		Description: data.Description,
+		Location:    data.Location,
+		Players:     []model.Player{},
+		CreatedAt:   now,
+	}
+
 	body := bson.M{
 		"name":        data.Name,
 		"description": data.Description,
 		"location":    data.Location,
-		"created_at":  time.Now(),
+		"created_at":  now,
 	}
 
+	var playerIDs []interface{}
+
 	if len(data.Players) > 0 {
 		players := []interface{}{}
 
 		for _, p := range data.Players {
-			players = append(players, bson.M{
-				"name":       p.Name,
-				"nickname":   p.Nickname,
-				"position":   p.Position,
-				"created_at": time.Now(),
-			})
+			doc := player.Document(p)
+			doc["created_at"] = now
+			players = append(players, doc)
 		}
 
-		res, err := repo.db.Collection("players").InsertMany(ctx, players)
+		ids, err := repo.writer.insertPlayers(ctx, players)
 		if err != nil {
-			return nil, errors.Wrap(err, op)
+			return nil, nil, err
+		}
+
+		for i, p := range data.Players {
+			oid, _ := ids[i].(primitive.ObjectID)
+			p.ID, p.CreatedAt = oid, now
+			res.Players = append(res.Players, p)
+		}
+
+		playerIDs = ids
+		body["players"] = ids
+	}
+
+	id, err := repo.writer.insertTeam(ctx, body)
+	if err != nil {
+		return nil, playerIDs, err
+	}
+
+	res.ID, _ = id.(primitive.ObjectID)
+
+	return res, playerIDs, nil
+}
+
+// insertTeamWithCompensation inserts a team without transaction, deleting its players on failure
+func (repo *repository) insertTeamWithCompensation(ctx context.Context, data model.Team) (*model.Team, error) {
+
+	res, playerIDs, err := repo.insertTeam(ctx, data)
+	if err == nil {
+		return res, nil
+	}
+
+	if len(playerIDs) > 0 {
+		if delErr := repo.writer.deletePlayers(ctx, playerIDs); delErr != nil {
+			return nil, errors.Wrapf(err, "cannot delete orphaned players: %v", delErr)
 		}
+	}
+
+	return nil, err
+}
+
+// UpdateTeam replaces the fields of a team, the roster is left untouched
+func (repo *repository) UpdateTeam(ctx context.Context, id string, data model.Team) (*model.Team, error) {
+	op := "team.Repository.UpdateTeam"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
 
-		body["players"] = res.InsertedIDs
+	update := bson.M{
+		"$set": bson.M{
+			"name":        data.Name,
+			"description": data.Description,
+			"location":    data.Location,
+		},
 	}
 
-	res, err := repo.db.Collection("teams").InsertOne(ctx, body)
+	res, err := repo.db.Collection("teams").UpdateOne(ctx, bson.M{"_id": oid}, update)
 	if err != nil {
 		return nil, errors.Wrap(err, op)
 	}
 
-	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
-		return repo.GetTeam(ctx, oid.Hex())
+	if res.MatchedCount == 0 {
+		return nil, nil
 	}
 
-	return nil, nil
+	return repo.GetTeam(ctx, id)
+}
+
+// PatchTeam applies a JSON merge patch to a team, null values remove the field
+func (repo *repository) PatchTeam(ctx context.Context, id string, patch map[string]interface{}) (*model.Team, error) {
+	op := "team.Repository.PatchTeam"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	set := bson.M{}
+	unset := bson.M{}
+
+	for k, v := range patch {
+		if v == nil {
+			unset[k] = ""
+		} else {
+			set[k] = v
+		}
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	if len(update) == 0 {
+		return repo.GetTeam(ctx, id)
+	}
+
+	res, err := repo.db.Collection("teams").UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if res.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	return repo.GetTeam(ctx, id)
+}
+
+// DeleteTeam deletes a team and returns the deleted team, the players are kept
+func (repo *repository) DeleteTeam(ctx context.Context, id string) (*model.Team, error) {
+	op := "team.Repository.DeleteTeam"
+
+	data, err := repo.GetTeam(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if data == nil {
+		return nil, nil
+	}
+
+	_, err = repo.db.Collection("teams").DeleteOne(ctx, bson.M{"_id": data.ID})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return data, nil
+}
+
+// AddPlayers attaches existing players to a team, a player can only belong to one team
+func (repo *repository) AddPlayers(ctx context.Context, id string, playerIDs []string) (*model.Team, error) {
+	op := "team.Repository.AddPlayers"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	found, err := repo.db.Collection("teams").CountDocuments(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if found == 0 {
+		return nil, nil
+	}
+
+	oids := []primitive.ObjectID{}
+	seen := map[primitive.ObjectID]bool{}
+
+	for _, pid := range playerIDs {
+		poid, _ := primitive.ObjectIDFromHex(pid)
+		if !seen[poid] {
+			seen[poid] = true
+			oids = append(oids, poid)
+		}
+	}
+
+	count, err := repo.db.Collection("players").CountDocuments(ctx, bson.M{"_id": bson.M{"$in": oids}})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if int(count) != len(oids) {
+		return nil, errors.Wrap(ErrPlayerNotFound, op)
+	}
+
+	count, err = repo.db.Collection("teams").CountDocuments(ctx, bson.M{"_id": bson.M{"$ne": oid}, "players": bson.M{"$in": oids}})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if count > 0 {
+		return nil, errors.Wrap(ErrPlayerHasTeam, op)
+	}
+
+	// The unique index on the rosters rejects a player added to another team in the meantime
+	_, err = repo.db.Collection("teams").UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$addToSet": bson.M{"players": bson.M{"$each": oids}}})
+	if isDuplicateKey(err) {
+		return nil, errors.Wrap(ErrPlayerHasTeam, op)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	return repo.GetTeam(ctx, id)
+}
+
+// RemovePlayer detaches a player from a team, the player itself is kept
+func (repo *repository) RemovePlayer(ctx context.Context, id string, playerID string) (*model.Team, error) {
+	op := "team.Repository.RemovePlayer"
+
+	oid, _ := primitive.ObjectIDFromHex(id)
+	poid, _ := primitive.ObjectIDFromHex(playerID)
+
+	found, err := repo.db.Collection("teams").CountDocuments(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if found == 0 {
+		return nil, nil
+	}
+
+	res, err := repo.db.Collection("teams").UpdateOne(ctx, bson.M{"_id": oid, "players": poid}, bson.M{"$pull": bson.M{"players": poid}})
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if res.MatchedCount == 0 {
+		return nil, errors.Wrap(ErrPlayerNotInTeam, op)
+	}
+
+	return repo.GetTeam(ctx, id)
+}
+
+// TransferPlayer moves a player from one team to another in a transaction when the server supports them
+func (repo *repository) TransferPlayer(ctx context.Context, playerID string, fromID string, toID string) (*model.Team, error) {
+	op := "team.Repository.TransferPlayer"
+
+	poid, _ := primitive.ObjectIDFromHex(playerID)
+	fromOID, _ := primitive.ObjectIDFromHex(fromID)
+	toOID, _ := primitive.ObjectIDFromHex(toID)
+
+	found := false
+
+	err := repo.writer.transaction(ctx, func(ctx context.Context) error {
+		var err error
+		found, _, err = repo.transferPlayer(ctx, poid, fromOID, toOID)
+		return err
+	})
+
+	if errors.Cause(err) == errTransactionUnsupported {
+		found, err = repo.transferPlayerWithCompensation(ctx, poid, fromOID, toOID)
+	}
+
+	if isDuplicateKey(err) {
+		err = ErrPlayerHasTeam
+	}
+
+	if errors.Cause(err) == errTeamDeleted {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	return repo.GetTeam(ctx, toID)
+}
+
+// errTeamDeleted is returned when the destination of a transfer is deleted during the transfer
+var errTeamDeleted = errors.New("team was deleted")
+
+// transferPlayer moves a player and returns whether both teams exist and whether the player was pulled
+func (repo *repository) transferPlayer(ctx context.Context, playerID, fromID, toID primitive.ObjectID) (bool, bool, error) {
+
+	count, err := repo.writer.countTeams(ctx, []primitive.ObjectID{fromID, toID})
+	if err != nil || count < 2 {
+		return false, false, err
+	}
+
+	pulled, err := repo.writer.pullPlayer(ctx, fromID, playerID)
+	if err != nil {
+		return false, false, err
+	}
+
+	if !pulled {
+		return false, false, ErrPlayerNotInTeam
+	}
+
+	added, err := repo.writer.addPlayer(ctx, toID, playerID)
+	if err != nil {
+		return false, true, err
+	}
+
+	if !added {
+		return false, true, errTeamDeleted
+	}
+
+	return true, true, nil
+}
+
+// transferPlayerWithCompensation moves a player without transaction, adding it back to its team on failure
+func (repo *repository) transferPlayerWithCompensation(ctx context.Context, playerID, fromID, toID primitive.ObjectID) (bool, error) {
+
+	found, pulled, err := repo.transferPlayer(ctx, playerID, fromID, toID)
+	if err == nil || !pulled {
+		return found, err
+	}
+
+	if _, addErr := repo.writer.addPlayer(ctx, fromID, playerID); addErr != nil {
+		return false, errors.Wrapf(err, "cannot return the player to its team: %v", addErr)
+	}
+
+	return false, err
+}
+
+// SearchTeam returns the teams matching a text search by relevance
+func (repo *repository) SearchTeam(ctx context.Context, q string, limit int) ([]model.SearchResult, error) {
+	op := "team.Repository.SearchTeam"
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"$text": bson.M{"$search": q}}},
+		{"$addFields": bson.M{"score": bson.M{"$meta": "textScore"}}},
+		{"$sort": bson.M{"score": -1}},
+		{"$limit": limit},
+		lookupPlayers,
+	}
+
+	cur, err := repo.db.Collection("teams").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	var items []struct {
+		model.Team `bson:",inline"`
+		Score      float64 `bson:"score"`
+	}
+
+	if err = cur.All(ctx, &items); err != nil {
+		return nil, errors.Wrap(err, op)
+	}
+
+	res := []model.SearchResult{}
+
+	for _, item := range items {
+		res = append(res, model.SearchResult{Type: model.SearchTypeTeam, Score: item.Score, Data: item.Team})
+	}
+
+	return res, nil
+}
+
+// EnsureIndexes creates the indexes of teams, the rosters must not share players
+func (repo *repository) EnsureIndexes(ctx context.Context) error {
+	op := "team.Repository.EnsureIndexes"
+
+	text := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "name", Value: "text"},
+			{Key: "description", Value: "text"},
+			{Key: "location", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("teams_text").
+			SetDefaultLanguage("none").
+			SetWeights(bson.M{"name": 10, "location": 5, "description": 1}),
+	}
+
+	// A player belongs to at most one team, the empty rosters are not indexed
+	roster := mongo.IndexModel{
+		Keys: bson.D{{Key: "players", Value: 1}},
+		Options: options.Index().
+			SetName("teams_players").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"players": bson.M{"$type": "objectId"}}),
+	}
+
+	_, err := repo.db.Collection("teams").Indexes().CreateMany(ctx, []mongo.IndexModel{text, roster})
+	if err != nil {
+		return errors.Wrap(err, op)
+	}
+
+	return nil
 }
@@ -0,0 +1,341 @@
+package team
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeWriter stores documents in memory and fails at the given step
+type fakeWriter struct {
+	standalone bool
+	failOn     string
+	players    map[interface{}]interface{}
+	teams      map[interface{}]interface{}
+	rosters    map[primitive.ObjectID][]primitive.ObjectID
+	adds       int
+}
+
+func newFakeWriter(standalone bool, failOn string) *fakeWriter {
+	return &fakeWriter{
+		standalone: standalone,
+		failOn:     failOn,
+		players:    map[interface{}]interface{}{},
+		teams:      map[interface{}]interface{}{},
+		rosters:    map[primitive.ObjectID][]primitive.ObjectID{},
+	}
+}
+
+func (w *fakeWriter) transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	if w.standalone {
+		return errTransactionUnsupported
+	}
+
+	players := map[interface{}]interface{}{}
+	for k, v := range w.players {
+		players[k] = v
+	}
+	teams := map[interface{}]interface{}{}
+	for k, v := range w.teams {
+		teams[k] = v
+	}
+	rosters := map[primitive.ObjectID][]primitive.ObjectID{}
+	for k, v := range w.rosters {
+		rosters[k] = append([]primitive.ObjectID{}, v...)
+	}
+
+	// Roll back on failure
+	if err := fn(ctx); err != nil {
+		w.players = players
+		w.teams = teams
+		w.rosters = rosters
+		return err
+	}
+
+	return nil
+}
+
+func (w *fakeWriter) insertPlayers(ctx context.Context, players []interface{}) ([]interface{}, error) {
+
+	if w.failOn == "players" {
+		return nil, errors.New("insert players failed")
+	}
+
+	ids := []interface{}{}
+	for _, p := range players {
+		id := primitive.NewObjectID()
+		w.players[id] = p
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (w *fakeWriter) insertTeam(ctx context.Context, team interface{}) (interface{}, error) {
+
+	if w.failOn == "team" || w.failOn == "delete" {
+		return nil, errors.New("insert team failed")
+	}
+
+	id := primitive.NewObjectID()
+	w.teams[id] = team
+
+	return id, nil
+}
+
+func (w *fakeWriter) deletePlayers(ctx context.Context, ids []interface{}) error {
+
+	if w.failOn == "delete" {
+		return errors.New("delete players failed")
+	}
+
+	for _, id := range ids {
+		delete(w.players, id)
+	}
+
+	return nil
+}
+
+func (w *fakeWriter) countTeams(ctx context.Context, ids []primitive.ObjectID) (int64, error) {
+
+	count := int64(0)
+	for _, id := range ids {
+		if _, ok := w.rosters[id]; ok {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (w *fakeWriter) pullPlayer(ctx context.Context, teamID primitive.ObjectID, playerID primitive.ObjectID) (bool, error) {
+
+	for i, id := range w.rosters[teamID] {
+		if id == playerID {
+			w.rosters[teamID] = append(w.rosters[teamID][:i:i], w.rosters[teamID][i+1:]...)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (w *fakeWriter) addPlayer(ctx context.Context, teamID primitive.ObjectID, playerID primitive.ObjectID) (bool, error) {
+
+	// The first add is the transfer, the next ones compensate it
+	w.adds++
+	if w.failOn == "add" && w.adds == 1 || w.failOn == "compensate" {
+		return false, errors.New("add player failed")
+	}
+
+	roster, ok := w.rosters[teamID]
+	if !ok {
+		return false, nil
+	}
+	w.rosters[teamID] = append(roster, playerID)
+
+	return true, nil
+}
+
+func TestCreateTeam(t *testing.T) {
+
+	mockTeam := model.Team{
+		Name:     "Arsenal",
+		Location: "London",
+		Players: []model.Player{
+			{Name: "Ronaldo", Position: "Captain"},
+			{Name: "Messi", Position: "Forward"},
+		},
+	}
+
+	for _, standalone := range []bool{false, true} {
+
+		name := "Transaction"
+		if standalone {
+			name = "Standalone"
+		}
+
+		t.Run(name+" Created", func(t *testing.T) {
+
+			w := newFakeWriter(standalone, "")
+			repo := &repository{writer: w}
+
+			res, err := repo.CreateTeam(context.Background(), mockTeam)
+
+			// Assertions
+			if assert.NoError(t, err) {
+				assert.False(t, res.ID.IsZero())
+				assert.Equal(t, mockTeam.Name, res.Name)
+				assert.Len(t, res.Players, 2)
+				assert.False(t, res.Players[0].ID.IsZero())
+				assert.Len(t, w.players, 2)
+				assert.Len(t, w.teams, 1)
+			}
+		})
+
+		t.Run(name+" Insert Players Failed", func(t *testing.T) {
+
+			w := newFakeWriter(standalone, "players")
+			repo := &repository{writer: w}
+
+			res, err := repo.CreateTeam(context.Background(), mockTeam)
+
+			// Assertions
+			assert.Error(t, err)
+			assert.Nil(t, res)
+			assert.Empty(t, w.players)
+			assert.Empty(t, w.teams)
+		})
+
+		t.Run(name+" Insert Team Failed", func(t *testing.T) {
+
+			w := newFakeWriter(standalone, "team")
+			repo := &repository{writer: w}
+
+			res, err := repo.CreateTeam(context.Background(), mockTeam)
+
+			// Assertions
+			assert.Error(t, err)
+			assert.Nil(t, res)
+			assert.Empty(t, w.players)
+			assert.Empty(t, w.teams)
+		})
+	}
+
+	t.Run("Standalone Delete Players Failed", func(t *testing.T) {
+
+		w := newFakeWriter(true, "delete")
+		repo := &repository{writer: w}
+
+		res, err := repo.CreateTeam(context.Background(), mockTeam)
+
+		// Assertions
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "delete players failed")
+			assert.Contains(t, err.Error(), "insert team failed")
+		}
+		assert.Nil(t, res)
+		assert.Len(t, w.players, 2)
+	})
+}
+
+func TestRepositoryTransferPlayer(t *testing.T) {
+
+	from := primitive.NewObjectID()
+	to := primitive.NewObjectID()
+	player := primitive.NewObjectID()
+
+	newWriter := func(standalone bool, failOn string) *fakeWriter {
+		w := newFakeWriter(standalone, failOn)
+		w.rosters[from] = []primitive.ObjectID{player}
+		w.rosters[to] = []primitive.ObjectID{}
+		return w
+	}
+
+	for _, standalone := range []bool{false, true} {
+
+		name := "Transaction"
+		if standalone {
+			name = "Standalone"
+		}
+
+		t.Run(name+" Transferred", func(t *testing.T) {
+
+			w := newWriter(standalone, "")
+			repo := &repository{writer: w}
+
+			// The destination team is read from the database once transferred
+			found := false
+			err := repo.writer.transaction(context.Background(), func(ctx context.Context) error {
+				var err error
+				found, _, err = repo.transferPlayer(ctx, player, from, to)
+				return err
+			})
+			if standalone {
+				found, err = repo.transferPlayerWithCompensation(context.Background(), player, from, to)
+			}
+
+			// Assertions
+			if assert.NoError(t, err) {
+				assert.True(t, found)
+				assert.Empty(t, w.rosters[from])
+				assert.Equal(t, []primitive.ObjectID{player}, w.rosters[to])
+			}
+		})
+	}
+
+	t.Run("Transaction Add Player Failed", func(t *testing.T) {
+
+		w := newWriter(false, "add")
+		repo := &repository{writer: w}
+
+		_, err := repo.TransferPlayer(context.Background(), player.Hex(), from.Hex(), to.Hex())
+
+		// Assertions
+		assert.Error(t, err)
+		assert.Equal(t, []primitive.ObjectID{player}, w.rosters[from])
+		assert.Empty(t, w.rosters[to])
+	})
+
+	t.Run("Standalone Add Player Failed", func(t *testing.T) {
+
+		w := newWriter(true, "add")
+		repo := &repository{writer: w}
+
+		_, err := repo.TransferPlayer(context.Background(), player.Hex(), from.Hex(), to.Hex())
+
+		// Assertions
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "add player failed")
+		}
+		assert.Equal(t, []primitive.ObjectID{player}, w.rosters[from])
+		assert.Empty(t, w.rosters[to])
+	})
+
+	t.Run("Standalone Compensation Failed", func(t *testing.T) {
+
+		w := newWriter(true, "compensate")
+		repo := &repository{writer: w}
+
+		_, err := repo.TransferPlayer(context.Background(), player.Hex(), from.Hex(), to.Hex())
+
+		// Assertions
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "cannot return the player to its team")
+		}
+		assert.Empty(t, w.rosters[from])
+	})
+
+	t.Run("Standalone Not In Team", func(t *testing.T) {
+
+		w := newWriter(true, "")
+		w.rosters[from] = []primitive.ObjectID{}
+		repo := &repository{writer: w}
+
+		res, err := repo.TransferPlayer(context.Background(), player.Hex(), from.Hex(), to.Hex())
+
+		// Assertions
+		assert.Equal(t, ErrPlayerNotInTeam, errors.Cause(err))
+		assert.Nil(t, res)
+		assert.Equal(t, 0, w.adds)
+	})
+
+	t.Run("Standalone Team Not Found", func(t *testing.T) {
+
+		w := newWriter(true, "")
+		delete(w.rosters, to)
+		repo := &repository{writer: w}
+
+		res, err := repo.TransferPlayer(context.Background(), player.Hex(), from.Hex(), to.Hex())
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Nil(t, res)
+		assert.Equal(t, []primitive.ObjectID{player}, w.rosters[from])
+	})
+}
@@ -1,9 +1,11 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/yezarela/go-soccer/pkg/query"
 )
 
 // ResponseMeta represents api response meta
@@ -15,8 +17,9 @@ type ResponseMeta struct {
 
 // Response represents api response
 type Response struct {
-	Meta ResponseMeta `json:"meta"`
-	Data interface{}  `json:"data"`
+	Meta       ResponseMeta `json:"meta"`
+	Data       interface{}  `json:"data"`
+	Pagination *query.Page  `json:"pagination,omitempty"`
 }
 
 // ResponseOK returns 200
@@ -32,6 +35,29 @@ func ResponseOK(c echo.Context, data interface{}) error {
 	return c.JSON(http.StatusOK, body)
 }
 
+// ResponsePage returns 200 with the pagination of a list and its next Link header
+func ResponsePage(c echo.Context, data interface{}, page *query.Page) error {
+
+	if page != nil && page.HasMore {
+		u := *c.Request().URL
+		q := u.Query()
+		q.Set("cursor", page.NextCursor)
+		u.RawQuery = q.Encode()
+
+		link := fmt.Sprintf("<%s://%s%s>; rel=\"next\"", c.Scheme(), c.Request().Host, u.RequestURI())
+		c.Response().Header().Set("Link", link)
+	}
+
+	body := &Response{
+		Meta: ResponseMeta{
+			Code: http.StatusOK,
+		},
+		Data:       data,
+		Pagination: page,
+	}
+
+	return c.JSON(http.StatusOK, body)
+}
 
 // ResponseCreated returns 201
 func ResponseCreated(c echo.Context, data interface{}) error {
@@ -74,6 +100,11 @@ func ResponseNotFound(c echo.Context, msg string) error {
 	return responseError(c, http.StatusNotFound, "Resource Not Found", msg)
 }
 
+// ResponseConflict returns 409
+func ResponseConflict(c echo.Context, msg string) error {
+	return responseError(c, http.StatusConflict, "Conflict", msg)
+}
+
 // ResponseError returns 500
 func ResponseError(c echo.Context, err error) error {
 	return responseError(c, http.StatusInternalServerError, "Internal Server Error", err.Error())
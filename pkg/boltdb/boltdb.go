@@ -0,0 +1,122 @@
+package boltdb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yezarela/go-soccer/model"
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// PlayersBucket holds the players by id
+	PlayersBucket = []byte("players")
+	// TeamsBucket holds the teams by id
+	TeamsBucket = []byte("teams")
+	// MatchesBucket holds the matches by id
+	MatchesBucket = []byte("matches")
+	// CompetitionsBucket holds the competitions by id
+	CompetitionsBucket = []byte("competitions")
+
+	metaBucket = []byte("meta")
+	versionKey = []byte("schema_version")
+)
+
+// Team represents a team as stored, with the ids of its players
+type Team struct {
+	ID          primitive.ObjectID   `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Location    string               `json:"location"`
+	Players     []primitive.ObjectID `json:"players"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
+
+// Player represents a player as stored, without the methods of the model so its age is not encoded
+type Player model.Player
+
+// migrations upgrade the schema of the database, they must only be appended
+var migrations = []func(tx *bolt.Tx) error{
+	// 1: create the buckets of players and teams
+	func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{PlayersBucket, TeamsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	// 2: create the bucket of matches
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(MatchesBucket)
+		return err
+	},
+	// 3: create the bucket of competitions
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(CompetitionsBucket)
+		return err
+	},
+}
+
+// Migrate applies the migrations which are not applied yet in a single transaction
+func Migrate(db *bolt.DB) error {
+	op := "boltdb.Migrate"
+
+	err := db.Update(func(tx *bolt.Tx) error {
+
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+
+		version := 0
+		if v := meta.Get(versionKey); v != nil {
+			version = int(binary.BigEndian.Uint64(v))
+		}
+
+		if version > len(migrations) {
+			return errors.Errorf("schema version %d is newer than the app", version)
+		}
+
+		for _, migrate := range migrations[version:] {
+			if err := migrate(tx); err != nil {
+				return err
+			}
+		}
+
+		v := make([]byte, 8)
+		binary.BigEndian.PutUint64(v, uint64(len(migrations)))
+
+		return meta.Put(versionKey, v)
+	})
+	if err != nil {
+		return errors.Wrap(err, op)
+	}
+
+	return nil
+}
+
+// Get decodes the record of an id, it returns false if the record does not exist
+func Get(b *bolt.Bucket, id primitive.ObjectID, v interface{}) (bool, error) {
+
+	data := b.Get(id[:])
+	if data == nil {
+		return false, nil
+	}
+
+	return true, json.Unmarshal(data, v)
+}
+
+// Put encodes the record of an id
+func Put(b *bolt.Bucket, id primitive.ObjectID, v interface{}) error {
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(id[:], data)
+}
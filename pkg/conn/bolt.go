@@ -0,0 +1,31 @@
+package conn
+
+import (
+	"errors"
+	"time"
+
+	"github.com/yezarela/go-soccer/pkg/boltdb"
+	bolt "go.etcd.io/bbolt"
+)
+
+// NewBoltDBConnection opens a boltdb file, creating it if needed, and migrates its schema
+func NewBoltDBConnection(path string) (*bolt.DB, error) {
+
+	if len(path) <= 0 {
+		return nil, errors.New("Cannot load BOLTDB_PATH from env, please provide one")
+	}
+
+	// Only one process can open the file
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = boltdb.Migrate(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
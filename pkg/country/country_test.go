@@ -0,0 +1,19 @@
+package country
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+
+	assert.True(t, Valid("IT"))
+	assert.True(t, Valid("GB-SCT"))
+	assert.True(t, Valid(Normalize(" br ")))
+
+	assert.False(t, Valid("it"))
+	assert.False(t, Valid("ITA"))
+	assert.False(t, Valid("XX"))
+	assert.False(t, Valid(""))
+}
@@ -0,0 +1,124 @@
+// Package elo rates the strength of teams from the results of their matches
+package elo
+
+import (
+	"math"
+	"sort"
+
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/table"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Config represents the parameters of the ratings
+type Config struct {
+	// Initial is the rating of a team before its first match
+	Initial float64
+	// K is the number of points at stake in a match before the goal margin multiplier
+	K float64
+	// HomeAdvantage is the number of points added to the home team to compute its expected result
+	HomeAdvantage float64
+}
+
+// Default is the configuration of the ratings of club football
+var Default = Config{Initial: 1500, K: 20, HomeAdvantage: 100}
+
+// Expected returns the expected result of a team against an opponent, between 0 and 1
+func Expected(rating, opponent float64) float64 {
+	return 1 / (1 + math.Pow(10, (opponent-rating)/400))
+}
+
+// Multiplier returns the multiplier of the points at stake for a goal margin
+func Multiplier(margin int) float64 {
+
+	if margin < 0 {
+		margin = -margin
+	}
+
+	switch {
+	case margin <= 1:
+		return 1
+	case margin == 2:
+		return 1.5
+	default:
+		return (11 + float64(margin)) / 8
+	}
+}
+
+// score returns the actual result of a team, a match decided by penalties is a draw
+func score(result string) float64 {
+	switch result {
+	case model.ResultWin:
+		return 1
+	case model.ResultDraw:
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+// round rounds a rating to two decimals
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// Rate returns the ratings of the teams which played the finished matches by team id
+func Rate(matches []model.Match, cfg Config) map[primitive.ObjectID]*model.Rating {
+
+	sorted := []model.Match{}
+	for _, m := range matches {
+		if m.Status == model.MatchStatusFinished {
+			sorted = append(sorted, m)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Kickoff.Before(sorted[j].Kickoff)
+	})
+
+	// The ratings are rounded once computed, the points are exchanged at full precision
+	points := map[primitive.ObjectID]float64{}
+	res := map[primitive.ObjectID]*model.Rating{}
+
+	rating := func(id primitive.ObjectID) float64 {
+		if _, ok := res[id]; !ok {
+			points[id] = cfg.Initial
+			res[id] = &model.Rating{TeamID: id, History: []model.RatingChange{}}
+		}
+		return points[id]
+	}
+
+	for _, m := range sorted {
+		home, away := rating(m.HomeTeamID), rating(m.AwayTeamID)
+
+		r := table.Result(m, m.HomeTeamID)
+		change := cfg.K * Multiplier(r.GoalsFor-r.GoalsAgainst) * (score(r.Result) - Expected(home+cfg.HomeAdvantage, away))
+
+		sides := []struct {
+			id     primitive.ObjectID
+			before float64
+			change float64
+		}{
+			{m.HomeTeamID, home, change},
+			{m.AwayTeamID, away, -change},
+		}
+
+		for _, side := range sides {
+			points[side.id] = side.before + side.change
+
+			t := res[side.id]
+			t.Played++
+			t.History = append(t.History, model.RatingChange{
+				TeamResult: table.Result(m, side.id),
+				Before:     round(side.before),
+				After:      round(points[side.id]),
+				Change:     round(side.change),
+			})
+		}
+	}
+
+	for id, t := range res {
+		t.Rating = round(points[id])
+	}
+
+	return res
+}
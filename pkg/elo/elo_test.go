@@ -0,0 +1,90 @@
+package elo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestExpected(t *testing.T) {
+
+	assert.Equal(t, 0.5, Expected(1500, 1500))
+	assert.InDelta(t, 0.64, Expected(1600, 1500), 0.001)
+	assert.InDelta(t, 1, Expected(1600, 1500)+Expected(1500, 1600), 1e-9)
+}
+
+func TestMultiplier(t *testing.T) {
+
+	assert.Equal(t, 1.0, Multiplier(0))
+	assert.Equal(t, 1.0, Multiplier(-1))
+	assert.Equal(t, 1.5, Multiplier(2))
+	assert.Equal(t, 1.75, Multiplier(3))
+	assert.Equal(t, 2.0, Multiplier(-5))
+}
+
+func TestRate(t *testing.T) {
+
+	milan := primitive.NewObjectID()
+	inter := primitive.NewObjectID()
+	juve := primitive.NewObjectID()
+
+	matches := []model.Match{}
+	play := func(home, away primitive.ObjectID, homeGoals, awayGoals int) model.Match {
+		m := model.Match{
+			ID:         primitive.NewObjectID(),
+			HomeTeamID: home,
+			AwayTeamID: away,
+			Kickoff:    time.Date(2020, 10, len(matches)+1, 15, 0, 0, 0, time.UTC),
+			Status:     model.MatchStatusFinished,
+			Score:      model.Score{Home: homeGoals, Away: awayGoals},
+		}
+		matches = append(matches, m)
+		return m
+	}
+
+	// A home win between equal teams is worth less than the full stake
+	first := play(milan, inter, 1, 0)
+	// A draw at home loses points
+	play(inter, juve, 0, 0)
+	// A big away win is worth more
+	last := play(milan, juve, 0, 3)
+
+	scheduled := play(juve, inter, 0, 0)
+	scheduled.Status = model.MatchStatusScheduled
+	matches[len(matches)-1] = scheduled
+
+	// The matches are read by kickoff whatever their order
+	res := Rate([]model.Match{last, matches[1], scheduled, first}, Default)
+
+	if assert.Len(t, res, 3) {
+		assert.Equal(t, 2, res[milan].Played)
+		assert.Equal(t, 2, res[inter].Played)
+		assert.Equal(t, 2, res[juve].Played)
+
+		if assert.Len(t, res[milan].History, 2) {
+			h := res[milan].History[0]
+			assert.Equal(t, first.ID, h.MatchID)
+			assert.Equal(t, inter, h.OpponentID)
+			assert.True(t, h.Home)
+			assert.Equal(t, model.ResultWin, h.Result)
+			assert.Equal(t, 1500.0, h.Before)
+			assert.Equal(t, 7.2, h.Change)
+			assert.Equal(t, 1507.2, h.After)
+
+			assert.Equal(t, 1507.2, res[milan].History[1].Before)
+			assert.Equal(t, res[milan].Rating, res[milan].History[1].After)
+		}
+
+		assert.Equal(t, -7.2, res[inter].History[0].Change)
+		assert.Equal(t, -2.61, res[inter].History[1].Change)
+
+		// The points are exchanged, the average rating stays the same
+		assert.InDelta(t, 4500, res[milan].Rating+res[inter].Rating+res[juve].Rating, 0.02)
+		assert.True(t, res[juve].Rating > res[milan].Rating)
+	}
+
+	assert.Empty(t, Rate(nil, Default))
+}
@@ -0,0 +1,65 @@
+// Package formation maps the formations of a team to the positions of their slots
+package formation
+
+import (
+	"sort"
+
+	"github.com/yezarela/go-soccer/model"
+)
+
+const (
+	gk  = model.PositionGoalkeeper
+	cb  = model.PositionCentreBack
+	lb  = model.PositionLeftBack
+	rb  = model.PositionRightBack
+	lwb = model.PositionLeftWingBack
+	rwb = model.PositionRightWingBack
+	dm  = model.PositionDefensiveMidfielder
+	cm  = model.PositionCentralMidfielder
+	lm  = model.PositionLeftMidfielder
+	rm  = model.PositionRightMidfielder
+	am  = model.PositionAttackingMidfielder
+	lw  = model.PositionLeftWinger
+	rw  = model.PositionRightWinger
+	cf  = model.PositionCentreForward
+	st  = model.PositionStriker
+)
+
+// slots are the positions of the starters of the formations, from the goal to the attack
+var slots = map[string][]string{
+	"4-4-2":   {gk, rb, cb, cb, lb, rm, cm, cm, lm, st, st},
+	"4-3-3":   {gk, rb, cb, cb, lb, cm, dm, cm, rw, st, lw},
+	"4-2-3-1": {gk, rb, cb, cb, lb, dm, dm, rw, am, lw, st},
+	"4-1-4-1": {gk, rb, cb, cb, lb, dm, rm, cm, cm, lm, st},
+	"4-4-1-1": {gk, rb, cb, cb, lb, rm, cm, cm, lm, cf, st},
+	"4-3-1-2": {gk, rb, cb, cb, lb, cm, dm, cm, am, st, st},
+	"4-5-1":   {gk, rb, cb, cb, lb, rm, cm, am, cm, lm, st},
+	"3-5-2":   {gk, cb, cb, cb, rwb, cm, dm, cm, lwb, st, st},
+	"3-4-3":   {gk, cb, cb, cb, rm, cm, cm, lm, rw, st, lw},
+	"3-4-2-1": {gk, cb, cb, cb, rwb, cm, cm, lwb, am, am, st},
+	"5-3-2":   {gk, rwb, cb, cb, cb, lwb, cm, cm, cm, st, st},
+	"5-4-1":   {gk, rwb, cb, cb, cb, lwb, rm, cm, cm, lm, st},
+}
+
+// Slots returns the positions of the starters of a formation and whether the formation is known
+func Slots(name string) ([]string, bool) {
+
+	s, ok := slots[name]
+	if !ok {
+		return nil, false
+	}
+
+	return append([]string{}, s...), true
+}
+
+// All returns the names of the known formations
+func All() []string {
+
+	res := []string{}
+	for name := range slots {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+
+	return res
+}
@@ -0,0 +1,46 @@
+package formation
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/position"
+)
+
+func TestSlots(t *testing.T) {
+
+	// Every formation has a goalkeeper then its outfield lines, as many players as its name says
+	for _, name := range All() {
+		s, ok := Slots(name)
+		assert.True(t, ok, name)
+
+		if assert.Len(t, s, model.Starters, name) {
+			assert.Equal(t, model.PositionGoalkeeper, s[0], name)
+		}
+
+		outfield := 0
+		for _, n := range strings.Split(name, "-") {
+			v, err := strconv.Atoi(n)
+			assert.NoError(t, err, name)
+			outfield += v
+		}
+		assert.Equal(t, model.Starters-1, outfield, name)
+
+		for _, code := range s[1:] {
+			assert.True(t, position.Valid(code), name)
+			assert.NotEqual(t, model.PositionGoalkeeper, code, name)
+		}
+	}
+
+	// The slots are copied
+	s, _ := Slots("4-3-3")
+	s[0] = model.PositionStriker
+	s, _ = Slots("4-3-3")
+	assert.Equal(t, model.PositionGoalkeeper, s[0])
+
+	_, ok := Slots("4-3-2")
+	assert.False(t, ok)
+}
@@ -0,0 +1,349 @@
+// Package groups computes the tables of a group stage and draws the knockout of its qualifiers
+package groups
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/knockout"
+	"github.com/yezarela/go-soccer/pkg/table"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Split returns the matches of the group stage and the ones of the knockout
+func Split(rules model.GroupRules, matches []model.Match) ([]model.Match, []model.Match) {
+
+	groupOf := map[primitive.ObjectID]int{}
+	for i, g := range rules.Groups {
+		for _, id := range g.TeamIDs {
+			groupOf[id] = i + 1
+		}
+	}
+
+	sorted := append([]model.Match{}, matches...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Kickoff.Before(sorted[j].Kickoff)
+	})
+
+	// The first legs between two teams of a group are group matches, the next ones are knockout ties
+	var group, ko []model.Match
+	played := map[[2]primitive.ObjectID]int{}
+
+	for _, m := range sorted {
+		home, away := groupOf[m.HomeTeamID], groupOf[m.AwayTeamID]
+		if home == 0 || home != away || m.Status == model.MatchStatusCancelled {
+			ko = append(ko, m)
+			continue
+		}
+
+		pair := [2]primitive.ObjectID{m.HomeTeamID, m.AwayTeamID}
+		if m.HomeTeamID.Hex() > m.AwayTeamID.Hex() {
+			pair = [2]primitive.ObjectID{m.AwayTeamID, m.HomeTeamID}
+		}
+
+		if played[pair] < rules.Legs {
+			played[pair]++
+			group = append(group, m)
+		} else {
+			ko = append(ko, m)
+		}
+	}
+
+	return group, ko
+}
+
+// Pending returns whether two teams of the same group still have group matches to play against each other
+func Pending(rules model.GroupRules, matches []model.Match, a, b primitive.ObjectID) bool {
+
+	for _, g := range rules.Groups {
+		in := map[primitive.ObjectID]bool{}
+		for _, id := range g.TeamIDs {
+			in[id] = true
+		}
+		if !in[a] || !in[b] {
+			continue
+		}
+
+		group, _ := Split(rules, matches)
+
+		played := 0
+		for _, m := range group {
+			if (m.HomeTeamID == a && m.AwayTeamID == b) || (m.HomeTeamID == b && m.AwayTeamID == a) {
+				played++
+			}
+		}
+
+		return played < rules.Legs
+	}
+
+	return false
+}
+
+// Standings returns the tables of the groups of a competition and the ranking of the best placed teams
+func Standings(comp model.Competition, matches []model.Match) model.GroupStandings {
+
+	rules := *comp.GroupStage
+	group, _ := Split(rules, matches)
+
+	res := model.GroupStandings{
+		Groups:     []model.GroupTable{},
+		BestPlaced: []model.Standing{},
+	}
+
+	for _, g := range rules.Groups {
+		t := model.GroupTable{
+			Name:      g.Name,
+			Complete:  complete(g, group, rules.Legs),
+			Standings: table.Compute(g.TeamIDs, group, comp.Points, comp.Tiebreakers),
+		}
+		for i := range t.Standings {
+			t.Standings[i].Group = g.Name
+		}
+		res.Groups = append(res.Groups, t)
+
+		if len(t.Standings) > rules.Advance {
+			res.BestPlaced = append(res.BestPlaced, t.Standings[rules.Advance])
+		}
+	}
+
+	sort.SliceStable(res.BestPlaced, func(i, j int) bool {
+		a, b := res.BestPlaced[i], res.BestPlaced[j]
+		switch {
+		case a.Points != b.Points:
+			return a.Points > b.Points
+		case a.GoalDifference != b.GoalDifference:
+			return a.GoalDifference > b.GoalDifference
+		case a.GoalsFor != b.GoalsFor:
+			return a.GoalsFor > b.GoalsFor
+		}
+		return a.FairPlay > b.FairPlay
+	})
+
+	for i := range res.BestPlaced {
+		res.BestPlaced[i].Position = i + 1
+	}
+
+	return res
+}
+
+// Bracket returns the final of the knockout of the qualifiers of a group stage, nil without qualifiers
+func Bracket(comp model.Competition, matches []model.Match) *model.Tie {
+
+	rules := *comp.GroupStage
+	_, ko := Split(rules, matches)
+
+	return knockout.BuildFrom(Draw(rules, Standings(comp, matches)), ko, knockout.Rules(comp))
+}
+
+// Draw returns the slots of the first round of the knockout of a group stage, paired two by two
+func Draw(rules model.GroupRules, standings model.GroupStandings) []model.TieSlot {
+
+	complete := true
+	for _, t := range standings.Groups {
+		complete = complete && t.Complete
+	}
+
+	slot := func(g int, place int) model.TieSlot {
+		t := standings.Groups[g]
+		s := model.TieSlot{Label: fmt.Sprintf("%s%d", t.Name, place)}
+		if t.Complete && place <= len(t.Standings) {
+			id := t.Standings[place-1].TeamID
+			s.TeamID = &id
+		}
+		return s
+	}
+
+	n := len(standings.Groups)
+
+	// Groups paired in order, A1 against B2 and B1 against A2 in different halves so they only meet again in the final
+	if rules.Advance == 2 && rules.BestPlaced == 0 && n%2 == 0 && knockout.Size(2*n) == 2*n {
+		var top, bottom []model.TieSlot
+		for g := 0; g < n; g += 2 {
+			top = append(top, slot(g, 1), slot(g+1, 2))
+			bottom = append(bottom, slot(g+1, 1), slot(g, 2))
+		}
+		return append(top, bottom...)
+	}
+
+	// Otherwise the qualifiers are seeded by place then by group, the best placed teams last
+	qualifiers := []model.TieSlot{}
+	groups, places := []int{}, []int{}
+
+	for place := 1; place <= rules.Advance; place++ {
+		for g := range standings.Groups {
+			qualifiers = append(qualifiers, slot(g, place))
+			groups = append(groups, g)
+			places = append(places, place)
+		}
+	}
+
+	for i := 0; i < rules.BestPlaced; i++ {
+		s := model.TieSlot{Label: fmt.Sprintf("%s #%d", ordinal(rules.Advance+1), i+1)}
+		g := -1
+		if complete && i < len(standings.BestPlaced) {
+			id := standings.BestPlaced[i].TeamID
+			s.TeamID = &id
+			for j, t := range standings.Groups {
+				if t.Name == standings.BestPlaced[i].Group {
+					g = j
+				}
+			}
+		}
+		qualifiers = append(qualifiers, s)
+		groups = append(groups, g)
+		places = append(places, rules.Advance+1)
+	}
+
+	slots := knockout.Place(qualifiers)
+	spread(slots, groups, places)
+
+	// A tie of two byes would never be decided, the bracket would stop at its round
+	for i := 0; i+1 < len(slots); i += 2 {
+		if slots[i].Seed == 0 && slots[i+1].Seed == 0 {
+			panic("groups: the draw paired two byes")
+		}
+	}
+
+	return slots
+}
+
+// spread moves the qualifiers of a group to different halves of the bracket so they meet as late as possible
+func spread(slots []model.TieSlot, groups, places []int) {
+
+	if len(slots) < 2 {
+		return
+	}
+
+	left, right := slots[:len(slots)/2], slots[len(slots)/2:]
+	for balance(left, right, groups, places) {
+	}
+
+	spread(left, groups, places)
+	spread(right, groups, places)
+}
+
+// balance swaps slots between the halves when it evens the qualifiers of the groups, it returns whether it did
+func balance(left, right []model.TieSlot, groups, places []int) bool {
+
+	group := func(s model.TieSlot) int {
+		if s.Seed == 0 {
+			return -1
+		}
+		return groups[s.Seed-1]
+	}
+
+	// The number of qualifiers of each group in the left half minus the right half
+	diff := map[int]int{}
+	for _, s := range left {
+		if g := group(s); g >= 0 {
+			diff[g]++
+		}
+	}
+	for _, s := range right {
+		if g := group(s); g >= 0 {
+			diff[g]--
+		}
+	}
+
+	// evens returns whether swapping the slots of the left half with those of the right half evens the groups
+	evens := func(out, in []model.TieSlot) bool {
+		moves := map[int]int{}
+		for _, s := range out {
+			if g := group(s); g >= 0 {
+				moves[g] -= 2
+			}
+		}
+		for _, s := range in {
+			if g := group(s); g >= 0 {
+				moves[g] += 2
+			}
+		}
+		change := 0
+		for g, m := range moves {
+			change += abs(diff[g]+m) - abs(diff[g])
+		}
+		return change < 0
+	}
+
+	place := func(s model.TieSlot) int {
+		if s.Seed == 0 {
+			return 0
+		}
+		return places[s.Seed-1]
+	}
+
+	// Qualifiers of the same place are swapped first, the lowest place first
+	for p := places[len(places)-1]; p > 0; p-- {
+		for i := range left {
+			for j := range right {
+				if place(left[i]) == p && place(right[j]) == p && evens(left[i:i+1], right[j:j+1]) {
+					left[i], right[j] = right[j], left[i]
+					return true
+				}
+			}
+		}
+	}
+
+	// A half of a single slot is a tie of its own
+	if len(left) < 2 {
+		return false
+	}
+
+	for i := 0; i < len(left); i += 2 {
+		for j := 0; j < len(right); j += 2 {
+			// Whole ties are swapped otherwise, so a bye always faces a qualifier
+			if evens(left[i:i+2], right[j:j+2]) {
+				left[i], left[i+1], right[j], right[j+1] = right[j], right[j+1], left[i], left[i+1]
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// complete returns whether all the matches of a group are finished
+func complete(g model.Group, matches []model.Match, legs int) bool {
+
+	in := map[primitive.ObjectID]bool{}
+	for _, id := range g.TeamIDs {
+		in[id] = true
+	}
+
+	finished := 0
+	for _, m := range matches {
+		if m.Status == model.MatchStatusFinished && in[m.HomeTeamID] && in[m.AwayTeamID] {
+			finished++
+		}
+	}
+
+	n := len(g.TeamIDs)
+
+	return finished >= n*(n-1)/2*legs
+}
+
+// ordinal returns the ordinal of a place, e.g. 3rd
+func ordinal(n int) string {
+
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+
+	return fmt.Sprintf("%d%s", n, suffix)
+}
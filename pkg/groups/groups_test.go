@@ -0,0 +1,289 @@
+package groups
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/table"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func teams(n int) []primitive.ObjectID {
+
+	res := []primitive.ObjectID{}
+	for i := 0; i < n; i++ {
+		res = append(res, primitive.NewObjectID())
+	}
+
+	return res
+}
+
+func match(home, away primitive.ObjectID, day, homeGoals, awayGoals int) model.Match {
+	return model.Match{
+		ID:         primitive.NewObjectID(),
+		HomeTeamID: home,
+		AwayTeamID: away,
+		Kickoff:    time.Date(2021, 6, day, 20, 0, 0, 0, time.UTC),
+		Status:     model.MatchStatusFinished,
+		Score:      model.Score{Home: homeGoals, Away: awayGoals},
+	}
+}
+
+// competition returns a competition of groups of n teams, the first team of each group is the best
+func competition(groups, n int, advance, bestPlaced int) (model.Competition, []model.Match) {
+
+	comp := model.Competition{
+		Type:        model.CompetitionTypeGroups,
+		Points:      table.DefaultPoints,
+		Tiebreakers: table.DefaultTiebreakers,
+		GroupStage:  &model.GroupRules{Legs: 1, Advance: advance, BestPlaced: bestPlaced},
+	}
+
+	matches := []model.Match{}
+
+	for g := 0; g < groups; g++ {
+		ids := teams(n)
+		comp.TeamIDs = append(comp.TeamIDs, ids...)
+		comp.GroupStage.Groups = append(comp.GroupStage.Groups, model.Group{Name: string(rune('A' + g)), TeamIDs: ids})
+
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				// The better team wins by more goals in the later groups
+				matches = append(matches, match(ids[i], ids[j], 1+i, 1+g, 0))
+			}
+		}
+	}
+
+	return comp, matches
+}
+
+func TestSplit(t *testing.T) {
+
+	ids := teams(3)
+	rules := model.GroupRules{Groups: []model.Group{{Name: "A", TeamIDs: ids[:2]}, {Name: "B", TeamIDs: ids[2:]}}, Legs: 1}
+
+	cancelled := match(ids[0], ids[1], 1, 0, 0)
+	cancelled.Status = model.MatchStatusCancelled
+
+	first := match(ids[0], ids[1], 2, 1, 0)
+	again := match(ids[1], ids[0], 9, 1, 0)
+	cross := match(ids[0], ids[2], 8, 1, 0)
+
+	group, ko := Split(rules, []model.Match{again, cross, first, cancelled})
+
+	assert.Equal(t, []model.Match{first}, group)
+	assert.Equal(t, []model.Match{cancelled, cross, again}, ko)
+}
+
+func TestPending(t *testing.T) {
+
+	comp, matches := competition(2, 3, 1, 0)
+	a, b := comp.GroupStage.Groups[0].TeamIDs, comp.GroupStage.Groups[1].TeamIDs
+
+	assert.True(t, Pending(*comp.GroupStage, nil, a[0], a[1]))
+	assert.False(t, Pending(*comp.GroupStage, matches, a[0], a[1]))
+	assert.False(t, Pending(*comp.GroupStage, nil, a[0], b[0]))
+
+	// A second leg is still to be played
+	comp.GroupStage.Legs = 2
+	assert.True(t, Pending(*comp.GroupStage, matches, a[1], a[0]))
+}
+
+func TestStandings(t *testing.T) {
+
+	comp, matches := competition(3, 4, 2, 2)
+
+	res := Standings(comp, matches)
+
+	if assert.Len(t, res.Groups, 3) {
+		a := res.Groups[0]
+		assert.Equal(t, "A", a.Name)
+		assert.True(t, a.Complete)
+		assert.Equal(t, comp.GroupStage.Groups[0].TeamIDs[0], a.Standings[0].TeamID)
+		assert.Equal(t, "A", a.Standings[0].Group)
+		assert.Equal(t, 9, a.Standings[0].Points)
+	}
+
+	// The third of the group with the narrowest defeats ranks first
+	if assert.Len(t, res.BestPlaced, 3) {
+		assert.Equal(t, comp.GroupStage.Groups[0].TeamIDs[2], res.BestPlaced[0].TeamID)
+		assert.Equal(t, 1, res.BestPlaced[0].Position)
+		assert.Equal(t, "A", res.BestPlaced[0].Group)
+		assert.Equal(t, comp.GroupStage.Groups[2].TeamIDs[2], res.BestPlaced[2].TeamID)
+	}
+
+	// A group is not complete until all its matches are finished
+	res = Standings(comp, matches[1:])
+	assert.False(t, res.Groups[0].Complete)
+	assert.True(t, res.Groups[1].Complete)
+}
+
+func TestDraw(t *testing.T) {
+
+	t.Run("Cross", func(t *testing.T) {
+
+		comp, matches := competition(4, 4, 2, 0)
+
+		slots := Draw(*comp.GroupStage, Standings(comp, matches))
+
+		labels := []string{}
+		for _, s := range slots {
+			labels = append(labels, s.Label)
+		}
+
+		assert.Equal(t, []string{"A1", "B2", "C1", "D2", "B1", "A2", "D1", "C2"}, labels)
+		assert.Equal(t, comp.GroupStage.Groups[1].TeamIDs[1], *slots[1].TeamID)
+	})
+
+	t.Run("Best Placed", func(t *testing.T) {
+
+		comp, matches := competition(3, 4, 1, 1)
+
+		slots := Draw(*comp.GroupStage, Standings(comp, matches))
+
+		labels := []string{}
+		for _, s := range slots {
+			labels = append(labels, s.Label)
+		}
+
+		// The best placed team plays the best group winner
+		assert.Equal(t, []string{"A1", "2nd #1", "B1", "C1"}, labels)
+		assert.Equal(t, comp.GroupStage.Groups[2].TeamIDs[1], *slots[1].TeamID)
+	})
+
+	t.Run("Odd Groups", func(t *testing.T) {
+
+		comp, matches := competition(3, 4, 2, 0)
+
+		slots := Draw(*comp.GroupStage, Standings(comp, matches))
+
+		labels := []string{}
+		for _, s := range slots {
+			labels = append(labels, s.Label)
+		}
+
+		// The best group winners get the byes, the teams of a group are in different halves
+		assert.Equal(t, []string{"A1", "", "C2", "B2", "B1", "", "C1", "A2"}, labels)
+		assert.Equal(t, comp.GroupStage.Groups[0].TeamIDs[1], *slots[7].TeamID)
+	})
+
+	t.Run("Best Placed Same Group", func(t *testing.T) {
+
+		comp, matches := competition(3, 4, 1, 1)
+
+		// The runner-up of the first group becomes the best placed team
+		a := comp.GroupStage.Groups[0].TeamIDs
+		for i := range matches {
+			if matches[i].HomeTeamID == a[1] {
+				matches[i].Score.Home = 9
+			}
+		}
+
+		slots := Draw(*comp.GroupStage, Standings(comp, matches))
+
+		labels := []string{}
+		for _, s := range slots {
+			labels = append(labels, s.Label)
+		}
+
+		// It cannot play its group winner before the final
+		assert.Equal(t, []string{"B1", "2nd #1", "A1", "C1"}, labels)
+		assert.Equal(t, a[1], *slots[1].TeamID)
+		assert.Equal(t, 1, slots[2].Seed)
+	})
+
+	t.Run("Byes", func(t *testing.T) {
+
+		formats := []struct {
+			groups, advance, bestPlaced int
+		}{
+			{4, 4, 1}, {4, 4, 3}, {5, 3, 3}, {5, 4, 2}, {5, 4, 3}, {5, 4, 4}, {5, 4, 5}, {8, 3, 3},
+		}
+
+		for _, f := range formats {
+			comp, matches := competition(f.groups, f.advance+1, f.advance, f.bestPlaced)
+
+			slots := Draw(*comp.GroupStage, Standings(comp, matches))
+
+			// place returns the place of the qualifier of a slot in its group, 0 for a bye
+			place := func(s model.TieSlot) int {
+				if s.Seed == 0 {
+					return 0
+				}
+				if strings.Contains(s.Label, "#") {
+					return f.advance + 1
+				}
+				p, _ := strconv.Atoi(s.Label[1:])
+				return p
+			}
+
+			// Every bye faces one of the best placed qualifiers
+			worst, best := 0, f.advance+1
+			for i := 0; i < len(slots); i += 2 {
+				a, b := place(slots[i]), place(slots[i+1])
+				assert.False(t, a == 0 && b == 0, "%v: two byes in tie %d", f, i/2)
+
+				switch {
+				case a == 0:
+					worst = max(worst, b)
+				case b == 0:
+					worst = max(worst, a)
+				default:
+					best = min(best, min(a, b))
+				}
+			}
+			assert.True(t, worst <= best, "%v: a bye faces a %d while a %d plays", f, worst, best)
+		}
+	})
+
+	t.Run("Incomplete", func(t *testing.T) {
+
+		comp, matches := competition(3, 4, 1, 1)
+
+		// The best placed teams are unknown until all the groups are complete
+		slots := Draw(*comp.GroupStage, Standings(comp, matches[1:]))
+
+		assert.Nil(t, slots[0].TeamID)
+		assert.Nil(t, slots[1].TeamID)
+		assert.NotNil(t, slots[2].TeamID)
+	})
+}
+
+func TestBracket(t *testing.T) {
+
+	comp, matches := competition(2, 3, 2, 0)
+	a, b := comp.GroupStage.Groups[0].TeamIDs, comp.GroupStage.Groups[1].TeamIDs
+
+	// The winner of group A beats the runner-up of group B
+	matches = append(matches, match(a[0], b[1], 20, 2, 0))
+
+	final := Bracket(comp, matches)
+
+	if assert.NotNil(t, final) {
+		assert.Equal(t, "final", final.Name)
+
+		semi := final.Children[0]
+		assert.Equal(t, "A1", semi.Slots[0].Label)
+		assert.Equal(t, a[0], *semi.WinnerID)
+		assert.Equal(t, a[0], *final.Slots[0].TeamID)
+		assert.Equal(t, "A1", final.Slots[0].Label)
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
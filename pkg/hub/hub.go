@@ -0,0 +1,225 @@
+// Package hub publishes messages to the subscribers of a topic in process and replays the last ones
+package hub
+
+import (
+	"sync"
+	"time"
+)
+
+// idle is how long a topic without subscribers is kept, for its subscribers to come back
+const idle = time.Minute
+
+// Message represents a message published to a topic, its id is never reused in the hub
+type Message struct {
+	ID   uint64      `json:"id"`
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// topic represents the history and the subscribers of a topic, and since when it has no subscribers
+type topic struct {
+	last    uint64
+	history []Message
+	subs    map[*Subscription]bool
+	since   time.Time
+}
+
+// Hub represents the topics of messages, it is safe for concurrent use
+type Hub struct {
+	mu      sync.Mutex
+	topics  map[string]*topic
+	history int
+	buffer  int
+	seq     uint64
+	idle    time.Duration
+	now     func() time.Time
+	swept   time.Time
+}
+
+// New creates a hub keeping the last history messages of each topic and buffer messages by subscriber
+func New(history, buffer int) *Hub {
+	return &Hub{
+		topics:  map[string]*topic{},
+		history: history,
+		buffer:  buffer,
+		idle:    idle,
+		now:     time.Now,
+	}
+}
+
+// topic returns a topic by name, it is created when missing. The hub must be locked
+func (h *Hub) topic(name string) *topic {
+
+	h.sweep()
+
+	// A topic created again starts after the last id of the hub
+	t, ok := h.topics[name]
+	if !ok {
+		t = &topic{last: h.seq, subs: map[*Subscription]bool{}, since: h.now()}
+		h.topics[name] = t
+	}
+
+	return t
+}
+
+// sweep drops the topics idle for longer than the idle time, the hub must be locked
+func (h *Hub) sweep() {
+
+	now := h.now()
+	if now.Sub(h.swept) < h.idle {
+		return
+	}
+	h.swept = now
+
+	for name, t := range h.topics {
+		if len(t.subs) == 0 && now.Sub(t.since) >= h.idle {
+			delete(h.topics, name)
+		}
+	}
+}
+
+// Publish sends a message to the subscribers of a topic without blocking and returns it with its id
+func (h *Hub) Publish(name, typ string, data interface{}) Message {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t := h.topic(name)
+
+	t.last++
+	if t.last > h.seq {
+		h.seq = t.last
+	}
+	msg := Message{ID: t.last, Type: typ, Data: data}
+
+	t.history = append(t.history, msg)
+	if len(t.history) > h.history {
+		t.history = t.history[len(t.history)-h.history:]
+	}
+
+	// A subscriber whose buffer is full is dropped rather than blocking the others
+	for s := range t.subs {
+		select {
+		case s.c <- msg:
+		default:
+			s.lagged = true
+			h.remove(t, s)
+		}
+	}
+
+	return msg
+}
+
+// Last returns the id of the last message of a topic, the id the topic would start after if missing
+func (h *Hub) Last(name string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if t, ok := h.topics[name]; ok {
+		return t.last
+	}
+
+	return h.seq
+}
+
+// Kept returns whether a topic has subscribers, or had some recently
+func (h *Hub) Kept(name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, ok := h.topics[name]
+
+	return ok
+}
+
+// Followed returns whether a topic has subscribers
+func (h *Hub) Followed(name string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t, ok := h.topics[name]
+
+	return ok && len(t.subs) > 0
+}
+
+// Subscribe subscribes to the messages of a topic published from now on
+func (h *Hub) Subscribe(name string) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.subscribe(h.topic(name))
+}
+
+// SubscribeAfter subscribes to a topic after a message id, returning the missed messages and whether all were kept
+func (h *Hub) SubscribeAfter(name string, id uint64) (*Subscription, []Message, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t := h.topic(name)
+
+	// An id ahead of the topic was not published by this hub, e.g. before a restart
+	if id > t.last {
+		return h.subscribe(t), nil, false
+	}
+
+	replay := []Message{}
+	for _, msg := range t.history {
+		if msg.ID > id {
+			replay = append(replay, msg)
+		}
+	}
+
+	complete := id == t.last || (len(replay) > 0 && replay[0].ID == id+1)
+
+	return h.subscribe(t), replay, complete
+}
+
+// subscribe adds a subscriber to a topic. The hub must be locked
+func (h *Hub) subscribe(t *topic) *Subscription {
+
+	c := make(chan Message, h.buffer)
+	s := &Subscription{C: c, c: c, hub: h, topic: t}
+	t.subs[s] = true
+
+	return s
+}
+
+// remove removes a subscriber from a topic and closes its channel. The hub must be locked
+func (h *Hub) remove(t *topic, s *Subscription) {
+
+	if !t.subs[s] {
+		return
+	}
+
+	delete(t.subs, s)
+	close(s.c)
+
+	// The topic is dropped once it has been idle for a while
+	if len(t.subs) == 0 {
+		t.since = h.now()
+	}
+}
+
+// Subscription represents a subscriber of a topic, its channel is closed once it is closed or dropped
+type Subscription struct {
+	C      <-chan Message
+	c      chan Message
+	hub    *Hub
+	topic  *topic
+	lagged bool
+}
+
+// Close unsubscribes from the topic, it can be called more than once
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s.topic, s)
+}
+
+// Lagged returns whether the subscriber was dropped for falling behind the topic
+func (s *Subscription) Lagged() bool {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	return s.lagged
+}
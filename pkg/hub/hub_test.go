@@ -0,0 +1,154 @@
+package hub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublish(t *testing.T) {
+
+	h := New(10, 10)
+
+	a := h.Subscribe("derby")
+	b := h.Subscribe("derby")
+	other := h.Subscribe("classico")
+
+	msg := h.Publish("derby", "score", 1)
+	assert.Equal(t, Message{ID: 1, Type: "score", Data: 1}, msg)
+	assert.Equal(t, uint64(2), h.Publish("derby", "score", 2).ID)
+
+	// The ids increase by topic
+	assert.Equal(t, uint64(1), h.Publish("classico", "score", 1).ID)
+
+	for _, s := range []*Subscription{a, b} {
+		assert.Equal(t, uint64(1), (<-s.C).ID)
+		assert.Equal(t, uint64(2), (<-s.C).ID)
+	}
+	assert.Len(t, other.C, 1)
+
+	// A closed subscription receives nothing
+	a.Close()
+	a.Close()
+	h.Publish("derby", "score", 3)
+
+	_, ok := <-a.C
+	assert.False(t, ok)
+	assert.False(t, a.Lagged())
+	assert.Equal(t, uint64(3), (<-b.C).ID)
+	assert.Equal(t, uint64(3), h.Last("derby"))
+
+	// A missing topic starts after the last id of the hub
+	assert.Equal(t, uint64(3), h.Last("clasico"))
+}
+
+func TestSlowSubscriber(t *testing.T) {
+
+	h := New(10, 2)
+
+	slow := h.Subscribe("derby")
+	fast := h.Subscribe("derby")
+
+	for i := 0; i < 3; i++ {
+		h.Publish("derby", "event", i)
+		<-fast.C
+	}
+
+	// The slow subscriber keeps what it was sent until it was dropped
+	assert.True(t, slow.Lagged())
+	assert.Equal(t, uint64(1), (<-slow.C).ID)
+	assert.Equal(t, uint64(2), (<-slow.C).ID)
+	_, ok := <-slow.C
+	assert.False(t, ok)
+
+	assert.False(t, fast.Lagged())
+	h.Publish("derby", "event", 3)
+	assert.Equal(t, uint64(4), (<-fast.C).ID)
+}
+
+func TestSubscribeAfter(t *testing.T) {
+
+	h := New(3, 10)
+
+	for i := 0; i < 5; i++ {
+		h.Publish("derby", "event", i)
+	}
+
+	s, replay, complete := h.SubscribeAfter("derby", 3)
+	assert.True(t, complete)
+	if assert.Len(t, replay, 2) {
+		assert.Equal(t, uint64(4), replay[0].ID)
+		assert.Equal(t, uint64(5), replay[1].ID)
+	}
+
+	h.Publish("derby", "event", 5)
+	assert.Equal(t, uint64(6), (<-s.C).ID)
+
+	// Up to date
+	_, replay, complete = h.SubscribeAfter("derby", 6)
+	assert.True(t, complete)
+	assert.Empty(t, replay)
+
+	// The history only keeps the last 3 messages
+	_, replay, complete = h.SubscribeAfter("derby", 1)
+	assert.False(t, complete)
+	assert.Len(t, replay, 3)
+
+	// An id the hub never published
+	_, replay, complete = h.SubscribeAfter("derby", 42)
+	assert.False(t, complete)
+	assert.Empty(t, replay)
+
+	_, replay, complete = h.SubscribeAfter("classico", 0)
+	assert.False(t, complete)
+	assert.Empty(t, replay)
+
+	_, replay, complete = h.SubscribeAfter("clasico", h.Last("clasico"))
+	assert.True(t, complete)
+	assert.Empty(t, replay)
+}
+
+func TestIdle(t *testing.T) {
+
+	now := time.Now()
+
+	h := New(10, 10)
+	h.now = func() time.Time { return now }
+
+	s := h.Subscribe("derby")
+	h.Publish("derby", "event", 1)
+	h.Publish("classico", "event", 1)
+
+	// A subscriber coming back within the idle time replays what it missed
+	s.Close()
+	now = now.Add(idle / 2)
+	assert.False(t, h.Followed("derby"))
+	assert.True(t, h.Kept("derby"))
+	h.Publish("derby", "event", 2)
+
+	s, replay, complete := h.SubscribeAfter("derby", 1)
+	assert.True(t, complete)
+	assert.Len(t, replay, 1)
+
+	// The topics without subscribers are dropped after the idle time
+	now = now.Add(idle)
+	assert.True(t, h.Followed("derby"))
+	h.Publish("derby", "event", 3)
+
+	assert.Len(t, h.topics, 1)
+	assert.False(t, h.Kept("classico"))
+
+	s.Close()
+	now = now.Add(idle)
+	assert.False(t, h.Followed("derby"))
+	h.Publish("other", "event", 1)
+
+	assert.Len(t, h.topics, 1)
+
+	// A topic created again never reuses an id
+	assert.Equal(t, uint64(4), h.Last("derby"))
+	_, replay, complete = h.SubscribeAfter("derby", 2)
+	assert.False(t, complete)
+	assert.Empty(t, replay)
+}
@@ -0,0 +1,286 @@
+// Package knockout draws knockout brackets and resolves their ties from the results of matches
+package knockout
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Seeds returns the seeds of the first round of a bracket of size teams so the best ones meet as late as possible
+func Seeds(size int) []int {
+
+	seeds := []int{1}
+
+	for len(seeds) < size {
+		n := len(seeds) * 2
+
+		next := []int{}
+		for _, s := range seeds {
+			next = append(next, s, n+1-s)
+		}
+		seeds = next
+	}
+
+	return seeds
+}
+
+// Size returns the size of the bracket of n teams, the smallest power of two holding them
+func Size(n int) int {
+
+	size := 1
+	for size < n {
+		size *= 2
+	}
+
+	return size
+}
+
+// Rules returns the knockout rules of a competition, single legs if not set
+func Rules(comp model.Competition) model.KnockoutRules {
+
+	if comp.Knockout == nil {
+		return model.KnockoutRules{Legs: 1}
+	}
+
+	return *comp.Knockout
+}
+
+// Find returns the tie between two teams in a bracket, nil if they do not meet
+func Find(tie *model.Tie, a, b primitive.ObjectID) *model.Tie {
+
+	if tie == nil {
+		return nil
+	}
+
+	x, y := tie.Slots[0].TeamID, tie.Slots[1].TeamID
+	if x != nil && y != nil && ((*x == a && *y == b) || (*x == b && *y == a)) {
+		return tie
+	}
+
+	for _, child := range tie.Children {
+		if res := Find(child, a, b); res != nil {
+			return res
+		}
+	}
+
+	return nil
+}
+
+// Draw returns the slots of the first round of teams in the order of their seeds, paired two by two
+func Draw(teamIDs []primitive.ObjectID) []model.TieSlot {
+
+	slots := []model.TieSlot{}
+	for i, id := range teamIDs {
+		id := id
+		slots = append(slots, model.TieSlot{TeamID: &id, Seed: i + 1})
+	}
+
+	return Place(slots)
+}
+
+// Place returns the slots of the first round of qualifiers in the order of their seeds, completed with byes
+func Place(qualifiers []model.TieSlot) []model.TieSlot {
+
+	slots := []model.TieSlot{}
+	for _, seed := range Seeds(Size(len(qualifiers))) {
+		if seed <= len(qualifiers) {
+			slot := qualifiers[seed-1]
+			slot.Seed = seed
+			slots = append(slots, slot)
+		} else {
+			slots = append(slots, model.TieSlot{})
+		}
+	}
+
+	return slots
+}
+
+// Build returns the final of the bracket of teams in the order of their seeds, nil with less than two teams
+func Build(teamIDs []primitive.ObjectID, matches []model.Match, rules model.KnockoutRules) *model.Tie {
+
+	if len(teamIDs) < 2 {
+		return nil
+	}
+
+	return BuildFrom(Draw(teamIDs), matches, rules)
+}
+
+// BuildFrom returns the final of the bracket of the slots of its first round, a power of two of them
+func BuildFrom(slots []model.TieSlot, matches []model.Match, rules model.KnockoutRules) *model.Tie {
+
+	rounds := 0
+	for n := len(slots); n > 1; n /= 2 {
+		rounds++
+	}
+
+	var ties []*model.Tie
+
+	for i := 0; i+1 < len(slots); i += 2 {
+		ties = append(ties, &model.Tie{Round: 1, Slots: [2]model.TieSlot{slots[i], slots[i+1]}})
+	}
+
+	if len(ties) == 0 {
+		return nil
+	}
+
+	for round := 1; ; round++ {
+		for _, tie := range ties {
+			tie.Name = Name(round, rounds)
+			tie.Legs = rules.Legs
+			if round == rounds && rules.SingleLegFinal {
+				tie.Legs = 1
+			}
+			resolve(tie, matches, rules)
+		}
+
+		if len(ties) == 1 {
+			return ties[0]
+		}
+
+		// The winners of two ties play the next one
+		var next []*model.Tie
+		for i := 0; i < len(ties); i += 2 {
+			tie := &model.Tie{Round: round + 1, Children: []*model.Tie{ties[i], ties[i+1]}}
+			for j, child := range tie.Children {
+				tie.Slots[j] = winner(child)
+			}
+			next = append(next, tie)
+		}
+		ties = next
+	}
+}
+
+// Name returns the name of a round of a bracket
+func Name(round, rounds int) string {
+
+	switch rounds - round {
+	case 0:
+		return "final"
+	case 1:
+		return "semi_final"
+	case 2:
+		return "quarter_final"
+	}
+
+	return fmt.Sprintf("round_of_%d", 1<<uint(rounds-round+1))
+}
+
+// winner returns the slot of the winner of a tie for the next round, empty until known
+func winner(tie *model.Tie) model.TieSlot {
+
+	for _, slot := range tie.Slots {
+		if slot.TeamID != nil && tie.WinnerID != nil && *slot.TeamID == *tie.WinnerID {
+			return model.TieSlot{TeamID: slot.TeamID, Seed: slot.Seed, Label: slot.Label}
+		}
+	}
+
+	return model.TieSlot{}
+}
+
+// resolve sums the legs of a tie and sets its winner once it is decided
+func resolve(tie *model.Tie, matches []model.Match, rules model.KnockoutRules) {
+
+	tie.MatchIDs = []primitive.ObjectID{}
+
+	a, b := tie.Slots[0].TeamID, tie.Slots[1].TeamID
+
+	switch {
+	case a != nil && isBye(tie.Slots[1]) && tie.Round == 1:
+		tie.WinnerID, tie.DecidedBy = a, model.DecidedByBye
+		return
+	case b != nil && isBye(tie.Slots[0]) && tie.Round == 1:
+		tie.WinnerID, tie.DecidedBy = b, model.DecidedByBye
+		return
+	case a == nil || b == nil:
+		return
+	}
+
+	legs := legsOf(*a, *b, matches)
+	for _, m := range legs {
+		tie.MatchIDs = append(tie.MatchIDs, m.ID)
+	}
+
+	played := 0
+	var last model.Match
+
+	for _, m := range legs {
+		if m.Status != model.MatchStatusFinished || played == tie.Legs {
+			continue
+		}
+		played++
+		last = m
+
+		for i, slot := range tie.Slots {
+			if m.HomeTeamID == *slot.TeamID {
+				tie.Slots[i].Aggregate += m.Score.Home
+			} else {
+				tie.Slots[i].Aggregate += m.Score.Away
+				tie.Slots[i].AwayGoals += m.Score.Away
+			}
+		}
+	}
+
+	if played < tie.Legs {
+		return
+	}
+
+	first, second := &tie.Slots[0], &tie.Slots[1]
+
+	switch {
+	case first.Aggregate != second.Aggregate:
+		tie.DecidedBy = model.DecidedByAggregate
+		if first.Aggregate > second.Aggregate {
+			tie.WinnerID = a
+		} else {
+			tie.WinnerID = b
+		}
+	case rules.AwayGoals && tie.Legs == 2 && first.AwayGoals != second.AwayGoals:
+		tie.DecidedBy = model.DecidedByAwayGoals
+		if first.AwayGoals > second.AwayGoals {
+			tie.WinnerID = a
+		} else {
+			tie.WinnerID = b
+		}
+	case last.Penalties != nil:
+		home, away := last.Penalties.Home, last.Penalties.Away
+		if last.HomeTeamID != *a {
+			home, away = away, home
+		}
+		first.Penalties, second.Penalties = &home, &away
+
+		tie.DecidedBy = model.DecidedByPenalties
+		if home > away {
+			tie.WinnerID = a
+		} else {
+			tie.WinnerID = b
+		}
+	}
+}
+
+// isBye returns whether a slot of the first round is a bye, a qualifier still to be known has a label
+func isBye(slot model.TieSlot) bool {
+	return slot.TeamID == nil && len(slot.Label) <= 0
+}
+
+// legsOf returns the matches between two teams by kickoff, cancelled matches are not legs
+func legsOf(a, b primitive.ObjectID, matches []model.Match) []model.Match {
+
+	res := []model.Match{}
+	for _, m := range matches {
+		if m.Status == model.MatchStatusCancelled {
+			continue
+		}
+		if (m.HomeTeamID == a && m.AwayTeamID == b) || (m.HomeTeamID == b && m.AwayTeamID == a) {
+			res = append(res, m)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Kickoff.Before(res[j].Kickoff)
+	})
+
+	return res
+}
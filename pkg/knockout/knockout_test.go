@@ -0,0 +1,152 @@
+package knockout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func teams(n int) []primitive.ObjectID {
+
+	res := []primitive.ObjectID{}
+	for i := 0; i < n; i++ {
+		res = append(res, primitive.NewObjectID())
+	}
+
+	return res
+}
+
+func leg(home, away primitive.ObjectID, day, homeGoals, awayGoals int) model.Match {
+	return model.Match{
+		ID:         primitive.NewObjectID(),
+		HomeTeamID: home,
+		AwayTeamID: away,
+		Kickoff:    time.Date(2021, 2, day, 20, 0, 0, 0, time.UTC),
+		Status:     model.MatchStatusFinished,
+		Score:      model.Score{Home: homeGoals, Away: awayGoals},
+	}
+}
+
+func TestSeeds(t *testing.T) {
+
+	assert.Equal(t, []int{1, 2}, Seeds(2))
+	assert.Equal(t, []int{1, 4, 2, 3}, Seeds(4))
+	assert.Equal(t, []int{1, 8, 4, 5, 2, 7, 3, 6}, Seeds(8))
+
+	assert.Equal(t, 8, Size(5))
+	assert.Equal(t, 8, Size(8))
+}
+
+func TestBuild(t *testing.T) {
+
+	t.Run("Byes", func(t *testing.T) {
+
+		ids := teams(5)
+
+		final := Build(ids, nil, model.KnockoutRules{Legs: 1})
+
+		assert.Equal(t, "final", final.Name)
+		assert.Equal(t, 3, final.Round)
+
+		semi := final.Children[0]
+		assert.Equal(t, "semi_final", semi.Name)
+
+		// The best seeds get the byes and advance to the next round
+		first := semi.Children[0]
+		assert.Equal(t, "quarter_final", first.Name)
+		assert.Equal(t, model.DecidedByBye, first.DecidedBy)
+		assert.Equal(t, ids[0], *first.WinnerID)
+		assert.Nil(t, first.Slots[1].TeamID)
+
+		open := semi.Children[1]
+		assert.Equal(t, 4, open.Slots[0].Seed)
+		assert.Equal(t, 5, open.Slots[1].Seed)
+		assert.Nil(t, open.WinnerID)
+
+		assert.Equal(t, ids[0], *semi.Slots[0].TeamID)
+		assert.Nil(t, semi.Slots[1].TeamID)
+	})
+
+	t.Run("Aggregate", func(t *testing.T) {
+
+		ids := teams(2)
+		matches := []model.Match{
+			leg(ids[1], ids[0], 16, 2, 1),
+			leg(ids[0], ids[1], 23, 2, 0),
+		}
+
+		final := Build(ids, matches, model.KnockoutRules{Legs: 2})
+
+		assert.Len(t, final.MatchIDs, 2)
+		assert.Equal(t, 3, final.Slots[0].Aggregate)
+		assert.Equal(t, 1, final.Slots[0].AwayGoals)
+		assert.Equal(t, 2, final.Slots[1].Aggregate)
+		assert.Equal(t, model.DecidedByAggregate, final.DecidedBy)
+		assert.Equal(t, ids[0], *final.WinnerID)
+
+		// The tie is open until both legs are played
+		final = Build(ids, matches[:1], model.KnockoutRules{Legs: 2})
+		assert.Nil(t, final.WinnerID)
+	})
+
+	t.Run("Away Goals", func(t *testing.T) {
+
+		ids := teams(2)
+		matches := []model.Match{
+			leg(ids[0], ids[1], 16, 1, 2),
+			leg(ids[1], ids[0], 23, 0, 1),
+		}
+
+		final := Build(ids, matches, model.KnockoutRules{Legs: 2, AwayGoals: true})
+
+		assert.Equal(t, model.DecidedByAwayGoals, final.DecidedBy)
+		assert.Equal(t, ids[1], *final.WinnerID)
+
+		// Without the away goals rule a level tie needs a shootout
+		final = Build(ids, matches, model.KnockoutRules{Legs: 2})
+		assert.Nil(t, final.WinnerID)
+	})
+
+	t.Run("Penalties", func(t *testing.T) {
+
+		ids := teams(2)
+		matches := []model.Match{
+			leg(ids[0], ids[1], 16, 1, 1),
+			leg(ids[1], ids[0], 23, 1, 1),
+		}
+		matches[1].Penalties = &model.Score{Home: 3, Away: 4}
+
+		final := Build(ids, matches, model.KnockoutRules{Legs: 2})
+
+		assert.Equal(t, model.DecidedByPenalties, final.DecidedBy)
+		assert.Equal(t, ids[0], *final.WinnerID)
+		assert.Equal(t, 4, *final.Slots[0].Penalties)
+		assert.Equal(t, 3, *final.Slots[1].Penalties)
+	})
+
+	t.Run("Advancement", func(t *testing.T) {
+
+		ids := teams(4)
+		matches := []model.Match{
+			leg(ids[0], ids[3], 16, 0, 1),
+			leg(ids[3], ids[0], 23, 0, 0),
+			leg(ids[1], ids[2], 16, 2, 2),
+		}
+
+		final := Build(ids, matches, model.KnockoutRules{Legs: 2, SingleLegFinal: true})
+
+		// The final is played in a single leg
+		assert.Equal(t, 1, final.Legs)
+		assert.Equal(t, 2, final.Children[0].Legs)
+
+		assert.Equal(t, ids[3], *final.Slots[0].TeamID)
+		assert.Equal(t, 4, final.Slots[0].Seed)
+		assert.Nil(t, final.Slots[1].TeamID)
+
+		assert.Equal(t, final.Children[1], Find(final, ids[2], ids[1]))
+		assert.Nil(t, Find(final, ids[0], ids[1]))
+	})
+}
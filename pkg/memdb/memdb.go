@@ -0,0 +1,38 @@
+package memdb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Team represents a team as stored, with the ids of its players
+type Team struct {
+	ID          primitive.ObjectID
+	Name        string
+	Description string
+	Location    string
+	Players     []primitive.ObjectID
+	CreatedAt   time.Time
+}
+
+// DB represents an in-memory database shared by the in-memory repositories, it must be locked
+type DB struct {
+	sync.RWMutex
+	Players      map[primitive.ObjectID]model.Player
+	Teams        map[primitive.ObjectID]Team
+	Matches      map[primitive.ObjectID]model.Match
+	Competitions map[primitive.ObjectID]model.Competition
+}
+
+// New creates a new empty in-memory database
+func New() *DB {
+	return &DB{
+		Players:      map[primitive.ObjectID]model.Player{},
+		Teams:        map[primitive.ObjectID]Team{},
+		Matches:      map[primitive.ObjectID]model.Match{},
+		Competitions: map[primitive.ObjectID]model.Competition{},
+	}
+}
@@ -0,0 +1,108 @@
+// Package position normalizes the positions of players to the codes of the taxonomy
+package position
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/yezarela/go-soccer/model"
+)
+
+// aliases are the legacy values of the positions, as keys
+var aliases = map[string][]string{
+	model.PositionGoalkeeper:          {"goalkeeper", "goalie", "keeper", "g"},
+	model.PositionCentreBack:          {"centre back", "center back", "central defender", "defender", "d", "def"},
+	model.PositionLeftBack:            {"left back", "left full back"},
+	model.PositionRightBack:           {"right back", "right full back"},
+	model.PositionLeftWingBack:        {"left wing back"},
+	model.PositionRightWingBack:       {"right wing back"},
+	model.PositionDefensiveMidfielder: {"defensive midfielder", "defensive midfield", "holding midfielder", "cdm"},
+	model.PositionCentralMidfielder:   {"central midfielder", "centre midfielder", "center midfielder", "midfielder", "m", "mf", "mid"},
+	model.PositionLeftMidfielder:      {"left midfielder", "left midfield"},
+	model.PositionRightMidfielder:     {"right midfielder", "right midfield"},
+	model.PositionAttackingMidfielder: {"attacking midfielder", "attacking midfield", "playmaker", "cam"},
+	model.PositionLeftWinger:          {"left winger", "left wing"},
+	model.PositionRightWinger:         {"right winger", "right wing"},
+	model.PositionCentreForward:       {"centre forward", "center forward", "second striker", "ss"},
+	model.PositionStriker:             {"striker", "forward", "attacker", "fw", "f", "fwd"},
+}
+
+// index are the positions by key of their codes and aliases
+var index = map[string]string{}
+
+func init() {
+	for code, values := range aliases {
+		index[key(code)] = code
+		for _, v := range values {
+			index[key(v)] = code
+		}
+	}
+}
+
+// key returns the lower case words of a value, hyphens and underscores separate words
+func key(value string) string {
+
+	value = strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(value))
+
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// Normalize returns the code of a position from its code or one of its aliases and whether it is known
+func Normalize(value string) (string, bool) {
+
+	code, ok := index[key(value)]
+	if !ok {
+		return value, false
+	}
+
+	return code, true
+}
+
+// Values returns the code of a position and its aliases as legacy values may spell them
+func Values(code string) []string {
+
+	res := []string{}
+	seen := map[string]bool{}
+	add := func(v string) {
+		if !seen[v] {
+			seen[v] = true
+			res = append(res, v)
+		}
+	}
+
+	add(code)
+	add(strings.ToLower(code))
+	for _, a := range aliases[code] {
+		for _, v := range []string{a, strings.ReplaceAll(a, " ", "-")} {
+			add(v)
+			add(strings.Title(v))
+			add(strings.ToUpper(v))
+		}
+	}
+
+	return res
+}
+
+// Valid returns whether a value is the code of a position
+func Valid(code string) bool {
+	_, ok := model.PositionLines[code]
+	return ok
+}
+
+// Line returns the line of a position, empty if unknown
+func Line(code string) string {
+	return model.PositionLines[code]
+}
+
+// All returns the positions of the taxonomy with their line and aliases, from the goal to the attack
+func All() []model.PositionInfo {
+
+	res := []model.PositionInfo{}
+	for _, code := range model.Positions {
+		a := append([]string{}, aliases[code]...)
+		sort.Strings(a)
+		res = append(res, model.PositionInfo{Code: code, Line: Line(code), Aliases: a})
+	}
+
+	return res
+}
@@ -0,0 +1,61 @@
+package position
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+)
+
+func TestNormalize(t *testing.T) {
+
+	for _, value := range []string{"forward", "FW", "Striker", "st", " ST "} {
+		code, ok := Normalize(value)
+		assert.True(t, ok, value)
+		assert.Equal(t, model.PositionStriker, code, value)
+	}
+
+	code, ok := Normalize("Centre-Back")
+	assert.True(t, ok)
+	assert.Equal(t, model.PositionCentreBack, code)
+
+	code, ok = Normalize("left_wing_back")
+	assert.True(t, ok)
+	assert.Equal(t, model.PositionLeftWingBack, code)
+
+	code, ok = Normalize("Captain")
+	assert.False(t, ok)
+	assert.Equal(t, "Captain", code)
+}
+
+func TestAll(t *testing.T) {
+
+	all := All()
+
+	// Every position has a line and the aliases do not overlap
+	assert.Len(t, all, len(model.Positions))
+	assert.Len(t, aliases, len(model.Positions))
+
+	count := len(all)
+	for _, p := range all {
+		assert.NotEmpty(t, p.Line, p.Code)
+		count += len(p.Aliases)
+	}
+	assert.Equal(t, count, len(index))
+
+	assert.Equal(t, model.LineGoalkeeper, Line(model.PositionGoalkeeper))
+	assert.Equal(t, model.LineAttack, Line(model.PositionLeftWinger))
+	assert.Empty(t, Line("forward"))
+}
+
+func TestValues(t *testing.T) {
+
+	values := Values(model.PositionLeftBack)
+
+	assert.Equal(t, []string{model.PositionLeftBack, "lb"}, values[:2])
+	for _, v := range []string{"left back", "Left Back", "LEFT BACK", "left-back", "Left-Back", "left full back"} {
+		assert.Contains(t, values, v)
+	}
+
+	assert.Equal(t, []string{"Captain", "captain"}, Values("Captain"))
+}
@@ -0,0 +1,369 @@
+// Package predict predicts the outcome of matches with a Poisson model of the goals
+package predict
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// FormLength is the number of results of the form of a team
+	FormLength = 5
+	// MaxGoals is the largest number of goals of a team counted in the probabilities
+	MaxGoals = 10
+	// ridge is the penalty keeping the coefficients small when there are few matches to fit them
+	ridge = 1.0
+)
+
+// Model represents the Poisson model of the goals of a match from the rating and form differences
+type Model struct {
+	// HomeGoals is the average goals of the home teams
+	HomeGoals float64
+	// AwayGoals is the average goals of the away teams
+	AwayGoals float64
+	// Rating is the coefficient of the difference of ratings, in units of 400 points
+	Rating float64
+	// Form is the coefficient of the difference of forms
+	Form float64
+}
+
+// Default is the model without any match, both teams are even
+var Default = Model{HomeGoals: 1.5, AwayGoals: 1.2}
+
+// Features represents the differences between the home and the away team before a match
+type Features struct {
+	// Rating is the difference of ratings, in units of 400 points
+	Rating float64
+	// Form is the difference of the points per match of the forms, a win is worth 1 and a draw 0.5
+	Form float64
+}
+
+// Sample represents a finished match to fit the model
+type Sample struct {
+	Features
+	MatchID primitive.ObjectID
+	Kickoff time.Time
+	Score   model.Score
+}
+
+// Report represents the scores of the predictions of the finished matches, a lower Brier score is better
+type Report struct {
+	// Matches is the number of predicted matches
+	Matches int
+	// Brier is the mean Brier score of the model, between 0 and 2
+	Brier float64
+	// Baseline is the mean Brier score of the frequencies of home wins, draws and away wins
+	Baseline float64
+}
+
+// Before returns the rating and the form of a team before a match, its current ones if unknown
+func Before(r model.Rating, matchID primitive.ObjectID) (float64, string) {
+
+	for i, h := range r.History {
+		if h.MatchID == matchID {
+			return h.Before, Form(r.History[:i])
+		}
+	}
+
+	return r.Rating, Form(r.History)
+}
+
+// Form returns the last results of a history, from the oldest
+func Form(history []model.RatingChange) string {
+
+	if len(history) > FormLength {
+		history = history[len(history)-FormLength:]
+	}
+
+	res := ""
+	for _, h := range history {
+		res += h.Result
+	}
+
+	return res
+}
+
+// points returns the points per match of a form, a team without results is average
+func points(form string) float64 {
+
+	if len(form) == 0 {
+		return 0.5
+	}
+
+	res := 0.0
+	for _, r := range form {
+		switch string(r) {
+		case model.ResultWin:
+			res++
+		case model.ResultDraw:
+			res += 0.5
+		}
+	}
+
+	return res / float64(len(form))
+}
+
+// Compare returns the features of a match between teams of the given ratings and forms
+func Compare(home, away float64, homeForm, awayForm string) Features {
+	return Features{
+		Rating: (home - away) / 400,
+		Form:   points(homeForm) - points(awayForm),
+	}
+}
+
+// Samples returns the finished matches by kickoff, with the ratings and the forms of their teams before them
+func Samples(matches []model.Match, ratings map[primitive.ObjectID]*model.Rating) []Sample {
+
+	sorted := []model.Match{}
+	for _, m := range matches {
+		if m.Status == model.MatchStatusFinished {
+			sorted = append(sorted, m)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Kickoff.Before(sorted[j].Kickoff)
+	})
+
+	res := []Sample{}
+	for _, m := range sorted {
+		home, okHome := ratings[m.HomeTeamID]
+		away, okAway := ratings[m.AwayTeamID]
+		if !okHome || !okAway {
+			continue
+		}
+
+		homeRating, homeForm := Before(*home, m.ID)
+		awayRating, awayForm := Before(*away, m.ID)
+
+		res = append(res, Sample{
+			Features: Compare(homeRating, awayRating, homeForm, awayForm),
+			MatchID:  m.ID,
+			Kickoff:  m.Kickoff,
+			Score:    m.Score,
+		})
+	}
+
+	return res
+}
+
+// Until returns the samples by kickoff of the matches kicked off before a time
+func Until(samples []Sample, kickoff time.Time) []Sample {
+
+	n := sort.Search(len(samples), func(i int) bool {
+		return !samples[i].Kickoff.Before(kickoff)
+	})
+
+	return samples[:n]
+}
+
+// Fit returns the model maximizing the likelihood of the goals of the samples
+func Fit(samples []Sample) Model {
+	return fit(samples, Default)
+}
+
+// fit returns the model of the samples, Newton's method starting from the coefficients of a model
+func fit(samples []Sample, start Model) Model {
+
+	res := Default
+	if len(samples) == 0 {
+		return res
+	}
+	res.Rating, res.Form = start.Rating, start.Form
+
+	// The averages count the default model as one more match
+	home, away := Default.HomeGoals, Default.AwayGoals
+	for _, s := range samples {
+		home += float64(s.Score.Home)
+		away += float64(s.Score.Away)
+	}
+	res.HomeGoals = home / float64(len(samples)+1)
+	res.AwayGoals = away / float64(len(samples)+1)
+
+	for i := 0; i < 50; i++ {
+		// Gradient and negated Hessian of the penalized log-likelihood
+		g1, g2 := -ridge*res.Rating, -ridge*res.Form
+		h11, h12, h22 := ridge, 0.0, ridge
+
+		for _, s := range samples {
+			lh, la := res.Goals(s.Features)
+			d := float64(s.Score.Home) - lh - float64(s.Score.Away) + la
+			w := lh + la
+
+			g1 += d * s.Rating
+			g2 += d * s.Form
+			h11 += w * s.Rating * s.Rating
+			h12 += w * s.Rating * s.Form
+			h22 += w * s.Form * s.Form
+		}
+
+		det := h11*h22 - h12*h12
+		step1 := (h22*g1 - h12*g2) / det
+		step2 := (h11*g2 - h12*g1) / det
+
+		res.Rating += step1
+		res.Form += step2
+
+		if math.Abs(step1) < 1e-9 && math.Abs(step2) < 1e-9 {
+			break
+		}
+	}
+
+	return res
+}
+
+// Goals returns the expected goals of the home and the away team
+func (m Model) Goals(f Features) (float64, float64) {
+	x := m.Rating*f.Rating + m.Form*f.Form
+	return m.HomeGoals * math.Exp(x), m.AwayGoals * math.Exp(-x)
+}
+
+// scores returns the probabilities of the scores up to MaxGoals by each team, they add up to 1
+func (m Model) scores(f Features) [][]float64 {
+
+	home, away := m.Goals(f)
+	ph, pa := poisson(home), poisson(away)
+
+	res := make([][]float64, MaxGoals+1)
+	total := 0.0
+	for i := range res {
+		res[i] = make([]float64, MaxGoals+1)
+		for j := range res[i] {
+			res[i][j] = ph[i] * pa[j]
+			total += res[i][j]
+		}
+	}
+
+	for i := range res {
+		for j := range res[i] {
+			res[i][j] /= total
+		}
+	}
+
+	return res
+}
+
+// poisson returns the probabilities of 0 to MaxGoals goals for a mean
+func poisson(mean float64) []float64 {
+
+	res := make([]float64, MaxGoals+1)
+	res[0] = math.Exp(-mean)
+	for k := 1; k <= MaxGoals; k++ {
+		res[k] = res[k-1] * mean / float64(k)
+	}
+
+	return res
+}
+
+// Probabilities returns the probabilities of a home win, a draw and an away win
+func (m Model) Probabilities(f Features) (float64, float64, float64) {
+
+	var home, draw, away float64
+	for i, row := range m.scores(f) {
+		for j, p := range row {
+			switch {
+			case i > j:
+				home += p
+			case i < j:
+				away += p
+			default:
+				draw += p
+			}
+		}
+	}
+
+	return home, draw, away
+}
+
+// Predict returns the prediction of a match with rounded probabilities and expected goals
+func (m Model) Predict(f Features) model.Prediction {
+
+	res := model.Prediction{}
+
+	home, draw, away := m.Probabilities(f)
+	res.HomeWin, res.Draw, res.AwayWin = round(home, 1000), round(draw, 1000), round(away, 1000)
+
+	goalsHome, goalsAway := m.Goals(f)
+	res.ExpectedScore = model.ExpectedScore{Home: round(goalsHome, 100), Away: round(goalsAway, 100)}
+
+	best := -1.0
+	for i, row := range m.scores(f) {
+		for j, p := range row {
+			if p > best {
+				best = p
+				res.LikelyScore = model.Score{Home: i, Away: j}
+			}
+		}
+	}
+
+	return res
+}
+
+// round rounds a value to a unit of 1/scale
+func round(v, scale float64) float64 {
+	return math.Round(v*scale) / scale
+}
+
+// outcome returns the index of the outcome of a score, a home win, a draw or an away win
+func outcome(score model.Score) int {
+	switch {
+	case score.Home > score.Away:
+		return 0
+	case score.Home == score.Away:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// brier returns the Brier score of the probabilities of a home win, a draw and an away win for a score
+func brier(home, draw, away float64, score model.Score) float64 {
+
+	res := 0.0
+	for i, p := range []float64{home, draw, away} {
+		if i == outcome(score) {
+			p--
+		}
+		res += p * p
+	}
+
+	return res
+}
+
+// Backtest scores the predictions of the samples after the first warmup ones, each from the samples before it
+func Backtest(samples []Sample, warmup int) Report {
+
+	res := Report{}
+	if warmup < 0 {
+		warmup = 0
+	}
+
+	// Each fit starts from the previous one, the baseline predicts the frequencies of the outcomes so far
+	m := Default
+	counts := [3]float64{1, 1, 1}
+	for i, s := range samples {
+		if i >= warmup {
+			m = fit(samples[:i], m)
+
+			home, draw, away := m.Probabilities(s.Features)
+			res.Brier += brier(home, draw, away, s.Score)
+
+			total := counts[0] + counts[1] + counts[2]
+			res.Baseline += brier(counts[0]/total, counts[1]/total, counts[2]/total, s.Score)
+
+			res.Matches++
+		}
+
+		counts[outcome(s.Score)]++
+	}
+
+	if res.Matches > 0 {
+		res.Brier /= float64(res.Matches)
+		res.Baseline /= float64(res.Matches)
+	}
+
+	return res
+}
@@ -0,0 +1,122 @@
+package predict
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	"github.com/yezarela/go-soccer/pkg/elo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestForm(t *testing.T) {
+
+	history := []model.RatingChange{}
+	for _, r := range "WWDLWLD" {
+		c := model.RatingChange{}
+		c.MatchID = primitive.NewObjectID()
+		c.Result = string(r)
+		c.Before = float64(1500 + len(history))
+		history = append(history, c)
+	}
+	current := model.Rating{Rating: 1490, History: history}
+
+	assert.Equal(t, "DLWLD", Form(history))
+	assert.Equal(t, "", Form(nil))
+
+	rating, form := Before(current, history[2].MatchID)
+	assert.Equal(t, 1502.0, rating)
+	assert.Equal(t, "WW", form)
+
+	rating, form = Before(current, primitive.NewObjectID())
+	assert.Equal(t, 1490.0, rating)
+	assert.Equal(t, "DLWLD", form)
+
+	assert.Equal(t, Features{Rating: 0.25, Form: 0.5}, Compare(1600, 1500, "WWWW", "WL"))
+	assert.Equal(t, Features{}, Compare(1500, 1500, "", "DD"))
+}
+
+func TestPredict(t *testing.T) {
+
+	// Even teams favour the home team, which scores more on average
+	even := Default.Predict(Features{})
+	assert.Equal(t, model.ExpectedScore{Home: 1.5, Away: 1.2}, even.ExpectedScore)
+	assert.Equal(t, model.Score{Home: 1, Away: 1}, even.LikelyScore)
+	assert.True(t, even.HomeWin > even.AwayWin)
+	assert.InDelta(t, 1, even.HomeWin+even.Draw+even.AwayWin, 0.002)
+
+	home, draw, away := Default.Probabilities(Features{Rating: 1})
+	assert.InDelta(t, 1, home+draw+away, 1e-9)
+
+	// A stronger away team is favoured
+	m := Model{HomeGoals: 1.5, AwayGoals: 1.2, Rating: 1}
+	strong := m.Predict(Features{Rating: -1})
+	assert.True(t, strong.AwayWin > strong.HomeWin)
+	assert.True(t, strong.ExpectedScore.Away > strong.ExpectedScore.Home)
+}
+
+func TestFit(t *testing.T) {
+
+	assert.Equal(t, Default, Fit(nil))
+
+	// The stronger teams score more and the teams in form concede less
+	samples := []Sample{}
+	for i := 0; i < 20; i++ {
+		samples = append(samples,
+			Sample{Features: Features{Rating: 0.5}, Score: model.Score{Home: 3, Away: 0}},
+			Sample{Features: Features{Rating: -0.5}, Score: model.Score{Home: 0, Away: 2}},
+			Sample{Features: Features{Form: 0.5}, Score: model.Score{Home: 2, Away: 1}},
+			Sample{Features: Features{}, Score: model.Score{Home: 1, Away: 1}},
+		)
+	}
+
+	m := Fit(samples)
+	assert.InDelta(t, 1.5, m.HomeGoals, 0.01)
+	assert.InDelta(t, 1.0, m.AwayGoals, 0.01)
+	assert.True(t, m.Rating > 1)
+	assert.True(t, m.Form > 0)
+
+	// Starting from another fit converges to the same model
+	warm := fit(samples, Fit(samples[:10]))
+	assert.InDelta(t, m.Rating, warm.Rating, 1e-6)
+	assert.InDelta(t, m.Form, warm.Form, 1e-6)
+
+	// The model predicts the samples better than the frequencies of the outcomes
+	r := Backtest(samples, 8)
+	assert.Equal(t, 72, r.Matches)
+	assert.True(t, r.Brier < r.Baseline)
+	assert.True(t, r.Brier > 0)
+
+	assert.Equal(t, Report{}, Backtest(samples, len(samples)))
+}
+
+func TestSamples(t *testing.T) {
+
+	milan := primitive.NewObjectID()
+	inter := primitive.NewObjectID()
+
+	kickoff := time.Date(2020, 10, 17, 15, 0, 0, 0, time.UTC)
+	first := model.Match{ID: primitive.NewObjectID(), HomeTeamID: milan, AwayTeamID: inter, Kickoff: kickoff, Status: model.MatchStatusFinished, Score: model.Score{Home: 1}}
+	second := model.Match{ID: primitive.NewObjectID(), HomeTeamID: inter, AwayTeamID: milan, Kickoff: kickoff.AddDate(0, 0, 7), Status: model.MatchStatusFinished, Score: model.Score{Home: 2, Away: 2}}
+	scheduled := model.Match{ID: primitive.NewObjectID(), HomeTeamID: milan, AwayTeamID: inter, Kickoff: kickoff.AddDate(0, 0, 14), Status: model.MatchStatusScheduled}
+
+	matches := []model.Match{second, scheduled, first}
+	res := Samples(matches, elo.Rate(matches, elo.Default))
+
+	if assert.Len(t, res, 2) {
+		assert.Equal(t, first.ID, res[0].MatchID)
+		assert.Equal(t, Features{}, res[0].Features)
+
+		// Inter lost the first match
+		assert.Equal(t, second.ID, res[1].MatchID)
+		assert.Equal(t, second.Score, res[1].Score)
+		assert.InDelta(t, -14.4/400, res[1].Rating, 1e-9)
+		assert.Equal(t, -1.0, res[1].Form)
+
+		// A match is predicted from the matches kicked off before it
+		assert.Empty(t, Until(res, first.Kickoff))
+		assert.Equal(t, res[:1], Until(res, second.Kickoff))
+		assert.Equal(t, res, Until(res, scheduled.Kickoff))
+	}
+}
@@ -0,0 +1,135 @@
+package query
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Counter returns the total number of items of a list
+type Counter func() (int64, error)
+
+// Total returns the counter of a list whose total is known
+func Total(n int) Counter {
+	return func() (int64, error) {
+		return int64(n), nil
+	}
+}
+
+// EstimatedCount returns the counter of a collection, from its metadata
+func EstimatedCount(ctx context.Context, coll *mongo.Collection) Counter {
+	return func() (int64, error) {
+		return coll.EstimatedDocumentCount(ctx)
+	}
+}
+
+// FetchLimit returns the number of items to fetch for a page, one more to tell whether there is a next one
+func (p Params) FetchLimit() int {
+	return p.Limit + 1
+}
+
+// NewPage returns the page of the n sorted items fetched with FetchLimit and the number of its items
+func (p Params) NewPage(n int, values func(i int) map[string]interface{}, count Counter) (*Page, int, error) {
+
+	page := &Page{}
+
+	if n > p.Limit {
+		n = p.Limit
+		page.HasMore = true
+		page.NextCursor = p.NextCursor(values(n - 1))
+	}
+
+	// Counting is only cheap when the list is not filtered
+	if !p.HasFilters() && count != nil {
+		total, err := count()
+		if err != nil {
+			return nil, 0, err
+		}
+		page.Total = &total
+	}
+
+	return page, n, nil
+}
+
+// MongoPage decodes the documents fetched with FetchLimit into a pointer to a slice and returns their page
+func (p Params) MongoPage(ctx context.Context, cur *mongo.Cursor, items interface{}, count Counter) (*Page, error) {
+
+	var docs []bson.Raw
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	// The cursor is read from the stored document so a missing or null field sorts like mongodb sorts it
+	page, n, err := p.NewPage(len(docs), func(i int) map[string]interface{} {
+		return p.mongoValues(docs[i])
+	}, count)
+	if err != nil {
+		return nil, err
+	}
+
+	slice := reflect.ValueOf(items).Elem()
+	res := reflect.MakeSlice(slice.Type(), n, n)
+	for i := 0; i < n; i++ {
+		if err := bson.Unmarshal(docs[i], res.Index(i).Addr().Interface()); err != nil {
+			return nil, err
+		}
+	}
+	slice.Set(res)
+
+	return page, nil
+}
+
+// mongoValues returns the values of the sort fields of a stored document, nil when missing or null
+func (p Params) mongoValues(doc bson.Raw) map[string]interface{} {
+
+	res := map[string]interface{}{}
+
+	for _, s := range p.Sort {
+		v := doc.Lookup(s.Field)
+
+		switch v.Type {
+		case bsontype.String:
+			res[s.Field] = v.StringValue()
+		case bsontype.DateTime:
+			res[s.Field] = v.Time().UTC()
+		case bsontype.ObjectID:
+			res[s.Field] = v.ObjectID()
+		default:
+			res[s.Field] = nil
+		}
+	}
+
+	return res
+}
+
+// All lists every item matching the filters, calling list with the params of each page
+func All(filters url.Values, fields Fields, list func(params Params) (*Page, error)) error {
+
+	values := url.Values{}
+	for k, v := range filters {
+		values[k] = v
+	}
+	values.Set("limit", strconv.Itoa(MaxLimit))
+
+	for {
+		params, err := ParseParams(values, fields)
+		if err != nil {
+			return err
+		}
+
+		page, err := list(params)
+		if err != nil {
+			return err
+		}
+
+		if !page.HasMore {
+			return nil
+		}
+		values.Set("cursor", page.NextCursor)
+	}
+}
@@ -0,0 +1,506 @@
+package query
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// DefaultLimit is the number of items returned when no limit is given
+	DefaultLimit = 20
+	// MaxLimit is the maximum number of items returned in a page
+	MaxLimit = 100
+)
+
+// FieldType represents the type of a filterable and sortable field
+type FieldType int
+
+const (
+	// String is a field compared as text
+	String FieldType = iota
+	// Time is a field compared as a RFC 3339 timestamp or a date
+	Time
+	// ID is a field compared as an ObjectID
+	ID
+)
+
+// Fields maps the filterable and sortable fields of a list to their type
+type Fields map[string]FieldType
+
+// Operators supported by filters, a filter without operator uses eq
+var operators = map[string]string{
+	"eq":  "$eq",
+	"ne":  "$ne",
+	"gt":  "$gt",
+	"gte": "$gte",
+	"lt":  "$lt",
+	"lte": "$lte",
+	"in":  "$in",
+}
+
+// reserved query params which are not filters
+var reserved = map[string]bool{
+	"limit":  true,
+	"cursor": true,
+	"sort":   true,
+}
+
+var filterKey = regexp.MustCompile(`^([a-z_]+)(?:\[([a-z]+)\])?$`)
+
+// Filter represents a condition on a field, Value is a slice for the in operator
+type Filter struct {
+	Field string
+	Op    string
+	Value interface{}
+}
+
+// Sort represents the order of a field
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// DefaultSort orders lists by creation
+var DefaultSort = []Sort{
+	{Field: "created_at"},
+	{Field: "_id"},
+}
+
+// Cursor represents the values of the sort fields of the last item of a page, nil when missing or null
+type Cursor struct {
+	Sort   string
+	Values []interface{}
+}
+
+// Params represents the params of a list query, Sort always ends with _id so the order is stable
+type Params struct {
+	Limit   int
+	Cursor  *Cursor
+	Filters []Filter
+	Sort    []Sort
+}
+
+// Page represents the pagination of a list
+type Page struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// ParseParams parses the limit, cursor, sort and filter query params of the given fields
+func ParseParams(values url.Values, fields Fields) (Params, error) {
+
+	params := Params{
+		Limit: DefaultLimit,
+		Sort:  DefaultSort,
+	}
+
+	limit, err := ParseLimit(values.Get("limit"))
+	if err != nil {
+		return params, err
+	}
+	params.Limit = limit
+
+	if v := values.Get("sort"); len(v) > 0 {
+		order, err := parseSort(v, fields)
+		if err != nil {
+			return params, err
+		}
+		params.Sort = order
+	}
+
+	if v := values.Get("cursor"); len(v) > 0 {
+		cursor, err := decodeCursor(v, params.Sort, fields)
+		if err != nil {
+			return params, err
+		}
+		params.Cursor = cursor
+	}
+
+	// Sort the keys so filters are always in the same order
+	keys := []string{}
+	for key := range values {
+		if !reserved[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, v := range values[key] {
+			filter, err := parseFilter(key, v, fields)
+			if err != nil {
+				return params, err
+			}
+			params.Filters = append(params.Filters, filter)
+		}
+	}
+
+	return params, nil
+}
+
+// ParseLimit parses the limit query param, an empty limit returns DefaultLimit
+func ParseLimit(v string) (int, error) {
+
+	if len(v) <= 0 {
+		return DefaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit <= 0 || limit > MaxLimit {
+		return 0, fmt.Errorf("limit must be a number between 1 and %d", MaxLimit)
+	}
+
+	return limit, nil
+}
+
+func parseSort(v string, fields Fields) ([]Sort, error) {
+
+	order := []Sort{}
+	seen := map[string]bool{}
+
+	for _, f := range strings.Split(v, ",") {
+		s := Sort{Field: strings.TrimPrefix(f, "-"), Desc: strings.HasPrefix(f, "-")}
+
+		if _, ok := fields[s.Field]; !ok {
+			return nil, fmt.Errorf("unknown sort field %s", s.Field)
+		}
+		if seen[s.Field] {
+			return nil, fmt.Errorf("duplicate sort field %s", s.Field)
+		}
+
+		seen[s.Field] = true
+		order = append(order, s)
+	}
+
+	return append(order, Sort{Field: "_id"}), nil
+}
+
+func parseFilter(key, v string, fields Fields) (Filter, error) {
+
+	m := filterKey.FindStringSubmatch(key)
+	if m == nil {
+		return Filter{}, fmt.Errorf("unknown filter %s", key)
+	}
+
+	field, op := m[1], m[2]
+	if len(op) <= 0 {
+		op = "eq"
+	}
+
+	typ, ok := fields[field]
+	if !ok {
+		return Filter{}, fmt.Errorf("unknown filter field %s", field)
+	}
+	if _, ok := operators[op]; !ok {
+		return Filter{}, fmt.Errorf("unknown filter operator %s", op)
+	}
+
+	if op == "in" {
+		items := []interface{}{}
+		for _, s := range strings.Split(v, ",") {
+			value, err := parseValue(s, typ)
+			if err != nil {
+				return Filter{}, fmt.Errorf("invalid value of filter %s", key)
+			}
+			items = append(items, value)
+		}
+		return Filter{Field: field, Op: op, Value: items}, nil
+	}
+
+	value, err := parseValue(v, typ)
+	if err != nil {
+		return Filter{}, fmt.Errorf("invalid value of filter %s", key)
+	}
+
+	return Filter{Field: field, Op: op, Value: value}, nil
+}
+
+func parseValue(v string, typ FieldType) (interface{}, error) {
+
+	switch typ {
+	case Time:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t.UTC(), nil
+		}
+		return time.Parse("2006-01-02", v)
+	case ID:
+		return primitive.ObjectIDFromHex(v)
+	}
+
+	return v, nil
+}
+
+func formatValue(v interface{}) interface{} {
+
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC().Format(time.RFC3339Nano)
+	case primitive.ObjectID:
+		return t.Hex()
+	}
+
+	return v
+}
+
+// sortKey returns the canonical form of a sort, used to tie a cursor to its sort
+func sortKey(order []Sort) string {
+
+	keys := []string{}
+	for _, s := range order {
+		if s.Desc {
+			keys = append(keys, "-"+s.Field)
+		} else {
+			keys = append(keys, s.Field)
+		}
+	}
+
+	return strings.Join(keys, ",")
+}
+
+// NextCursor encodes the cursor after an item given the values of its fields
+func (p Params) NextCursor(values map[string]interface{}) string {
+
+	raw := struct {
+		Sort   string        `json:"s"`
+		Values []interface{} `json:"v"`
+	}{
+		Sort: sortKey(p.Sort),
+	}
+
+	for _, s := range p.Sort {
+		raw.Values = append(raw.Values, formatValue(values[s.Field]))
+	}
+
+	b, _ := json.Marshal(raw)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string, order []Sort, fields Fields) (*Cursor, error) {
+
+	invalid := errors.New("invalid cursor")
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalid
+	}
+
+	var raw struct {
+		Sort   string    `json:"s"`
+		Values []*string `json:"v"`
+	}
+
+	if err = json.Unmarshal(b, &raw); err != nil {
+		return nil, invalid
+	}
+
+	if raw.Sort != sortKey(order) || len(raw.Values) != len(order) {
+		return nil, errors.New("cursor does not match the sort")
+	}
+
+	cursor := &Cursor{Sort: raw.Sort}
+
+	for i, s := range order {
+		typ := ID
+		if s.Field != "_id" {
+			typ = fields[s.Field]
+		}
+
+		// A missing or null field sorts before any value
+		if raw.Values[i] == nil {
+			cursor.Values = append(cursor.Values, nil)
+			continue
+		}
+
+		value, err := parseValue(*raw.Values[i], typ)
+		if err != nil {
+			return nil, invalid
+		}
+
+		cursor.Values = append(cursor.Values, value)
+	}
+
+	return cursor, nil
+}
+
+// HasFilters returns whether the list is filtered
+func (p Params) HasFilters() bool {
+	return len(p.Filters) > 0
+}
+
+// MongoFilter returns the mongodb filter matching the filters and the items after the cursor
+func (p Params) MongoFilter() bson.M {
+
+	and := bson.A{}
+
+	for _, f := range p.Filters {
+		and = append(and, bson.M{f.Field: bson.M{operators[f.Op]: f.Value}})
+	}
+
+	// Items after the cursor are the ones greater on the first sort field,
+	// or equal on it and greater on the next one, and so on.
+	// Missing and null fields sort before any value and only match null
+	if p.Cursor != nil {
+		or := bson.A{}
+
+		for i, s := range p.Sort {
+			cond := bson.M{}
+			for j := 0; j < i; j++ {
+				cond[p.Sort[j].Field] = p.Cursor.Values[j]
+			}
+
+			v := p.Cursor.Values[i]
+			switch {
+			case v == nil && s.Desc:
+				// Nothing sorts after null in descending order
+				continue
+			case v == nil:
+				cond[s.Field] = bson.M{"$ne": nil}
+			case s.Desc:
+				cond["$or"] = bson.A{bson.M{s.Field: bson.M{"$lt": v}}, bson.M{s.Field: nil}}
+			default:
+				cond[s.Field] = bson.M{"$gt": v}
+			}
+
+			or = append(or, cond)
+		}
+
+		and = append(and, bson.M{"$or": or})
+	}
+
+	if len(and) == 0 {
+		return bson.M{}
+	}
+
+	return bson.M{"$and": and}
+}
+
+// MongoSort returns the mongodb sort of a list
+func (p Params) MongoSort() bson.D {
+
+	res := bson.D{}
+
+	for _, s := range p.Sort {
+		order := 1
+		if s.Desc {
+			order = -1
+		}
+		res = append(res, bson.E{Key: s.Field, Value: order})
+	}
+
+	return res
+}
+
+// Match returns whether the values of an item match the filters and come after the cursor
+func (p Params) Match(values map[string]interface{}) bool {
+
+	for _, f := range p.Filters {
+		if !matchFilter(f, values[f.Field]) {
+			return false
+		}
+	}
+
+	if p.Cursor != nil {
+		cursor := map[string]interface{}{}
+		for i, s := range p.Sort {
+			cursor[s.Field] = p.Cursor.Values[i]
+		}
+
+		return p.Less(cursor, values)
+	}
+
+	return true
+}
+
+// Less returns whether an item comes before another in the sort
+func (p Params) Less(a, b map[string]interface{}) bool {
+
+	for _, s := range p.Sort {
+		c := compare(a[s.Field], b[s.Field])
+		if s.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+	}
+
+	return false
+}
+
+func matchFilter(f Filter, v interface{}) bool {
+
+	if f.Op == "in" {
+		for _, item := range f.Value.([]interface{}) {
+			if compare(v, item) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	c := compare(v, f.Value)
+
+	switch f.Op {
+	case "ne":
+		return c != 0
+	case "gt":
+		return c > 0
+	case "gte":
+		return c >= 0
+	case "lt":
+		return c < 0
+	case "lte":
+		return c <= 0
+	}
+
+	return c == 0
+}
+
+// compare returns -1, 0 or 1 when a is less, equal or greater than b, nil is less than any value
+func compare(a, b interface{}) int {
+
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y)
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			switch {
+			case x.Before(y):
+				return -1
+			case x.After(y):
+				return 1
+			}
+			return 0
+		}
+	case primitive.ObjectID:
+		if y, ok := b.(primitive.ObjectID); ok {
+			return strings.Compare(x.Hex(), y.Hex())
+		}
+	}
+
+	return -1
+}
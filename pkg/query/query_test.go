@@ -0,0 +1,63 @@
+package query
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var fields = Fields{"name": String, "description": String}
+
+func TestCursorMissingField(t *testing.T) {
+
+	id := primitive.NewObjectID()
+
+	params, err := ParseParams(url.Values{"sort": {"description"}}, fields)
+	require.NoError(t, err)
+
+	// A missing field is read as null from the stored document
+	doc, err := bson.Marshal(bson.M{"_id": id, "name": "Milan"})
+	require.NoError(t, err)
+
+	values := params.mongoValues(doc)
+	assert.Nil(t, values["description"])
+	assert.Equal(t, id, values["_id"])
+
+	params, err = ParseParams(url.Values{"sort": {"description"}, "cursor": {params.NextCursor(values)}}, fields)
+	require.NoError(t, err)
+
+	assert.Equal(t, []interface{}{nil, id}, params.Cursor.Values)
+
+	// The next items have a description or a greater id without one
+	assert.Equal(t, bson.M{"$and": bson.A{bson.M{"$or": bson.A{
+		bson.M{"description": bson.M{"$ne": nil}},
+		bson.M{"description": nil, "_id": bson.M{"$gt": id}},
+	}}}}, params.MongoFilter())
+
+	// In descending order nothing sorts after a missing description but a greater id
+	params, err = ParseParams(url.Values{"sort": {"-description"}}, fields)
+	require.NoError(t, err)
+	params, err = ParseParams(url.Values{"sort": {"-description"}, "cursor": {params.NextCursor(values)}}, fields)
+	require.NoError(t, err)
+
+	assert.Equal(t, bson.M{"$and": bson.A{bson.M{"$or": bson.A{
+		bson.M{"description": nil, "_id": bson.M{"$gt": id}},
+	}}}}, params.MongoFilter())
+
+	// After a description come the smaller ones and the missing ones
+	values["description"] = "b"
+	params, err = ParseParams(url.Values{"sort": {"-description"}, "cursor": {params.NextCursor(values)}}, fields)
+	require.NoError(t, err)
+
+	assert.Equal(t, bson.M{"$and": bson.A{bson.M{"$or": bson.A{
+		bson.M{"$or": bson.A{bson.M{"description": bson.M{"$lt": "b"}}, bson.M{"description": nil}}},
+		bson.M{"description": "b", "_id": bson.M{"$gt": id}},
+	}}}}, params.MongoFilter())
+
+	// In memory a missing field sorts first too
+	assert.True(t, params.Less(map[string]interface{}{"description": "a", "_id": id}, map[string]interface{}{"description": nil, "_id": id}))
+}
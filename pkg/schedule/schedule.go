@@ -0,0 +1,156 @@
+// Package schedule draws round-robin fixtures and finds the dates of their rounds
+package schedule
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// dateLayout is the layout of a calendar date
+const dateLayout = "2006-01-02"
+
+// Fixture represents a match to schedule between two teams
+type Fixture struct {
+	Round      int
+	HomeTeamID primitive.ObjectID
+	AwayTeamID primitive.ObjectID
+}
+
+// Options represents the rules of the calendar of a season
+type Options struct {
+	// Start is the kickoff of the first round, every round kicks off at the same time of day
+	Start time.Time
+	// Interval is the number of days between two rounds
+	Interval int
+	// RestDays is the minimum number of days without a match between two matches of a team
+	RestDays int
+	// Blackouts are the dates without any match
+	Blackouts []time.Time
+	// Busy are the kickoffs of the matches the teams already play, in other competitions for instance
+	Busy map[primitive.ObjectID][]time.Time
+}
+
+// RoundRobin returns the rounds of a single or double round-robin between teams using the circle method
+func RoundRobin(teamIDs []primitive.ObjectID, double bool) [][]Fixture {
+
+	// With an odd number of teams, the team drawn against the bye rests.
+	// The bye is the fixed team so the sides of the others stay balanced
+	circle := append([]primitive.ObjectID{}, teamIDs...)
+	if len(circle)%2 == 1 {
+		circle = append([]primitive.ObjectID{primitive.NilObjectID}, circle...)
+	}
+
+	n := len(circle)
+	rounds := [][]Fixture{}
+
+	for r := 0; r < n-1; r++ {
+		round := []Fixture{}
+
+		for i := 0; i < n/2; i++ {
+			home, away := circle[i], circle[n-1-i]
+
+			// The fixed team alternates every round, the other pairs every other position
+			if (i == 0 && r%2 == 1) || (i > 0 && i%2 == 1) {
+				home, away = away, home
+			}
+
+			if home.IsZero() || away.IsZero() {
+				continue
+			}
+			round = append(round, Fixture{Round: r + 1, HomeTeamID: home, AwayTeamID: away})
+		}
+
+		rounds = append(rounds, round)
+
+		// Rotate every team but the first one
+		last := circle[n-1]
+		copy(circle[2:], circle[1:n-1])
+		circle[1] = last
+	}
+
+	if !double {
+		return rounds
+	}
+
+	// The second half mirrors the first one
+	first := len(rounds)
+	for r := 0; r < first; r++ {
+		round := []Fixture{}
+		for _, f := range rounds[r] {
+			round = append(round, Fixture{Round: first + r + 1, HomeTeamID: f.AwayTeamID, AwayTeamID: f.HomeTeamID})
+		}
+		rounds = append(rounds, round)
+	}
+
+	return rounds
+}
+
+// Dates returns the kickoff of each round, postponing a round on a blackout date or without rest days
+func Dates(rounds [][]Fixture, opts Options) []time.Time {
+
+	blackouts := map[string]bool{}
+	for _, d := range opts.Blackouts {
+		blackouts[d.UTC().Format(dateLayout)] = true
+	}
+
+	busy := map[primitive.ObjectID][]time.Time{}
+	for id, kickoffs := range opts.Busy {
+		busy[id] = append([]time.Time{}, kickoffs...)
+	}
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+
+	res := []time.Time{}
+	kickoff := opts.Start
+
+	for _, round := range rounds {
+		for blackouts[kickoff.UTC().Format(dateLayout)] || !rested(round, busy, kickoff, opts.RestDays) {
+			kickoff = kickoff.AddDate(0, 0, 1)
+		}
+
+		res = append(res, kickoff)
+
+		for _, f := range round {
+			busy[f.HomeTeamID] = append(busy[f.HomeTeamID], kickoff)
+			busy[f.AwayTeamID] = append(busy[f.AwayTeamID], kickoff)
+		}
+
+		kickoff = kickoff.AddDate(0, 0, interval)
+	}
+
+	return res
+}
+
+// rested returns whether the teams of a round have no other match within the rest days of a kickoff
+func rested(round []Fixture, busy map[primitive.ObjectID][]time.Time, kickoff time.Time, restDays int) bool {
+
+	for _, f := range round {
+		for _, id := range []primitive.ObjectID{f.HomeTeamID, f.AwayTeamID} {
+			for _, t := range busy[id] {
+				if days(t, kickoff) <= restDays {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// days returns the number of calendar days between two times, whatever their order
+func days(a, b time.Time) int {
+
+	da, _ := time.Parse(dateLayout, a.UTC().Format(dateLayout))
+	db, _ := time.Parse(dateLayout, b.UTC().Format(dateLayout))
+
+	d := int(da.Sub(db).Hours() / 24)
+	if d < 0 {
+		return -d
+	}
+
+	return d
+}
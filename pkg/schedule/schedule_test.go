@@ -0,0 +1,122 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func teams(n int) []primitive.ObjectID {
+
+	res := []primitive.ObjectID{}
+	for i := 0; i < n; i++ {
+		res = append(res, primitive.NewObjectID())
+	}
+
+	return res
+}
+
+func TestRoundRobin(t *testing.T) {
+
+	for _, n := range []int{2, 5, 6, 20} {
+		ids := teams(n)
+
+		for _, double := range []bool{false, true} {
+			rounds := RoundRobin(ids, double)
+
+			legs := 1
+			if double {
+				legs = 2
+			}
+
+			// An odd number of teams needs one more round, with a team resting in each
+			expected := n - 1
+			if n%2 == 1 {
+				expected = n
+			}
+			assert.Len(t, rounds, expected*legs)
+
+			pairs := map[[2]primitive.ObjectID]int{}
+			homes := map[primitive.ObjectID]int{}
+			breaks := 0
+			last := map[primitive.ObjectID]bool{}
+
+			for r, round := range rounds {
+				played := map[primitive.ObjectID]bool{}
+
+				for _, f := range round {
+					assert.Equal(t, r+1, f.Round)
+					assert.False(t, played[f.HomeTeamID] || played[f.AwayTeamID], "a team plays once a round")
+					played[f.HomeTeamID], played[f.AwayTeamID] = true, true
+
+					pairs[[2]primitive.ObjectID{f.HomeTeamID, f.AwayTeamID}]++
+					homes[f.HomeTeamID]++
+
+					if home, ok := last[f.HomeTeamID]; ok && home {
+						breaks++
+					}
+					if home, ok := last[f.AwayTeamID]; ok && !home {
+						breaks++
+					}
+					last[f.HomeTeamID], last[f.AwayTeamID] = true, false
+				}
+			}
+
+			// Every team meets every other team once per leg, on both sides in a double round-robin
+			assert.Len(t, pairs, n*(n-1)/2*legs)
+			for _, count := range pairs {
+				assert.Equal(t, 1, count)
+			}
+
+			// The home matches are balanced
+			for _, id := range ids {
+				assert.InDelta(t, float64((n-1)*legs)/2, homes[id], 0.5)
+			}
+
+			// The circle method gives the minimum number of breaks of a single round-robin
+			if !double && n%2 == 0 {
+				assert.Equal(t, n-2, breaks)
+			}
+		}
+	}
+}
+
+func TestDates(t *testing.T) {
+
+	ids := teams(4)
+	rounds := RoundRobin(ids, false)
+	start := time.Date(2020, 12, 19, 15, 0, 0, 0, time.UTC)
+
+	t.Run("Interval", func(t *testing.T) {
+
+		dates := Dates(rounds, Options{Start: start, Interval: 7})
+
+		assert.Equal(t, []time.Time{start, start.AddDate(0, 0, 7), start.AddDate(0, 0, 14)}, dates)
+	})
+
+	t.Run("Blackouts", func(t *testing.T) {
+
+		blackouts := []time.Time{
+			time.Date(2020, 12, 26, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, 12, 27, 0, 0, 0, 0, time.UTC),
+		}
+
+		dates := Dates(rounds, Options{Start: start, Interval: 7, Blackouts: blackouts})
+
+		assert.Equal(t, []time.Time{start, start.AddDate(0, 0, 9), start.AddDate(0, 0, 16)}, dates)
+	})
+
+	t.Run("Rest Days", func(t *testing.T) {
+
+		// The first team plays a cup match four days after the start
+		busy := map[primitive.ObjectID][]time.Time{
+			ids[0]: {start.AddDate(0, 0, 4)},
+		}
+
+		dates := Dates(rounds, Options{Start: start, Interval: 2, RestDays: 2, Busy: busy})
+
+		assert.Equal(t, []time.Time{start, start.AddDate(0, 0, 7), start.AddDate(0, 0, 10)}, dates)
+	})
+}
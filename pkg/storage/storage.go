@@ -0,0 +1,59 @@
+// Package storage provides the helpers shared by the in-memory and boltdb repositories
+package storage
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Now returns the current time with the precision stored by mongodb
+func Now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// Text represents a searchable field and its weight
+type Text struct {
+	Value  string
+	Weight float64
+}
+
+// TextScore returns the relevance of a text search on weighted fields like a mongodb text index
+func TextScore(q string, fields ...Text) float64 {
+
+	terms := words(q)
+	score := 0.0
+
+	for _, f := range fields {
+		matched := 0.0
+		for _, w := range words(f.Value) {
+			for _, t := range terms {
+				if w == t {
+					matched++
+					break
+				}
+			}
+		}
+		score += f.Weight * matched
+	}
+
+	return score
+}
+
+// words splits a text into lowercase words without diacritics
+func words(s string) []string {
+
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+
+	return strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
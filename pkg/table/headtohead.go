@@ -0,0 +1,143 @@
+package table
+
+import (
+	"sort"
+
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FormLength is the number of results of the form of a head-to-head
+const FormLength = 5
+
+// Meetings returns the finished matches between the teams of a group from the oldest
+func Meetings(group []primitive.ObjectID, matches []model.Match) []model.Match {
+
+	in := map[primitive.ObjectID]bool{}
+	for _, id := range group {
+		in[id] = true
+	}
+
+	res := []model.Match{}
+	for _, m := range matches {
+		if m.Status == model.MatchStatusFinished && m.HomeTeamID != m.AwayTeamID && in[m.HomeTeamID] && in[m.AwayTeamID] {
+			res = append(res, m)
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Kickoff.Before(res[j].Kickoff)
+	})
+
+	return res
+}
+
+// Result returns a finished match from the side of a team, a tie decided by penalties is a draw
+func Result(m model.Match, teamID primitive.ObjectID) model.TeamResult {
+
+	res := model.TeamResult{
+		MatchID:      m.ID,
+		OpponentID:   m.AwayTeamID,
+		Home:         m.HomeTeamID == teamID,
+		Kickoff:      m.Kickoff,
+		GoalsFor:     m.Score.Home,
+		GoalsAgainst: m.Score.Away,
+	}
+
+	if !res.Home {
+		res.OpponentID = m.HomeTeamID
+		res.GoalsFor, res.GoalsAgainst = m.Score.Away, m.Score.Home
+	}
+
+	switch {
+	case res.GoalsFor > res.GoalsAgainst:
+		res.Result = model.ResultWin
+	case res.GoalsFor < res.GoalsAgainst:
+		res.Result = model.ResultLoss
+	default:
+		res.Result = model.ResultDraw
+	}
+
+	return res
+}
+
+// Bigger returns whether a is a bigger win or loss than b by margin then goals of the winner, ties go to a
+func Bigger(a model.TeamResult, b *model.TeamResult) bool {
+
+	if b == nil {
+		return true
+	}
+
+	margin := func(r model.TeamResult) int {
+		if r.GoalsFor > r.GoalsAgainst {
+			return r.GoalsFor - r.GoalsAgainst
+		}
+		return r.GoalsAgainst - r.GoalsFor
+	}
+	winner := func(r model.TeamResult) int {
+		if r.GoalsFor > r.GoalsAgainst {
+			return r.GoalsFor
+		}
+		return r.GoalsAgainst
+	}
+
+	if margin(a) != margin(*b) {
+		return margin(a) > margin(*b)
+	}
+
+	return winner(a) >= winner(*b)
+}
+
+// HeadToHead returns the head-to-head of a team against another team from their finished meetings
+func HeadToHead(teamID, otherID primitive.ObjectID, matches []model.Match) model.HeadToHead {
+
+	group := []primitive.ObjectID{teamID, otherID}
+	meetings := Meetings(group, matches)
+	t := build(group, meetings, DefaultPoints)
+
+	res := model.HeadToHead{
+		Played:   len(meetings),
+		Meetings: []model.TeamResult{},
+	}
+
+	side := func(id primitive.ObjectID) model.HeadToHeadTeam {
+		row := t.rows[id]
+		return model.HeadToHeadTeam{
+			TeamID:       id,
+			Won:          row.Won,
+			Drawn:        row.Drawn,
+			Lost:         row.Lost,
+			GoalsFor:     row.GoalsFor,
+			GoalsAgainst: row.GoalsAgainst,
+		}
+	}
+	res.Team = side(teamID)
+	res.Other = side(otherID)
+
+	for _, m := range meetings {
+		r := Result(m, teamID)
+		res.Meetings = append(res.Meetings, r)
+
+		switch r.Result {
+		case model.ResultWin:
+			if Bigger(r, res.Team.BiggestWin) {
+				win := r
+				res.Team.BiggestWin = &win
+			}
+		case model.ResultLoss:
+			if other := Result(m, otherID); Bigger(other, res.Other.BiggestWin) {
+				res.Other.BiggestWin = &other
+			}
+		}
+	}
+
+	last := res.Meetings
+	if len(last) > FormLength {
+		last = last[len(last)-FormLength:]
+	}
+	for _, r := range last {
+		res.Form += r.Result
+	}
+
+	return res
+}
@@ -0,0 +1,68 @@
+package table
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestHeadToHead(t *testing.T) {
+
+	milan := primitive.NewObjectID()
+	inter := primitive.NewObjectID()
+	juve := primitive.NewObjectID()
+
+	matches := []model.Match{}
+	meet := func(home, away primitive.ObjectID, homeGoals, awayGoals int) model.Match {
+		m := result(home, away, homeGoals, awayGoals)
+		m.ID = primitive.NewObjectID()
+		m.Kickoff = time.Date(2020, 1, len(matches)+1, 15, 0, 0, 0, time.UTC)
+		matches = append(matches, m)
+		return m
+	}
+
+	first := meet(milan, inter, 3, 0)
+	meet(inter, milan, 1, 1)
+	meet(milan, juve, 5, 0)
+	rout := meet(inter, milan, 4, 1)
+	meet(milan, inter, 0, 0)
+	meet(inter, milan, 0, 1)
+	last := meet(milan, inter, 2, 1)
+
+	scheduled := meet(milan, inter, 0, 0)
+	scheduled.Status = model.MatchStatusScheduled
+	matches[len(matches)-1] = scheduled
+
+	// The matches are read by kickoff whatever their order
+	res := HeadToHead(milan, inter, append([]model.Match{last, scheduled}, matches[:6]...))
+
+	assert.Equal(t, 6, res.Played)
+	assert.Equal(t, model.HeadToHeadTeam{TeamID: milan, Won: 3, Drawn: 2, Lost: 1, GoalsFor: 8, GoalsAgainst: 6, BiggestWin: res.Team.BiggestWin}, res.Team)
+	assert.Equal(t, 1, res.Other.Won)
+	assert.Equal(t, 8, res.Other.GoalsAgainst)
+
+	if assert.NotNil(t, res.Team.BiggestWin) {
+		assert.Equal(t, first.ID, res.Team.BiggestWin.MatchID)
+	}
+	if assert.NotNil(t, res.Other.BiggestWin) {
+		assert.Equal(t, rout.ID, res.Other.BiggestWin.MatchID)
+		assert.Equal(t, milan, res.Other.BiggestWin.OpponentID)
+		assert.Equal(t, 4, res.Other.BiggestWin.GoalsFor)
+	}
+
+	// The form is the last five meetings from the side of the first team
+	assert.Equal(t, "DLDWW", res.Form)
+	if assert.Len(t, res.Meetings, 6) {
+		assert.Equal(t, first.ID, res.Meetings[0].MatchID)
+		assert.Equal(t, last.ID, res.Meetings[5].MatchID)
+		assert.False(t, res.Meetings[1].Home)
+	}
+
+	none := HeadToHead(milan, juve, nil)
+	assert.Equal(t, 0, none.Played)
+	assert.Empty(t, none.Meetings)
+	assert.Equal(t, "", none.Form)
+}
@@ -0,0 +1,202 @@
+// Package table computes league tables from the results of matches
+package table
+
+import (
+	"sort"
+
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// criterionPoints ranks teams by points, it always comes before the tiebreakers
+const criterionPoints = "points"
+
+// DefaultPoints are the points of a win, a draw and a loss when a competition does not set them
+var DefaultPoints = model.Points{Win: 3, Draw: 1, Loss: 0}
+
+// DefaultTiebreakers are the tiebreakers used when a competition does not set them
+var DefaultTiebreakers = []string{
+	model.TiebreakerGoalDifference,
+	model.TiebreakerGoalsScored,
+	model.TiebreakerHeadToHead,
+	model.TiebreakerFairPlay,
+}
+
+// table represents the rows of the teams and the matches they were computed from
+type table struct {
+	teamIDs []primitive.ObjectID
+	rows    map[primitive.ObjectID]*model.Standing
+	matches []model.Match
+	points  model.Points
+}
+
+// Compute returns the table of teams ranked by points then by the tiebreakers from the finished matches
+func Compute(teamIDs []primitive.ObjectID, matches []model.Match, points model.Points, tiebreakers []string) []model.Standing {
+
+	t := build(teamIDs, matches, points)
+
+	ranked := t.rank(teamIDs, append([]string{criterionPoints}, tiebreakers...))
+
+	res := []model.Standing{}
+	for i, id := range ranked {
+		row := *t.rows[id]
+		row.Position = i + 1
+		res = append(res, row)
+	}
+
+	return res
+}
+
+// build returns the rows of teams from the finished matches between them
+func build(teamIDs []primitive.ObjectID, matches []model.Match, points model.Points) *table {
+
+	t := &table{
+		teamIDs: teamIDs,
+		rows:    map[primitive.ObjectID]*model.Standing{},
+		points:  points,
+	}
+
+	for _, id := range teamIDs {
+		t.rows[id] = &model.Standing{TeamID: id}
+	}
+
+	for _, m := range matches {
+		home, away := t.rows[m.HomeTeamID], t.rows[m.AwayTeamID]
+		if m.Status != model.MatchStatusFinished || home == nil || away == nil {
+			continue
+		}
+
+		t.matches = append(t.matches, m)
+		t.result(home, m.Score.Home, m.Score.Away)
+		t.result(away, m.Score.Away, m.Score.Home)
+
+		for _, e := range m.Events {
+			if e.TeamID == nil || t.rows[*e.TeamID] == nil {
+				continue
+			}
+			switch e.Type {
+			case model.EventYellowCard:
+				t.rows[*e.TeamID].FairPlay--
+			case model.EventRedCard:
+				t.rows[*e.TeamID].FairPlay -= 3
+			}
+		}
+	}
+
+	return t
+}
+
+// result adds the result of a match to the row of a team
+func (t *table) result(row *model.Standing, scored, conceded int) {
+
+	row.Played++
+	row.GoalsFor += scored
+	row.GoalsAgainst += conceded
+	row.GoalDifference = row.GoalsFor - row.GoalsAgainst
+
+	switch {
+	case scored > conceded:
+		row.Won++
+		row.Points += t.points.Win
+	case scored == conceded:
+		row.Drawn++
+		row.Points += t.points.Draw
+	default:
+		row.Lost++
+		row.Points += t.points.Loss
+	}
+}
+
+// rank orders a group of teams by the first criterion then each subgroup still tied by the next ones
+func (t *table) rank(group []primitive.ObjectID, criteria []string) []primitive.ObjectID {
+
+	if len(group) <= 1 || len(criteria) == 0 {
+		return t.seeded(group)
+	}
+
+	values := t.values(criteria[0], group)
+
+	sorted := append([]primitive.ObjectID{}, group...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compare(values[sorted[i]], values[sorted[j]]) > 0
+	})
+
+	res := []primitive.ObjectID{}
+
+	for i := 0; i < len(sorted); {
+		j := i + 1
+		for j < len(sorted) && compare(values[sorted[i]], values[sorted[j]]) == 0 {
+			j++
+		}
+
+		res = append(res, t.rank(sorted[i:j], criteria[1:])...)
+		i = j
+	}
+
+	return res
+}
+
+// values returns the values of a criterion for a group of teams, the higher the better
+func (t *table) values(criterion string, group []primitive.ObjectID) map[primitive.ObjectID][]int {
+
+	res := map[primitive.ObjectID][]int{}
+
+	if criterion == model.TiebreakerHeadToHead {
+		// The table of the meetings of the tied teams only
+		mini := build(group, Meetings(group, t.matches), t.points)
+		for _, id := range group {
+			row := mini.rows[id]
+			res[id] = []int{row.Points, row.GoalDifference, row.GoalsFor}
+		}
+		return res
+	}
+
+	for _, id := range group {
+		row := t.rows[id]
+		switch criterion {
+		case criterionPoints:
+			res[id] = []int{row.Points}
+		case model.TiebreakerGoalDifference:
+			res[id] = []int{row.GoalDifference}
+		case model.TiebreakerGoalsScored:
+			res[id] = []int{row.GoalsFor}
+		case model.TiebreakerFairPlay:
+			res[id] = []int{row.FairPlay}
+		default:
+			res[id] = []int{}
+		}
+	}
+
+	return res
+}
+
+// seeded returns a group of teams in the order of the teams of the table
+func (t *table) seeded(group []primitive.ObjectID) []primitive.ObjectID {
+
+	index := map[primitive.ObjectID]int{}
+	for i, id := range t.teamIDs {
+		index[id] = i
+	}
+
+	res := append([]primitive.ObjectID{}, group...)
+	sort.SliceStable(res, func(i, j int) bool {
+		return index[res[i]] < index[res[j]]
+	})
+
+	return res
+}
+
+// compare compares values lexicographically
+func compare(a, b []int) int {
+
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch {
+		case a[i] > b[i]:
+			return 1
+		case a[i] < b[i]:
+			return -1
+		}
+	}
+
+	return len(a) - len(b)
+}
@@ -0,0 +1,109 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yezarela/go-soccer/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func result(home, away primitive.ObjectID, homeGoals, awayGoals int) model.Match {
+	return model.Match{
+		HomeTeamID: home,
+		AwayTeamID: away,
+		Status:     model.MatchStatusFinished,
+		Score:      model.Score{Home: homeGoals, Away: awayGoals},
+	}
+}
+
+func order(standings []model.Standing) []primitive.ObjectID {
+
+	res := []primitive.ObjectID{}
+	for _, s := range standings {
+		res = append(res, s.TeamID)
+	}
+
+	return res
+}
+
+func TestCompute(t *testing.T) {
+
+	a, b, c, d := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	t.Run("Points", func(t *testing.T) {
+
+		matches := []model.Match{
+			result(a, b, 2, 0),
+			result(b, c, 1, 1),
+			result(c, a, 0, 3),
+		}
+
+		res := Compute([]primitive.ObjectID{a, b, c}, matches, DefaultPoints, DefaultTiebreakers)
+
+		if assert.Len(t, res, 3) {
+			assert.Equal(t, model.Standing{Position: 1, TeamID: a, Played: 2, Won: 2, GoalsFor: 5, GoalDifference: 5, Points: 6}, res[0])
+			assert.Equal(t, model.Standing{Position: 2, TeamID: b, Played: 2, Drawn: 1, Lost: 1, GoalsFor: 1, GoalsAgainst: 3, GoalDifference: -2, Points: 1}, res[1])
+			assert.Equal(t, 3, res[2].Position)
+			assert.Equal(t, -3, res[2].GoalDifference)
+		}
+	})
+
+	t.Run("Ignored Matches", func(t *testing.T) {
+
+		scheduled := result(a, b, 0, 0)
+		scheduled.Status = model.MatchStatusScheduled
+
+		matches := []model.Match{
+			scheduled,
+			result(a, d, 5, 0),
+		}
+
+		res := Compute([]primitive.ObjectID{b, a}, matches, DefaultPoints, DefaultTiebreakers)
+
+		// Without any counted match the teams keep their order
+		assert.Equal(t, []primitive.ObjectID{b, a}, order(res))
+		assert.Equal(t, 0, res[1].Played)
+	})
+
+	t.Run("Tiebreakers", func(t *testing.T) {
+
+		// a and b have the same points, b has the better goal difference but a won their match
+		matches := []model.Match{
+			result(a, b, 1, 0),
+			result(a, c, 0, 0),
+			result(b, c, 0, 0),
+			result(b, d, 5, 0),
+		}
+
+		res := Compute([]primitive.ObjectID{d, c, b, a}, matches, DefaultPoints, DefaultTiebreakers)
+		assert.Equal(t, []primitive.ObjectID{b, a, c, d}, order(res))
+
+		res = Compute([]primitive.ObjectID{d, c, b, a}, matches, DefaultPoints, []string{model.TiebreakerHeadToHead})
+		assert.Equal(t, []primitive.ObjectID{a, b, c, d}, order(res))
+	})
+
+	t.Run("Fair Play", func(t *testing.T) {
+
+		draw := result(a, b, 0, 0)
+		draw.Events = []model.MatchEvent{
+			{Type: model.EventYellowCard, TeamID: &a},
+			{Type: model.EventYellowCard, TeamID: &b},
+			{Type: model.EventRedCard, TeamID: &a},
+		}
+
+		res := Compute([]primitive.ObjectID{a, b}, []model.Match{draw}, DefaultPoints, DefaultTiebreakers)
+
+		assert.Equal(t, []primitive.ObjectID{b, a}, order(res))
+		assert.Equal(t, -1, res[0].FairPlay)
+		assert.Equal(t, -4, res[1].FairPlay)
+	})
+
+	t.Run("Custom Points", func(t *testing.T) {
+
+		res := Compute([]primitive.ObjectID{a, b}, []model.Match{result(a, b, 0, 1)}, model.Points{Win: 2, Draw: 1, Loss: 1}, nil)
+
+		assert.Equal(t, 2, res[0].Points)
+		assert.Equal(t, 1, res[1].Points)
+	})
+}